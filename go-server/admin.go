@@ -0,0 +1,83 @@
+package main
+
+import (
+    "context"
+    "crypto/subtle"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// AbortAll cancels every running validation and kills its processes
+func (s *CCToolsServer) AbortAll(ctx context.Context, req *pb.AbortAllRequest) (*pb.AbortAllResponse, error) {
+    if err := s.requireAdmin(ctx); err != nil {
+        return nil, err
+    }
+
+    aborted := s.jobs.cancelAll(errAborted)
+    logCtx(ctx, "AbortAll: aborted running validations", "count", aborted, "reason", req.Reason)
+
+    return &pb.AbortAllResponse{Aborted: int32(aborted)}, nil
+}
+
+// CancelValidationsByProject cancels every running validation of one
+// project and kills its processes, leaving other projects running
+func (s *CCToolsServer) CancelValidationsByProject(ctx context.Context, req *pb.CancelValidationsRequest) (*pb.CancelValidationsResponse, error) {
+    if err := s.requireAdmin(ctx); err != nil {
+        return nil, err
+    }
+    if req.ProjectRoot == "" {
+        return nil, status.Error(codes.InvalidArgument, "project_root is required")
+    }
+
+    ids := s.jobs.cancelProject(req.ProjectRoot, errAborted, req.DryRun)
+    if !req.DryRun {
+        logCtx(ctx, "CancelValidationsByProject: aborted running validations", "count", len(ids), "project_root", req.ProjectRoot, "reason", req.Reason)
+    }
+
+    return &pb.CancelValidationsResponse{JobIds: ids}, nil
+}
+
+// CancelValidation cancels one running validation by the run id returned
+// in its x-run-id header, response or stream events. Runs started by a TLS
+// client can only be cancelled by that client or an admin.
+func (s *CCToolsServer) CancelValidation(ctx context.Context, req *pb.CancelValidationRequest) (*pb.CancelValidationResponse, error) {
+    if req.RunId == "" {
+        return nil, status.Error(codes.InvalidArgument, "run_id is required")
+    }
+    job, ok := s.jobs.lookup(req.RunId)
+    if !ok {
+        return nil, status.Errorf(codes.NotFound, "no running validation with run_id %q", req.RunId)
+    }
+    if job.identity != "" && job.identity != identityFromContext(ctx) {
+        if err := s.requireAdmin(ctx); err != nil {
+            return nil, status.Error(codes.PermissionDenied, "run belongs to another client")
+        }
+    }
+
+    job.cancel(errRunCancelled)
+    logCtx(ctx, "CancelValidation: cancelled run", "run_id", job.id, "project_root", job.projectRoot, "reason", req.Reason)
+
+    return &pb.CancelValidationResponse{ProjectRoot: job.projectRoot}, nil
+}
+
+// requireAdmin checks that the caller presented the ADMIN_TOKEN as an
+// "authorization: Bearer <token>" header. Admin RPCs are disabled when no
+// token is configured.
+func (s *CCToolsServer) requireAdmin(ctx context.Context) error {
+    if s.adminToken == "" {
+        return status.Error(codes.PermissionDenied, "admin RPCs are disabled; set ADMIN_TOKEN to enable them")
+    }
+
+    md, _ := metadata.FromIncomingContext(ctx)
+    expected := []byte("Bearer " + s.adminToken)
+    for _, value := range md.Get("authorization") {
+        if subtle.ConstantTimeCompare([]byte(value), expected) == 1 {
+            return nil
+        }
+    }
+    return status.Error(codes.Unauthenticated, "missing or invalid admin token")
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+    "context"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestCancelValidationByRunID(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    started := filepath.Join(t.TempDir(), "started")
+    root := makeProject(t, map[string]string{
+        "lint": "echo running > " + started + "; sleep 30",
+        "test": "true",
+    })
+
+    type outcome struct {
+        resp   *pb.ValidationResponse
+        header metadata.MD
+        err    error
+    }
+    done := make(chan outcome, 1)
+    go func() {
+        var header metadata.MD
+        resp, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root, MaxParallelValidators: 1}, grpc.Header(&header))
+        done <- outcome{resp, header, err}
+    }()
+    waitForFile(t, started)
+
+    // The client reads the id from the x-run-id header as soon as the run
+    // starts; the unary client API only hands headers over at the end, so
+    // the test looks it up in the registry instead
+    ids := ts.parts.tools.jobs.cancelProject(root, nil, true)
+    if len(ids) != 1 {
+        t.Fatalf("runs of %s = %v, want one", root, ids)
+    }
+    cancelledAt := time.Now()
+    if _, err := ts.client.CancelValidation(ctx, &pb.CancelValidationRequest{RunId: ids[0], Reason: "test"}); err != nil {
+        t.Fatalf("CancelValidation: %v", err)
+    }
+
+    var got outcome
+    select {
+    case got = <-done:
+    case <-time.After(10 * time.Second):
+        t.Fatal("validation still running after CancelValidation")
+    }
+    if got.err != nil {
+        t.Fatalf("ValidateProject: %v", got.err)
+    }
+    if took := time.Since(cancelledAt); took > 5*time.Second {
+        t.Errorf("run ended %s after the cancel", took)
+    }
+    if got.resp.Success || got.resp.RunId != ids[0] {
+        t.Errorf("success=%v run_id=%q, want a failed run %s", got.resp.Success, got.resp.RunId, ids[0])
+    }
+    if header := got.header.Get(runIDHeader); len(header) != 1 || header[0] != ids[0] {
+        t.Errorf("%s header = %q, want %s", runIDHeader, header, ids[0])
+    }
+    if lint := resultsByName(got.resp)["lint"]; lint.GetFailureReason() != pb.FailureReason_FAILURE_REASON_CANCELLED {
+        t.Errorf("lint result = %v, want CANCELLED", lint)
+    }
+
+    // The finished run left the registry
+    if _, err := ts.client.CancelValidation(ctx, &pb.CancelValidationRequest{RunId: ids[0]}); status.Code(err) != codes.NotFound {
+        t.Errorf("cancelling a finished run = %v, want NotFound", err)
+    }
+}
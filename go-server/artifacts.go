@@ -0,0 +1,142 @@
+package main
+
+import (
+    "errors"
+    "io/fs"
+    "os"
+    "path"
+    "path/filepath"
+    "strings"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// errArtifactLimit stops the artifact walk once the count limit is reached
+var errArtifactLimit = errors.New("artifact limit reached")
+
+// artifactSkipDirs are never searched for artifacts
+var artifactSkipDirs = map[string]bool{
+    ".git":         true,
+    "node_modules": true,
+}
+
+// artifactLimits bounds what a single validator can return
+type artifactLimits struct {
+    maxFileBytes  int64 // larger files are listed without content
+    maxTotalBytes int64 // content budget across one validator's artifacts
+    maxFiles      int   // artifacts listed per validator
+}
+
+func loadArtifactLimits() artifactLimits {
+    return artifactLimits{
+        maxFileBytes:  int64(envInt("ARTIFACT_MAX_FILE_BYTES", 1<<20)),
+        maxTotalBytes: int64(envInt("ARTIFACT_MAX_TOTAL_BYTES", 8<<20)),
+        maxFiles:      envInt("ARTIFACT_MAX_FILES", 100),
+    }
+}
+
+// validArtifactGlob rejects patterns that could reach outside the project root
+func validArtifactGlob(pattern string) bool {
+    return validRelativePath(pattern)
+}
+
+// validRelativePath reports whether p is a non-empty path that stays below
+// the directory it is resolved against
+func validRelativePath(p string) bool {
+    if p == "" || path.IsAbs(p) || filepath.IsAbs(p) {
+        return false
+    }
+    for _, segment := range strings.Split(filepath.ToSlash(p), "/") {
+        if segment == ".." {
+            return false
+        }
+    }
+    return true
+}
+
+// collectArtifacts returns files under root matching globs that were
+// written at or after since, so only this validator's output is picked up.
+// Globs are slash-separated and relative to root; "**" matches any number
+// of directories. Symlinks are not followed.
+func collectArtifacts(root string, globs []string, since time.Time, limits artifactLimits) []*pb.Artifact {
+    artifacts := make([]*pb.Artifact, 0)
+    budget := limits.maxTotalBytes
+
+    _ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+        if err != nil {
+            return nil
+        }
+        if d.IsDir() {
+            if p != root && artifactSkipDirs[d.Name()] {
+                return filepath.SkipDir
+            }
+            return nil
+        }
+        if !d.Type().IsRegular() {
+            return nil
+        }
+
+        rel, err := filepath.Rel(root, p)
+        if err != nil {
+            return nil
+        }
+        rel = filepath.ToSlash(rel)
+        if !matchAnyGlob(globs, rel) {
+            return nil
+        }
+
+        info, err := d.Info()
+        if err != nil || info.ModTime().Before(since) {
+            return nil
+        }
+
+        artifact := &pb.Artifact{Path: rel, SizeBytes: info.Size()}
+        if info.Size() <= limits.maxFileBytes && info.Size() <= budget {
+            if content, err := os.ReadFile(p); err == nil {
+                artifact.Content = content
+                budget -= int64(len(content))
+            }
+        }
+        artifact.ContentOmitted = artifact.Content == nil && info.Size() > 0
+        artifacts = append(artifacts, artifact)
+
+        if len(artifacts) >= limits.maxFiles {
+            return errArtifactLimit
+        }
+        return nil
+    })
+    return artifacts
+}
+
+func matchAnyGlob(globs []string, rel string) bool {
+    for _, glob := range globs {
+        if matchGlob(strings.Split(glob, "/"), strings.Split(rel, "/")) {
+            return true
+        }
+    }
+    return false
+}
+
+// matchGlob matches path segments against pattern segments, where a "**"
+// segment matches zero or more path segments
+func matchGlob(pattern, segments []string) bool {
+    for len(pattern) > 0 {
+        if pattern[0] == "**" {
+            for i := 0; i <= len(segments); i++ {
+                if matchGlob(pattern[1:], segments[i:]) {
+                    return true
+                }
+            }
+            return false
+        }
+        if len(segments) == 0 {
+            return false
+        }
+        if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+            return false
+        }
+        pattern, segments = pattern[1:], segments[1:]
+    }
+    return len(segments) == 0
+}
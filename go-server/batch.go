@@ -0,0 +1,143 @@
+package main
+
+import (
+    "context"
+    "runtime"
+    "sync"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// ValidateProjects validates several projects, returning partial results on
+// deadline. An entry that cannot run (bad request, failed detection, load
+// shedding) carries its own error_code and never fails the whole batch.
+func (s *CCToolsServer) ValidateProjects(ctx context.Context, req *pb.BatchValidationRequest) (*pb.BatchValidationResponse, error) {
+    limit := s.batchConcurrency(req.MaxConcurrency)
+    results, done := runBatch(ctx, len(req.Requests), limit, func(ctx context.Context, i int) *pb.ValidationResponse {
+        entry := req.Requests[i]
+        err := s.limits.checkRoot("project_root", entry.GetProjectRoot())
+        var resp *pb.ValidationResponse
+        if err == nil {
+            resp, err = s.ValidateProject(ctx, entry)
+        }
+        if err != nil {
+            st := status.Convert(err)
+            return &pb.ValidationResponse{
+                Success:      false,
+                ErrorMessage: st.Message(),
+                ErrorCode:    st.Code().String(),
+            }
+        }
+        return resp
+    })
+
+    responses := make([]*pb.ValidationResponse, len(results))
+    for i, resp := range results {
+        if !done[i] {
+            resp = &pb.ValidationResponse{
+                Success:      false,
+                ErrorMessage: "Validation did not complete before the deadline",
+                ErrorCode:    codes.DeadlineExceeded.String(),
+            }
+        }
+        responses[i] = resp
+    }
+
+    return &pb.BatchValidationResponse{Responses: responses}, nil
+}
+
+// GetProjectMetadataBatch detects metadata for several projects
+func (s *CCToolsServer) GetProjectMetadataBatch(ctx context.Context, req *pb.BatchValidationRequest) (*pb.ProjectMetadataBatchResponse, error) {
+    limit := s.batchConcurrency(req.MaxConcurrency)
+    results, done := runBatch(ctx, len(req.Requests), limit, func(ctx context.Context, i int) *pb.ProjectMetadataResult {
+        entry := req.Requests[i]
+        err := s.limits.checkRoot("project_root", entry.GetProjectRoot())
+        var metadata *pb.ProjectMetadata
+        if err == nil {
+            metadata, err = s.GetProjectMetadata(ctx, entry)
+        }
+        if err != nil {
+            return &pb.ProjectMetadataResult{Error: err.Error()}
+        }
+        return &pb.ProjectMetadataResult{Metadata: metadata}
+    })
+
+    for i := range results {
+        if !done[i] {
+            results[i] = &pb.ProjectMetadataResult{Error: "Metadata detection did not complete before the deadline"}
+        }
+    }
+
+    return &pb.ProjectMetadataBatchResponse{Results: results}, nil
+}
+
+// batchConcurrency resolves the per-call parallelism, defaulting to NumCPU
+// and never exceeding the server-wide maximum
+func (s *CCToolsServer) batchConcurrency(requested int32) int {
+    limit := int(requested)
+    if limit <= 0 {
+        limit = runtime.NumCPU()
+    }
+    if s.maxBatchConcurrency > 0 && limit > s.maxBatchConcurrency {
+        limit = s.maxBatchConcurrency
+    }
+    return limit
+}
+
+// runBatch calls fn for every index with at most limit calls in flight.
+// If ctx ends first it stops scheduling and returns whatever finished;
+// done reports which entries hold a real result. Calls still running keep
+// going in the background but can no longer touch the returned slices.
+func runBatch[T any](ctx context.Context, n, limit int, fn func(ctx context.Context, i int) T) ([]T, []bool) {
+    var (
+        mu      sync.Mutex
+        closed  bool
+        results = make([]T, n)
+        done    = make([]bool, n)
+        wg      sync.WaitGroup
+    )
+
+    sem := make(chan struct{}, limit)
+
+schedule:
+    for i := 0; i < n; i++ {
+        select {
+        case sem <- struct{}{}:
+        case <-ctx.Done():
+            break schedule
+        }
+
+        wg.Add(1)
+        go func(i int) {
+            defer wg.Done()
+            defer func() { <-sem }()
+            result := fn(ctx, i)
+
+            mu.Lock()
+            defer mu.Unlock()
+            if !closed {
+                results[i] = result
+                done[i] = true
+            }
+        }(i)
+    }
+
+    finished := make(chan struct{})
+    go func() {
+        wg.Wait()
+        close(finished)
+    }()
+
+    select {
+    case <-finished:
+    case <-ctx.Done():
+    }
+
+    mu.Lock()
+    defer mu.Unlock()
+    closed = true
+    return results, done
+}
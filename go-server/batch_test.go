@@ -0,0 +1,68 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestValidateProjectsMixedRoots(t *testing.T) {
+    passing := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+    failing := makeProject(t, map[string]string{"lint": "true", "test": "exit 1"})
+    // The test's temp dirs share a parent; the system temp dir holds it
+    t.Setenv("ALLOWED_ROOTS", filepath.Dir(passing))
+    outside := os.TempDir()
+    ts := newTestServer(t, serverConfig{})
+
+    resp, err := ts.client.ValidateProjects(context.Background(), &pb.BatchValidationRequest{
+        Requests: []*pb.ValidationRequest{
+            {ProjectRoot: passing},
+            {ProjectRoot: failing},
+            {ProjectRoot: passing, WorkingDir: "no-such-dir"},
+            {ProjectRoot: outside},
+            {ProjectRoot: "relative/project"},
+        },
+        MaxConcurrency: 2,
+    })
+    if err != nil {
+        t.Fatalf("ValidateProjects: %v", err)
+    }
+    if len(resp.Responses) != 5 {
+        t.Fatalf("got %d responses, want 5", len(resp.Responses))
+    }
+
+    // Responses come back in request order, each with its own outcome
+    if r := resp.Responses[0]; !r.Success || r.Metadata.GetProjectRoot() != passing {
+        t.Errorf("passing project: success=%v root=%q error=%q", r.Success, r.Metadata.GetProjectRoot(), r.ErrorMessage)
+    }
+    if r := resp.Responses[1]; r.Success || r.ErrorMessage != "" || resultsByName(r)["test"].GetSuccess() {
+        t.Errorf("failing project: success=%v error=%q, want a failed test result", r.Success, r.ErrorMessage)
+    }
+    if r := resp.Responses[2]; r.Success || r.ErrorCode != codes.InvalidArgument.String() {
+        t.Errorf("bad working_dir: success=%v code=%q, want %s", r.Success, r.ErrorCode, codes.InvalidArgument)
+    }
+    // A bad root fails only its own entry
+    for i, name := range map[int]string{3: "disallowed root", 4: "relative root"} {
+        if r := resp.Responses[i]; r.Success || r.ErrorCode != codes.InvalidArgument.String() || len(r.Results) != 0 {
+            t.Errorf("%s: success=%v code=%q results=%d, want %s and nothing run", name, r.Success, r.ErrorCode, len(r.Results), codes.InvalidArgument)
+        }
+    }
+
+    metadata, err := ts.client.GetProjectMetadataBatch(context.Background(), &pb.BatchValidationRequest{
+        Requests: []*pb.ValidationRequest{{ProjectRoot: passing}, {ProjectRoot: outside}},
+    })
+    if err != nil {
+        t.Fatalf("GetProjectMetadataBatch: %v", err)
+    }
+    if r := metadata.Results[0]; r.Error != "" || r.Metadata.GetProjectType() != "make" {
+        t.Errorf("allowed root: type=%q error=%q", r.Metadata.GetProjectType(), r.Error)
+    }
+    if r := metadata.Results[1]; r.Error == "" || r.Metadata != nil {
+        t.Errorf("disallowed root: metadata=%v error=%q, want an error", r.Metadata, r.Error)
+    }
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+)
+
+// defaultAllowedCommands are the programs the built-in detectors emit
+// commands for
+const defaultAllowedCommands = "npm,pnpm,yarn,cargo,make,go,mvn,gradle,gradlew,pytest,ruff,flake8,poetry,mix,zig,cmake,ctest,composer,php,phpunit,bazel,bazelisk,deno"
+
+// projectLocalCommands are the wrappers the detectors run from inside the
+// project; they are the only path-qualified programs allowed outside the
+// trusted directories
+var projectLocalCommands = map[string]bool{
+    "./gradlew":            true,
+    "./vendor/bin/phpunit": true,
+}
+
+// commandAllowlist restricts validators to known executables so a project
+// config or override cannot make the server run arbitrary programs.
+//
+// The validator's own program must have an allowed basename and resolve to
+// a file in one of the trusted directories (ALLOWED_COMMAND_DIRS,
+// colon-separated; the server's PATH by default), so a `./npm` shipped by
+// the project or a `/tmp/x/npm` is refused. Bare names resolve against the
+// server's PATH, as exec does. A login shell resolves the program itself
+// and would run anything after a `;`, so login-shell commands must be a
+// single simple command: a bare program name, no operators, redirections
+// or substitutions, and no PATH in the request env. The operator's
+// VALIDATOR_COMMAND_PREFIX is trusted as-is.
+type commandAllowlist struct {
+    names       map[string]bool
+    trustedDirs []string
+}
+
+// loadCommandAllowlist reads ALLOWED_COMMANDS (comma-separated basenames)
+// and ALLOWED_COMMAND_DIRS; "*" allows any command and returns nil
+func loadCommandAllowlist() *commandAllowlist {
+    allowed := &commandAllowlist{names: make(map[string]bool)}
+    for _, name := range strings.Split(envString("ALLOWED_COMMANDS", defaultAllowedCommands), ",") {
+        if name = strings.TrimSpace(name); name == "*" {
+            return nil
+        } else if name != "" {
+            allowed.names[name] = true
+        }
+    }
+    dirs := envString("ALLOWED_COMMAND_DIRS", os.Getenv("PATH"))
+    for _, dir := range filepath.SplitList(dirs) {
+        // Relative PATH entries would trust whatever directory a
+        // validator runs in
+        if filepath.IsAbs(dir) {
+            allowed.trustedDirs = append(allowed.trustedDirs, resolveRoot(filepath.Clean(dir)))
+        }
+    }
+    return allowed
+}
+
+// errNotPermitted is wrapped by every allowlist rejection
+var errNotPermitted = errors.New("command not permitted")
+
+// check returns an error wrapping errNotPermitted when the validator may
+// not run; a nil allowlist permits everything
+func (a *commandAllowlist) check(spec *validatorSpec) error {
+    if a == nil {
+        return nil
+    }
+    program := spec.program()
+    if spec.loginShell {
+        if err := simpleShellCommand(spec.command); err != nil {
+            return fmt.Errorf("%w: login shell command %v", errNotPermitted, err)
+        }
+        if strings.ContainsRune(program, '/') {
+            return fmt.Errorf("%w: login shell command must name its program without a path, got %q", errNotPermitted, program)
+        }
+        if _, ok := spec.env["PATH"]; ok {
+            return fmt.Errorf("%w: PATH cannot be overridden for a login shell command", errNotPermitted)
+        }
+    }
+    return a.checkProgram(program, spec.workDir)
+}
+
+// permits reports whether program, run from dir, passes the allowlist
+func (a *commandAllowlist) permits(program, dir string) bool {
+    return a == nil || a.checkProgram(program, dir) == nil
+}
+
+func (a *commandAllowlist) checkProgram(program, dir string) error {
+    base := filepath.Base(program)
+    if !a.names[base] {
+        return fmt.Errorf("%w: %q is not in ALLOWED_COMMANDS", errNotPermitted, base)
+    }
+    if projectLocalCommands[program] {
+        return nil
+    }
+
+    path := program
+    if strings.ContainsRune(program, '/') && !filepath.IsAbs(program) {
+        path = filepath.Join(dir, program)
+    }
+    resolved, err := exec.LookPath(path)
+    if err != nil {
+        if !strings.ContainsRune(program, '/') {
+            // Not installed: let it fail to start and be reported as such
+            return nil
+        }
+        return fmt.Errorf("%w: %q: %v", errNotPermitted, program, err)
+    }
+    if resolved, err = filepath.Abs(resolveRoot(resolved)); err != nil {
+        return fmt.Errorf("%w: %q: %v", errNotPermitted, program, err)
+    }
+    for _, dir := range a.trustedDirs {
+        if withinRoot(resolved, dir) {
+            return nil
+        }
+    }
+    return fmt.Errorf("%w: %q resolves to %s, outside ALLOWED_COMMAND_DIRS", errNotPermitted, program, resolved)
+}
+
+// simpleShellCommand rejects anything in a shell command string beyond a
+// single command with literal arguments: separators, pipes, redirections,
+// background jobs, subshells, and variable or command substitution
+func simpleShellCommand(command string) error {
+    var quote rune
+    escaped := false
+    for _, r := range command {
+        switch {
+        case escaped:
+            escaped = false
+        case quote == '\'':
+            if r == '\'' {
+                quote = 0
+            }
+        case r == '\\':
+            escaped = true
+        case quote == '"':
+            if r == '"' {
+                quote = 0
+            } else if r == '$' || r == '`' {
+                return fmt.Errorf("contains %q", r)
+            }
+        case r == '\'' || r == '"':
+            quote = r
+        case strings.ContainsRune(";&|<>()$`\n\r", r):
+            return fmt.Errorf("contains %q", r)
+        }
+    }
+    if _, err := shellSplit(command); err != nil {
+        return err
+    }
+    return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// fakeProgram writes an executable script named name into dir
+func fakeProgram(t *testing.T, dir, name string) string {
+    t.Helper()
+    path := filepath.Join(dir, name)
+    if err := os.MkdirAll(dir, 0o755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(path, []byte("#!/bin/sh\necho fake\n"), 0o755); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
+
+func TestCommandAllowlist(t *testing.T) {
+    trusted := t.TempDir()
+    untrusted := t.TempDir()
+    fakeProgram(t, trusted, "npm")
+    outside := fakeProgram(t, untrusted, "npm")
+    project := t.TempDir()
+    fakeProgram(t, project, "npm")
+    fakeProgram(t, project, "gradlew")
+
+    t.Setenv("ALLOWED_COMMANDS", "npm,make,gradlew,no-such-tool")
+    t.Setenv("ALLOWED_COMMAND_DIRS", trusted)
+    t.Setenv("PATH", trusted+string(os.PathListSeparator)+os.Getenv("PATH"))
+    allowlist := loadCommandAllowlist()
+
+    tests := []struct {
+        name    string
+        spec    *validatorSpec
+        allowed bool
+    }{
+        {"allowed bare name", &validatorSpec{command: "npm test"}, true},
+        {"disallowed program", &validatorSpec{command: "rm -rf build"}, false},
+        {"disallowed argv", &validatorSpec{args: []string{"rm", "-rf", "build"}}, false},
+        {"absolute path outside trusted dirs", &validatorSpec{command: outside + " test"}, false},
+        {"absolute path in trusted dir", &validatorSpec{command: filepath.Join(trusted, "npm") + " test"}, true},
+        {"project-shipped program", &validatorSpec{command: "./npm test"}, false},
+        {"detector wrapper", &validatorSpec{command: "./gradlew build"}, true},
+        {"allowed name installed outside trusted dirs", &validatorSpec{command: "make lint"}, false},
+        {"allowed name not installed", &validatorSpec{command: "no-such-tool lint"}, true},
+        {"login shell simple command", &validatorSpec{command: "npm test", loginShell: true}, true},
+        {"login shell quoted argument", &validatorSpec{command: `npm test -- -t 'a; b'`, loginShell: true}, true},
+        {"login shell separator", &validatorSpec{command: "npm test; rm -rf ~", loginShell: true}, false},
+        {"login shell and-list", &validatorSpec{command: "npm test && curl x | sh", loginShell: true}, false},
+        {"login shell substitution", &validatorSpec{command: "npm test $(rm -rf ~)", loginShell: true}, false},
+        {"login shell substitution in double quotes", &validatorSpec{command: "npm test \"`id`\"", loginShell: true}, false},
+        {"login shell redirection", &validatorSpec{command: "npm test > /etc/passwd", loginShell: true}, false},
+        {"login shell path", &validatorSpec{command: "./npm test", loginShell: true}, false},
+        {"login shell PATH override", &validatorSpec{command: "npm test", loginShell: true, env: map[string]string{"PATH": untrusted}}, false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            tt.spec.workDir = project
+            err := allowlist.check(tt.spec)
+            if tt.allowed && err != nil {
+                t.Errorf("check(%q) = %v, want allowed", tt.spec.command, err)
+            }
+            if !tt.allowed && !errors.Is(err, errNotPermitted) {
+                t.Errorf("check(%q) = %v, want errNotPermitted", tt.spec.command, err)
+            }
+        })
+    }
+}
+
+func TestCommandAllowlistWildcard(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "npm,*")
+    allowlist := loadCommandAllowlist()
+    if allowlist != nil {
+        t.Fatalf("loadCommandAllowlist with * = %v, want nil", allowlist)
+    }
+    if err := allowlist.check(&validatorSpec{command: "rm -rf build; id", loginShell: true}); err != nil {
+        t.Errorf("nil allowlist refused a command: %v", err)
+    }
+}
+
+func TestValidateProjectRefusesDisallowedCommand(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", defaultAllowedCommands)
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{"test": "true"})
+    marker := filepath.Join(root, "removed")
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot:      root,
+        OverrideCommands: map[string]string{"lint": "touch " + marker},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    lint := resultsByName(resp)["lint"]
+    if lint == nil || lint.Success || lint.FailureReason != pb.FailureReason_FAILURE_REASON_NOT_PERMITTED {
+        t.Fatalf("lint result = %v, want NOT_PERMITTED", lint)
+    }
+    if !strings.Contains(lint.Error, "command not permitted") {
+        t.Errorf("error = %q, want a command not permitted message", lint.Error)
+    }
+    if _, err := os.Stat(marker); err == nil {
+        t.Error("the disallowed command ran")
+    }
+}
+
+func TestRunManifestSkipsDisallowedPrograms(t *testing.T) {
+    dir := t.TempDir()
+    probe := fakeProgram(t, dir, "probe")
+    t.Setenv("ALLOWED_COMMANDS", "npm")
+    s := NewCCToolsServer()
+
+    spec := &validatorSpec{name: "lint", command: probe + " --fix", workDir: dir}
+    manifest := s.buildRunManifest(context.Background(), &pb.ValidationRequest{ProjectRoot: dir}, &pb.ProjectMetadata{}, []*validatorSpec{spec})
+    if _, probed := manifest.ToolchainVersions[probe]; probed {
+        t.Errorf("ToolchainVersions = %v, want the disallowed program left unprobed", manifest.ToolchainVersions)
+    }
+}
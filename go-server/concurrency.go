@@ -0,0 +1,187 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "sync"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Validator scheduling
+//
+// A request starts all of its validators at once, up to its parallelism
+// (VALIDATOR_PARALLELISM, lowered per request by max_parallel_validators).
+// Before it runs, a validator takes the lock for its contention key and
+// then one of the MAX_CONCURRENT_VALIDATORS slots. Validators sharing a key
+// run one at a time across all requests; different keys run in parallel.
+// The key defaults to the project type, the directory the validator runs
+// in and the validator's name, so two runs of the same crate's cargo test
+// never overlap while its lint and test, or a cargo build and an npm lint,
+// proceed together. Keys are configurable, highest precedence first:
+//
+//   1. contention_keys on the request, per validator ("" opts out)
+//   2. CONTENTION_KEYS per project type (e.g. "cargo=cargo" serializes
+//      every cargo validator on the node, for a shared CARGO_TARGET_DIR)
+//   3. the default "<project type>:<working dir>:<validator>"
+
+// defaultValidatorParallelism is how many validators of one request run at
+// once unless VALIDATOR_PARALLELISM says otherwise; 1 runs them in order
+const defaultValidatorParallelism = 4
+
+// validatorSlots schedules validators by contention key and global limit
+type validatorSlots struct {
+    sem chan struct{} // nil when MAX_CONCURRENT_VALIDATORS is unlimited
+
+    mu   sync.Mutex
+    keys map[string]*keyLock
+}
+
+// keyLock serializes the validators sharing a contention key
+type keyLock struct {
+    ch   chan struct{}
+    refs int // holders plus waiters; the entry is dropped at zero
+}
+
+func newValidatorSlots(limit int) *validatorSlots {
+    v := &validatorSlots{keys: make(map[string]*keyLock)}
+    if limit > 0 {
+        v.sem = make(chan struct{}, limit)
+    }
+    return v
+}
+
+// contentionKey resolves the scheduling key of a validator
+func (s *CCToolsServer) contentionKey(req *pb.ValidationRequest, metadata *pb.ProjectMetadata, spec *validatorSpec) string {
+    if key, ok := req.ContentionKeys[spec.name]; ok {
+        return key
+    }
+    if key, ok := s.contentionKeys[metadata.ProjectType]; ok {
+        return key
+    }
+    return metadata.ProjectType + ":" + spec.workDir + ":" + spec.name
+}
+
+// validatorParallelism is the server's parallelism, lowered by the
+// request's max_parallel_validators
+func (s *CCToolsServer) validatorParallelism(req *pb.ValidationRequest) int {
+    n := max(1, s.parallelism)
+    if req.MaxParallelValidators > 0 {
+        n = min(n, int(req.MaxParallelValidators))
+    }
+    return n
+}
+
+// runScheduled runs one validator of a request once the scheduler admits it
+func (s *CCToolsServer) runScheduled(ctx context.Context, req *pb.ValidationRequest, spec *validatorSpec, stream *validationStream, usage *runConcurrency) *pb.ValidationResult {
+    release, err := s.slots.acquire(ctx, spec.contentionKey, usage)
+    if err != nil {
+        result := &pb.ValidationResult{Validator: spec.name, Error: fmt.Sprintf("cancelled while waiting for a validator slot: %v", err)}
+        stream.finish(nil, result)
+        return result
+    }
+    batcher := stream.attach(spec)
+    result := s.runValidator(ctx, req, spec)
+    release()
+    stream.finish(batcher, result)
+    return result
+}
+
+// runConcurrency records how parallel one run actually was: the most of its
+// validators holding a slot at once and the total time spent queueing
+type runConcurrency struct {
+    mu        sync.Mutex
+    running   int32
+    max       int32
+    queueWait time.Duration
+}
+
+// acquire waits for the key's lock and a slot on behalf of a run and
+// returns the function that gives both back, or ctx's error if ctx ends
+// first. The key is taken first so a queued validator holds no slot.
+func (v *validatorSlots) acquire(ctx context.Context, key string, usage *runConcurrency) (func(), error) {
+    start := time.Now()
+    releaseKey, err := v.lockKey(ctx, key)
+    if err != nil {
+        usage.started(time.Since(start), false)
+        return nil, err
+    }
+    if v.sem != nil {
+        select {
+        case v.sem <- struct{}{}:
+        case <-ctx.Done():
+            releaseKey()
+            usage.started(time.Since(start), false)
+            return nil, ctx.Err()
+        }
+    }
+    usage.started(time.Since(start), true)
+
+    return func() {
+        usage.finished()
+        if v.sem != nil {
+            <-v.sem
+        }
+        releaseKey()
+    }, nil
+}
+
+// lockKey takes the lock for key; an empty key is never contended
+func (v *validatorSlots) lockKey(ctx context.Context, key string) (func(), error) {
+    if key == "" {
+        return func() {}, nil
+    }
+
+    v.mu.Lock()
+    lock, ok := v.keys[key]
+    if !ok {
+        lock = &keyLock{ch: make(chan struct{}, 1)}
+        v.keys[key] = lock
+    }
+    lock.refs++
+    v.mu.Unlock()
+
+    unref := func() {
+        v.mu.Lock()
+        defer v.mu.Unlock()
+        if lock.refs--; lock.refs == 0 {
+            delete(v.keys, key)
+        }
+    }
+
+    select {
+    case lock.ch <- struct{}{}:
+    case <-ctx.Done():
+        unref()
+        return nil, ctx.Err()
+    }
+    return func() {
+        <-lock.ch
+        unref()
+    }, nil
+}
+
+func (r *runConcurrency) started(wait time.Duration, acquired bool) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.queueWait += wait
+    if acquired {
+        r.running++
+        r.max = max(r.max, r.running)
+    }
+}
+
+func (r *runConcurrency) finished() {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    r.running--
+}
+
+// annotate adds the run's concurrency figures to its summary
+func (r *runConcurrency) annotate(summary *pb.ValidationSummary) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    summary.MaxConcurrency = r.max
+    summary.QueueWaitMs = r.queueWait.Milliseconds()
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+    "os"
+    "strconv"
+    "strings"
+)
+
+// envInt reads an integer environment variable, falling back to def when unset or invalid
+func envInt(key string, def int) int {
+    v := os.Getenv(key)
+    if v == "" {
+        return def
+    }
+    n, err := strconv.Atoi(v)
+    if err != nil {
+        return def
+    }
+    return n
+}
+
+// envBool reads a boolean environment variable ("1", "true", ...), falling back to def
+func envBool(key string, def bool) bool {
+    v := os.Getenv(key)
+    if v == "" {
+        return def
+    }
+    b, err := strconv.ParseBool(v)
+    if err != nil {
+        return def
+    }
+    return b
+}
+
+// envMap parses a comma-separated list of key=value pairs (e.g. "cargo=2,npm=1")
+func envMap(key string) map[string]string {
+    result := make(map[string]string)
+    for _, pair := range strings.Split(os.Getenv(key), ",") {
+        k, v, ok := strings.Cut(strings.TrimSpace(pair), "=")
+        if !ok || k == "" {
+            continue
+        }
+        result[k] = v
+    }
+    return result
+}
+
+// envSet parses a comma-separated list of names (e.g. "npm,cargo") into a set
+func envSet(key string) map[string]bool {
+    result := make(map[string]bool)
+    for _, name := range strings.Split(os.Getenv(key), ",") {
+        if name = strings.TrimSpace(name); name != "" {
+            result[name] = true
+        }
+    }
+    return result
+}
+
+// envString reads a string environment variable, falling back to def when unset
+func envString(key, def string) string {
+    if v := os.Getenv(key); v != "" {
+        return v
+    }
+    return def
+}
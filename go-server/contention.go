@@ -0,0 +1,111 @@
+package main
+
+import (
+    "context"
+    "expvar"
+    "sort"
+    "sync"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// lockPollInterval is how often a waiting AcquireLock retries
+const lockPollInterval = 50 * time.Millisecond
+
+// otherProjects collects contention for projects beyond the tracked limit
+const otherProjects = "_other"
+
+// Per-project lock wait counters, keyed by project path (or otherProjects)
+var (
+    lockWaitCount   = expvar.NewMap("lock_wait_count")
+    lockWaitMsTotal = expvar.NewMap("lock_wait_ms_total")
+)
+
+// lockWaitStats accumulates contention for one project
+type lockWaitStats struct {
+    contended   int64 // attempts that found the lock held
+    acquired    int64 // waits that ended with the lock taken
+    gaveUp      int64 // waits that timed out, were cancelled or did not wait
+    totalWaitMs int64
+    maxWaitMs   int64
+}
+
+// lockContention tracks lock waits per project. Cardinality is bounded:
+// once maxProjects paths are tracked, new ones are folded into otherProjects.
+type lockContention struct {
+    mu          sync.Mutex
+    projects    map[string]*lockWaitStats
+    maxProjects int
+}
+
+func newLockContention(maxProjects int) *lockContention {
+    return &lockContention{
+        projects:    make(map[string]*lockWaitStats),
+        maxProjects: maxProjects,
+    }
+}
+
+// record notes one contended acquire attempt and how long it waited
+func (c *lockContention) record(projectPath string, waited time.Duration, acquired bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    key := projectPath
+    stats, exists := c.projects[key]
+    if !exists && len(c.projects) >= c.maxProjects {
+        key = otherProjects
+        stats = c.projects[key]
+    }
+    if stats == nil {
+        stats = &lockWaitStats{}
+        c.projects[key] = stats
+    }
+
+    ms := waited.Milliseconds()
+    stats.contended++
+    if acquired {
+        stats.acquired++
+    } else {
+        stats.gaveUp++
+    }
+    stats.totalWaitMs += ms
+    if ms > stats.maxWaitMs {
+        stats.maxWaitMs = ms
+    }
+
+    lockWaitCount.Add(key, 1)
+    lockWaitMsTotal.Add(key, ms)
+    lockWaitsTotal.WithLabelValues(key).Inc()
+    lockWaitSecondsTotal.WithLabelValues(key).Add(waited.Seconds())
+}
+
+// snapshot returns the per-project stats, most waited-on first
+func (c *lockContention) snapshot() []*pb.LockContention {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    result := make([]*pb.LockContention, 0, len(c.projects))
+    for project, stats := range c.projects {
+        result = append(result, &pb.LockContention{
+            ProjectPath:       project,
+            Contended:         stats.contended,
+            AcquiredAfterWait: stats.acquired,
+            GaveUp:            stats.gaveUp,
+            TotalWaitMs:       stats.totalWaitMs,
+            MaxWaitMs:         stats.maxWaitMs,
+        })
+    }
+    sort.Slice(result, func(i, j int) bool {
+        if result[i].TotalWaitMs != result[j].TotalWaitMs {
+            return result[i].TotalWaitMs > result[j].TotalWaitMs
+        }
+        return result[i].Contended > result[j].Contended
+    })
+    return result
+}
+
+// GetStats reports server statistics, currently lock contention per project
+func (s *CCToolsServer) GetStats(ctx context.Context, req *pb.StatsRequest) (*pb.ServerStats, error) {
+    return &pb.ServerStats{LockContention: s.contention.snapshot()}, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// denoConfigFiles identify a Deno project; deno.jsonc allows comments
+var denoConfigFiles = []string{"deno.json", "deno.jsonc"}
+
+// denoConfig is the part of deno.json the server reads
+type denoConfig struct {
+    Name  string                     `json:"name"`
+    Tasks map[string]json.RawMessage `json:"tasks"`
+}
+
+// readDenoConfig parses the first Deno config file found in dir
+func readDenoConfig(dir string) (*denoConfig, error) {
+    for _, file := range denoConfigFiles {
+        data, err := os.ReadFile(filepath.Join(dir, file))
+        if os.IsNotExist(err) {
+            continue
+        }
+        if err != nil {
+            return nil, err
+        }
+        var config denoConfig
+        if err := json.Unmarshal(stripJSONC(data), &config); err != nil {
+            return nil, fmt.Errorf("%s: %w", file, err)
+        }
+        return &config, nil
+    }
+    return nil, fmt.Errorf("no %s in %s", denoConfigFiles[0], dir)
+}
+
+// denoProjectName reads the package name a JSR package declares
+func denoProjectName(dir string) (string, []string, error) {
+    config, err := readDenoConfig(dir)
+    if err != nil {
+        return "", nil, err
+    }
+    return config.Name, nil, nil
+}
+
+// denoStageTasks are the task names, in order of preference, that stand in
+// for each validator stage
+var denoStageTasks = map[string][]string{
+    "format":    {"fmt", "format"},
+    "lint":      {"lint"},
+    "typecheck": {"check", "typecheck"},
+    "build":     {"build"},
+    "test":      {"test"},
+}
+
+// detectDeno runs the project's own tasks where deno.json defines them
+// (`deno task lint`), and Deno's built-in tools otherwise
+func (s *CCToolsServer) detectDeno(metadata *pb.ProjectMetadata, dir, marker string) {
+    config, err := readDenoConfig(filepath.Join(metadata.ProjectRoot, dir))
+    if err != nil {
+        metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("deno detection: %v", err))
+        return
+    }
+    for stage, tasks := range denoStageTasks {
+        for _, task := range tasks {
+            if _, ok := config.Tasks[task]; ok {
+                metadata.Commands[stage] = "deno task " + task
+                break
+            }
+        }
+    }
+}
+
+// stripJSONC turns JSON with comments into plain JSON: // and /* */
+// comments outside strings are blanked and trailing commas before a
+// closing bracket dropped
+func stripJSONC(data []byte) []byte {
+    out := make([]byte, 0, len(data))
+    inString := false
+    for i := 0; i < len(data); i++ {
+        c := data[i]
+        switch {
+        case inString:
+            out = append(out, c)
+            if c == '\\' && i+1 < len(data) {
+                i++
+                out = append(out, data[i])
+            } else if c == '"' {
+                inString = false
+            }
+        case c == '"':
+            inString = true
+            out = append(out, c)
+        case c == '/' && i+1 < len(data) && data[i+1] == '/':
+            for i < len(data) && data[i] != '\n' {
+                i++
+            }
+            i--
+        case c == '/' && i+1 < len(data) && data[i+1] == '*':
+            i += 2
+            for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+                i++
+            }
+            i++
+        case c == '}' || c == ']':
+            // Drop a trailing comma, looking back over whitespace
+            j := len(out) - 1
+            for j >= 0 && (out[j] == ' ' || out[j] == '\t' || out[j] == '\n' || out[j] == '\r') {
+                j--
+            }
+            if j >= 0 && out[j] == ',' {
+                out = append(out[:j], out[j+1:]...)
+            }
+            out = append(out, c)
+        default:
+            out = append(out, c)
+        }
+    }
+    return out
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+    "encoding/json"
+    "testing"
+)
+
+func TestDetectDeno(t *testing.T) {
+    tests := []struct {
+        name        string
+        files       map[string]string
+        configFile  string
+        projectName string
+        commands    map[string]string
+    }{
+        {
+            name:        "default commands",
+            files:       map[string]string{"deno.json": `{"name": "@scope/app"}`, "main.ts": ""},
+            configFile:  "deno.json",
+            projectName: "@scope/app",
+            commands:    map[string]string{"format": "deno fmt --check", "lint": "deno lint", "test": "deno test"},
+        },
+        {
+            name: "tasks",
+            files: map[string]string{"deno.json": `{
+                "tasks": {
+                    "lint": "deno lint --rules-exclude=no-explicit-any",
+                    "check": "deno check main.ts",
+                    "format": "deno fmt --check src",
+                    "test": {"command": "deno test -A", "description": "unit tests"},
+                    "dev": "deno run --watch main.ts"
+                }
+            }`},
+            configFile: "deno.json",
+            commands: map[string]string{
+                "format":    "deno task format",
+                "lint":      "deno task lint",
+                "typecheck": "deno task check",
+                "test":      "deno task test",
+            },
+        },
+        {
+            name: "tasks in deno.jsonc",
+            files: map[string]string{"deno.jsonc": `{
+                // published to JSR
+                "name": "@scope/lib",
+                "tasks": {
+                    /* "lint": "disabled", */
+                    "fmt": "deno fmt --check", // preferred over "format"
+                    "format": "deno fmt",
+                    "build": "deno compile main.ts",
+                },
+            }`},
+            configFile:  "deno.jsonc",
+            projectName: "@scope/lib",
+            commands: map[string]string{
+                "format": "deno task fmt",
+                "lint":   "deno lint",
+                "build":  "deno task build",
+                "test":   "deno test",
+            },
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            metadata, err := NewCCToolsServer().detectProjectMetadata(writeProject(t, tt.files))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if metadata.ProjectType != "deno" || metadata.Language != "typescript" {
+                t.Errorf("type=%q language=%q, want deno and typescript", metadata.ProjectType, metadata.Language)
+            }
+            if len(metadata.ConfigFiles) == 0 || metadata.ConfigFiles[0] != tt.configFile {
+                t.Errorf("config files = %v, want %s first", metadata.ConfigFiles, tt.configFile)
+            }
+            if metadata.ProjectName != tt.projectName {
+                t.Errorf("project name = %q, want %q", metadata.ProjectName, tt.projectName)
+            }
+            if len(metadata.Commands) != len(tt.commands) {
+                t.Errorf("commands = %v, want %v", metadata.Commands, tt.commands)
+            }
+            for stage, want := range tt.commands {
+                if got := metadata.Commands[stage]; got != want {
+                    t.Errorf("%s = %q, want %q", stage, got, want)
+                }
+            }
+            if len(metadata.Warnings) != 0 {
+                t.Errorf("warnings = %v", metadata.Warnings)
+            }
+        })
+    }
+}
+
+func TestStripJSONC(t *testing.T) {
+    in := `{
+        // line comment
+        "url": "https://jsr.io/@std", /* block */
+        "glob": "src/**/*.ts",
+        "list": [1, 2, ],
+    }`
+    var got struct {
+        URL  string `json:"url"`
+        Glob string `json:"glob"`
+        List []int  `json:"list"`
+    }
+    if err := json.Unmarshal(stripJSONC([]byte(in)), &got); err != nil {
+        t.Fatalf("stripped JSONC does not parse: %v\n%s", err, stripJSONC([]byte(in)))
+    }
+    if got.URL != "https://jsr.io/@std" || got.Glob != "src/**/*.ts" || len(got.List) != 2 {
+        t.Errorf("got %+v", got)
+    }
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "path"
+    "path/filepath"
+    "regexp"
+    "strings"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// detector recognizes one project type. detectProjectMetadata asks the
+// detectors in order whether they match and lets the first that does
+// describe the project; GetSupportedProjectTypes lists the same detectors,
+// so what the server advertises is what it detects.
+type detector interface {
+    name() string
+
+    // matches reports whether the project at metadata.ProjectRoot is of
+    // this type. It only records filesystem problems in metadata.Warnings.
+    matches(s *CCToolsServer, metadata *pb.ProjectMetadata) (detectorMatch, bool)
+
+    // describe fills in the type, language, config files and commands of
+    // a project matches accepted
+    describe(s *CCToolsServer, metadata *pb.ProjectMetadata, m detectorMatch)
+
+    // descriptor is the project type as GetSupportedProjectTypes lists it
+    descriptor() *pb.ProjectTypeDescriptor
+}
+
+// detectorMatch is where a detector found its project
+type detectorMatch struct {
+    dir    string // directory holding the marker, relative to the project root
+    marker string
+}
+
+// projectDetector is the detector for project types identified by a marker
+// file, which is all of them so far
+type projectDetector struct {
+    projectType string
+    language    string
+
+    // markers identify the project, in the order they are tried; the
+    // directory holding the first one found is where validators run
+    markers []string
+
+    // commands are the defaults the project type starts from
+    commands map[string]string
+
+    // refine, when set, adjusts the defaults for what else the marker
+    // directory holds (lockfiles, tool configs, wrappers)
+    refine func(s *CCToolsServer, metadata *pb.ProjectMetadata, dir, marker string)
+}
+
+// detectors are tried in order and the first match wins
+var detectors = []detector{
+    &projectDetector{
+        // Checked first: a Bazel workspace builds everything in it, even
+        // when the root also holds a go.mod or package.json
+        projectType: "bazel",
+        markers:     bazelMarkers,
+        commands:    map[string]string{"build": "bazel build //...", "test": "bazel test //..."},
+        refine:      (*CCToolsServer).detectBazel,
+    },
+    &projectDetector{
+        // Checked before npm: PHP apps often carry a package.json for
+        // their frontend assets
+        projectType: "composer",
+        language:    "php",
+        markers:     []string{"composer.json"},
+        commands:    map[string]string{"lint": "composer run lint", "test": "composer test"},
+        refine:      (*CCToolsServer).detectComposer,
+    },
+    &projectDetector{
+        // Checked before npm: Deno reads package.json too, but deno.json
+        // is what makes a project a Deno one
+        projectType: "deno",
+        language:    "typescript",
+        markers:     denoConfigFiles,
+        commands:    map[string]string{"format": "deno fmt --check", "lint": "deno lint", "test": "deno test"},
+        refine:      (*CCToolsServer).detectDeno,
+    },
+    &projectDetector{
+        projectType: "npm",
+        language:    "javascript",
+        markers:     []string{"package.json"},
+        commands:    map[string]string{"lint": "npm run lint", "test": "npm test"},
+        refine:      (*CCToolsServer).detectNode,
+    },
+    &projectDetector{
+        projectType: "cargo",
+        language:    "rust",
+        markers:     []string{"Cargo.toml"},
+        commands:    map[string]string{"lint": "cargo clippy", "test": "cargo test"},
+    },
+    &projectDetector{
+        projectType: "mix",
+        language:    "elixir",
+        markers:     []string{"mix.exs"},
+        commands:    map[string]string{"lint": "mix format --check-formatted", "test": "mix test"},
+        refine:      (*CCToolsServer).detectMix,
+    },
+    &projectDetector{
+        projectType: "zig",
+        language:    "zig",
+        markers:     []string{"build.zig"},
+        commands:    map[string]string{"build": "zig build", "lint": "zig fmt --check .", "test": "zig build test"},
+        refine:      (*CCToolsServer).detectZig,
+    },
+    &projectDetector{
+        // Checked before make so a Go repo with a helper Makefile stays Go
+        projectType: "gomod",
+        language:    "go",
+        markers:     []string{"go.mod"},
+        commands:    map[string]string{"lint": "go vet ./...", "test": "go test ./...", "build": "go build ./..."},
+        refine:      (*CCToolsServer).detectGoMod,
+    },
+    &projectDetector{
+        projectType: "python",
+        language:    "python",
+        markers:     pythonMarkers,
+        commands:    map[string]string{"lint": "ruff check .", "test": "pytest"},
+        refine:      (*CCToolsServer).detectPython,
+    },
+    &projectDetector{
+        projectType: "maven",
+        language:    "java",
+        markers:     []string{"pom.xml"},
+        commands:    map[string]string{"lint": "mvn checkstyle:check", "test": "mvn test"},
+    },
+    &projectDetector{
+        projectType: "gradle",
+        language:    "java",
+        markers:     gradleBuildFiles,
+        commands:    map[string]string{"lint": "gradle check", "test": "gradle test"},
+        refine:      (*CCToolsServer).detectGradle,
+    },
+    &projectDetector{
+        projectType: "cmake",
+        language:    "cpp",
+        markers:     []string{"CMakeLists.txt"},
+        commands: map[string]string{
+            configureValidator: "cmake -S . -B build",
+            "build":            "cmake --build build",
+            "test":             "ctest --test-dir build",
+        },
+    },
+    &projectDetector{
+        projectType: "make",
+        markers:     []string{"Makefile"},
+        commands:    map[string]string{"lint": "make lint", "test": "make test"},
+        refine:      (*CCToolsServer).detectMake,
+    },
+}
+
+// loadDisabledDetectors reads DISABLED_DETECTORS, a comma-separated list of
+// project types this node should never detect (e.g. "cargo,mix")
+func loadDisabledDetectors() map[string]bool {
+    return envSet("DISABLED_DETECTORS")
+}
+
+func (d *projectDetector) name() string {
+    return d.projectType
+}
+
+// matches finds the first of the detector's markers
+func (d *projectDetector) matches(s *CCToolsServer, metadata *pb.ProjectMetadata) (detectorMatch, bool) {
+    for _, marker := range d.markers {
+        if dir, ok := s.locateMarker(metadata, d.projectType, marker); ok {
+            return detectorMatch{dir: dir, marker: marker}, true
+        }
+    }
+    return detectorMatch{}, false
+}
+
+// describe applies the defaults, then refines them for the marker directory
+func (d *projectDetector) describe(s *CCToolsServer, metadata *pb.ProjectMetadata, m detectorMatch) {
+    metadata.ProjectType = d.projectType
+    metadata.Language = d.language
+    metadata.MarkerDir = m.dir
+    metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(m.dir, m.marker))
+    for name, command := range d.commands {
+        metadata.Commands[name] = command
+    }
+    if d.refine != nil {
+        d.refine(s, metadata, m.dir, m.marker)
+    }
+}
+
+// descriptor reports the markers and default commands
+func (d *projectDetector) descriptor() *pb.ProjectTypeDescriptor {
+    commands := make(map[string]string, len(d.commands))
+    for name, command := range d.commands {
+        commands[name] = command
+    }
+    return &pb.ProjectTypeDescriptor{
+        ProjectType:     d.projectType,
+        Language:        d.language,
+        ConfigFiles:     d.markers,
+        DefaultCommands: commands,
+    }
+}
+
+// detectMix uses credo for lint when mix.lock confirms it is a dependency
+func (s *CCToolsServer) detectMix(metadata *pb.ProjectMetadata, dir, marker string) {
+    lock, err := os.ReadFile(filepath.Join(metadata.ProjectRoot, dir, "mix.lock"))
+    if err != nil {
+        if !os.IsNotExist(err) {
+            metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("mix detection: %v", err))
+        }
+        return
+    }
+    metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(dir, "mix.lock"))
+    if strings.Contains(string(lock), `"credo":`) {
+        metadata.Commands["lint"] = "mix credo"
+    }
+}
+
+// detectZig records the package manifest next to build.zig
+func (s *CCToolsServer) detectZig(metadata *pb.ProjectMetadata, dir, marker string) {
+    if s.fileExists(filepath.Join(metadata.ProjectRoot, dir, "build.zig.zon")) {
+        metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(dir, "build.zig.zon"))
+    }
+}
+
+// detectGoMod records go.sum and the module path
+func (s *CCToolsServer) detectGoMod(metadata *pb.ProjectMetadata, dir, marker string) {
+    root := filepath.Join(metadata.ProjectRoot, dir)
+    if s.fileExists(filepath.Join(root, "go.sum")) {
+        metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(dir, "go.sum"))
+    }
+    if data, err := os.ReadFile(filepath.Join(root, "go.mod")); err == nil {
+        metadata.ModulePath, _ = goModDirective(data, "module")
+    }
+}
+
+// bazelMarkers identify a Bazel workspace, Bzlmod before the legacy files
+var bazelMarkers = []string{"MODULE.bazel", "WORKSPACE.bazel", "WORKSPACE"}
+
+// bazelLintTargetPattern finds a target named lint in a BUILD file
+var bazelLintTargetPattern = regexp.MustCompile(`\bname\s*=\s*["']lint["']`)
+
+// detectBazel adds lint when the root package defines a //:lint target
+func (s *CCToolsServer) detectBazel(metadata *pb.ProjectMetadata, dir, marker string) {
+    for _, build := range []string{"BUILD.bazel", "BUILD"} {
+        data, err := os.ReadFile(filepath.Join(metadata.ProjectRoot, dir, build))
+        if err != nil {
+            continue
+        }
+        if bazelLintTargetPattern.Match(data) {
+            metadata.Commands["lint"] = "bazel run //:lint"
+        }
+        return
+    }
+}
+
+// cSourceExtensions map C and C++ source extensions to the language
+var cSourceExtensions = map[string]string{
+    ".c":   "c",
+    ".h":   "c",
+    ".cc":  "cpp",
+    ".cpp": "cpp",
+    ".cxx": "cpp",
+    ".hpp": "cpp",
+}
+
+// detectMake reports a Makefile project as C or C++ when the marker
+// directory holds such sources, C++ winning when both are present
+func (s *CCToolsServer) detectMake(metadata *pb.ProjectMetadata, dir, marker string) {
+    entries, err := os.ReadDir(filepath.Join(metadata.ProjectRoot, dir))
+    if err != nil {
+        return
+    }
+    for _, entry := range entries {
+        language := cSourceExtensions[filepath.Ext(entry.Name())]
+        if entry.IsDir() || language == "" {
+            continue
+        }
+        if metadata.Language = language; language == "cpp" {
+            return
+        }
+    }
+}
+
+// GetSupportedProjectTypes lists the project types this server detects,
+// excluding disabled detectors, with the markers and default commands of
+// each and the validator stages ValidateProject runs
+func (s *CCToolsServer) GetSupportedProjectTypes(ctx context.Context, req *pb.SupportedProjectTypesRequest) (*pb.SupportedProjectTypes, error) {
+    resp := &pb.SupportedProjectTypes{ValidatorStages: validatorStages}
+    for _, d := range detectors {
+        if s.disabledDetectors[d.name()] {
+            continue
+        }
+        resp.ProjectTypes = append(resp.ProjectTypes, d.name())
+        resp.Types = append(resp.Types, d.descriptor())
+    }
+    return resp, nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+    "context"
+    "slices"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// detectorByName returns the registered detector for a project type
+func detectorByName(t *testing.T, name string) detector {
+    t.Helper()
+    for _, d := range detectors {
+        if d.name() == name {
+            return d
+        }
+    }
+    t.Fatalf("no %s detector", name)
+    return nil
+}
+
+// detectorCases is a minimal fixture for each registered detector with
+// what describing it yields
+var detectorCases = []struct {
+    projectType string
+    language    string
+    files       map[string]string
+    commands    map[string]string
+}{
+    {"bazel", "", map[string]string{"MODULE.bazel": ""},
+        map[string]string{"build": "bazel build //...", "test": "bazel test //..."}},
+    {"composer", "php", map[string]string{"composer.json": `{"scripts": {"lint": "phpcs", "test": "phpunit"}}`},
+        map[string]string{"lint": "composer run lint", "test": "composer test"}},
+    {"deno", "typescript", map[string]string{"deno.json": "{}"},
+        map[string]string{"format": "deno fmt --check", "lint": "deno lint", "test": "deno test"}},
+    {"npm", "javascript", map[string]string{"package.json": `{"scripts": {"lint": "eslint .", "test": "jest"}}`},
+        map[string]string{"lint": "npm run lint", "test": "npm test"}},
+    {"cargo", "rust", map[string]string{"Cargo.toml": "[package]\nname = \"app\"\n"},
+        map[string]string{"lint": "cargo clippy", "test": "cargo test"}},
+    {"mix", "elixir", map[string]string{"mix.exs": ""},
+        map[string]string{"lint": "mix format --check-formatted", "test": "mix test"}},
+    {"zig", "zig", map[string]string{"build.zig": ""},
+        map[string]string{"build": "zig build", "lint": "zig fmt --check .", "test": "zig build test"}},
+    {"gomod", "go", map[string]string{"go.mod": "module example.com/app\n"},
+        map[string]string{"build": "go build ./...", "lint": "go vet ./...", "test": "go test ./..."}},
+    {"python", "python", map[string]string{"pyproject.toml": ""},
+        map[string]string{"lint": "ruff check .", "test": "pytest"}},
+    {"maven", "java", map[string]string{"pom.xml": "<project/>"},
+        map[string]string{"lint": "mvn checkstyle:check", "test": "mvn test"}},
+    {"gradle", "java", map[string]string{"build.gradle": ""},
+        map[string]string{"lint": "gradle check", "test": "gradle test"}},
+    {"cmake", "cpp", map[string]string{"CMakeLists.txt": ""},
+        map[string]string{configureValidator: "cmake -S . -B build", "build": "cmake --build build", "test": "ctest --test-dir build"}},
+    {"make", "", map[string]string{"Makefile": "lint:\n\ttrue\ntest:\n\ttrue\n"},
+        map[string]string{"lint": "make lint", "test": "make test"}},
+}
+
+func TestDetectors(t *testing.T) {
+    if len(detectorCases) != len(detectors) {
+        t.Errorf("%d detectors tested, %d registered", len(detectorCases), len(detectors))
+    }
+    s := NewCCToolsServer()
+    for _, tt := range detectorCases {
+        t.Run(tt.projectType, func(t *testing.T) {
+            d := detectorByName(t, tt.projectType)
+
+            empty := &pb.ProjectMetadata{ProjectRoot: t.TempDir(), Commands: map[string]string{}}
+            if _, ok := d.matches(s, empty); ok {
+                t.Error("matched an empty project")
+            }
+
+            metadata := &pb.ProjectMetadata{ProjectRoot: writeProject(t, tt.files), Commands: map[string]string{}}
+            m, ok := d.matches(s, metadata)
+            if !ok {
+                t.Fatalf("did not match %v", tt.files)
+            }
+            if metadata.ProjectType != "" || len(metadata.Commands) != 0 {
+                t.Errorf("matches filled in metadata: %v", metadata)
+            }
+            d.describe(s, metadata, m)
+            if metadata.ProjectType != tt.projectType || metadata.Language != tt.language || metadata.MarkerDir != "." {
+                t.Errorf("type=%q language=%q marker dir=%q, want %q, %q and .", metadata.ProjectType, metadata.Language, metadata.MarkerDir, tt.projectType, tt.language)
+            }
+            if len(metadata.Commands) != len(tt.commands) {
+                t.Errorf("commands = %v, want %v", metadata.Commands, tt.commands)
+            }
+            for name, want := range tt.commands {
+                if got := metadata.Commands[name]; got != want {
+                    t.Errorf("%s = %q, want %q", name, got, want)
+                }
+            }
+            if descriptor := d.descriptor(); descriptor.ProjectType != tt.projectType || len(descriptor.ConfigFiles) == 0 {
+                t.Errorf("descriptor = %v", descriptor)
+            }
+        })
+    }
+}
+
+func TestDetectProjectMetadataPriority(t *testing.T) {
+    tests := []struct {
+        name  string
+        files map[string]string
+        want  string
+    }{
+        {"nothing recognized", map[string]string{"README.md": "# app\n"}, "unknown"},
+        {"bazel over go and npm", map[string]string{"MODULE.bazel": "", "go.mod": "module x\n", "package.json": "{}"}, "bazel"},
+        {"composer over npm", map[string]string{"composer.json": "{}", "package.json": "{}"}, "composer"},
+        {"deno over npm", map[string]string{"deno.json": "{}", "package.json": "{}"}, "deno"},
+        {"go over make", map[string]string{"go.mod": "module x\n", "Makefile": "test:\n\ttrue\n"}, "gomod"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            metadata, err := NewCCToolsServer().detectProjectMetadata(writeProject(t, tt.files))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if metadata.ProjectType != tt.want {
+                t.Errorf("project type = %q, want %q", metadata.ProjectType, tt.want)
+            }
+        })
+    }
+}
+
+func TestDisabledDetectorFallsThrough(t *testing.T) {
+    t.Setenv("DISABLED_DETECTORS", "gomod")
+    metadata, err := NewCCToolsServer().detectProjectMetadata(writeProject(t, map[string]string{
+        "go.mod":   "module x\n",
+        "Makefile": "test:\n\ttrue\n",
+    }))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if metadata.ProjectType != "make" {
+        t.Errorf("project type = %q, want make with gomod disabled", metadata.ProjectType)
+    }
+}
+
+func TestSupportedProjectTypesCoverDetection(t *testing.T) {
+    t.Setenv("DISABLED_DETECTORS", "zig")
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    resp, err := ts.client.GetSupportedProjectTypes(ctx, &pb.SupportedProjectTypesRequest{})
+    if err != nil {
+        t.Fatalf("GetSupportedProjectTypes: %v", err)
+    }
+    listed := make(map[string]*pb.ProjectTypeDescriptor, len(resp.Types))
+    for _, descriptor := range resp.Types {
+        listed[descriptor.ProjectType] = descriptor
+    }
+    if len(resp.ProjectTypes) != len(listed) {
+        t.Errorf("project_types %v and types %d entries disagree", resp.ProjectTypes, len(listed))
+    }
+
+    // Every type detection produces is listed, with the language it reports
+    for _, tt := range detectorCases {
+        metadata, err := ts.client.GetProjectMetadata(ctx, &pb.ValidationRequest{ProjectRoot: writeProject(t, tt.files)})
+        if err != nil {
+            t.Fatalf("%s: GetProjectMetadata: %v", tt.projectType, err)
+        }
+        descriptor, ok := listed[metadata.ProjectType]
+        if tt.projectType == "zig" {
+            if ok || metadata.ProjectType == "zig" {
+                t.Errorf("disabled zig detector: detected %q, listed %v", metadata.ProjectType, ok)
+            }
+            continue
+        }
+        if metadata.ProjectType != tt.projectType || !ok {
+            t.Errorf("%s fixture detected as %q, listed %v", tt.projectType, metadata.ProjectType, ok)
+            continue
+        }
+        if descriptor.Language != tt.language {
+            t.Errorf("%s: listed language %q, want %q", tt.projectType, descriptor.Language, tt.language)
+        }
+        for file := range tt.files {
+            if !slices.Contains(descriptor.ConfigFiles, file) {
+                t.Errorf("%s: config files %v do not include %s", tt.projectType, descriptor.ConfigFiles, file)
+            }
+        }
+    }
+    if len(listed) != len(detectorCases)-1 {
+        t.Errorf("listed %v, want the %d enabled detectors", resp.ProjectTypes, len(detectorCases)-1)
+    }
+}
+
+func TestDetectCMakeAndMakefileC(t *testing.T) {
+    tests := []struct {
+        name        string
+        files       map[string]string
+        projectType string
+        language    string
+        configFile  string
+    }{
+        {"cmake", map[string]string{"CMakeLists.txt": "cmake_minimum_required(VERSION 3.20)\nproject(app CXX)\nadd_executable(app main.cpp)\n", "main.cpp": ""}, "cmake", "cpp", "CMakeLists.txt"},
+        {"cmake over make", map[string]string{"CMakeLists.txt": "", "Makefile": "all:\n"}, "cmake", "cpp", "CMakeLists.txt"},
+        {"makefile C project", map[string]string{"Makefile": "test:\n\ttrue\n", "main.c": "", "util.h": ""}, "make", "c", "Makefile"},
+        {"makefile C++ project", map[string]string{"Makefile": "test:\n\ttrue\n", "main.c": "", "app.cpp": ""}, "make", "cpp", "Makefile"},
+        {"makefile without sources", map[string]string{"Makefile": "test:\n\ttrue\n"}, "make", "", "Makefile"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            metadata, err := NewCCToolsServer().detectProjectMetadata(writeProject(t, tt.files))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if metadata.ProjectType != tt.projectType || metadata.Language != tt.language {
+                t.Errorf("type=%q language=%q, want %q and %q", metadata.ProjectType, metadata.Language, tt.projectType, tt.language)
+            }
+            if len(metadata.ConfigFiles) == 0 || metadata.ConfigFiles[0] != tt.configFile {
+                t.Errorf("config files = %v, want %s first", metadata.ConfigFiles, tt.configFile)
+            }
+        })
+    }
+}
+
+func TestDetectBazel(t *testing.T) {
+    tests := []struct {
+        name   string
+        files  map[string]string
+        marker string
+        lint   string
+    }{
+        {"WORKSPACE", map[string]string{"WORKSPACE": ""}, "WORKSPACE", ""},
+        {"WORKSPACE.bazel", map[string]string{"WORKSPACE.bazel": ""}, "WORKSPACE.bazel", ""},
+        {"MODULE.bazel", map[string]string{"MODULE.bazel": "module(name = \"app\")\n"}, "MODULE.bazel", ""},
+        {"lint target in BUILD.bazel", map[string]string{"WORKSPACE": "", "BUILD.bazel": "sh_binary(\n    name = \"lint\",\n    srcs = [\"lint.sh\"],\n)\n"}, "WORKSPACE", "bazel run //:lint"},
+        {"lint target in BUILD", map[string]string{"WORKSPACE": "", "BUILD": "sh_binary(name='lint', srcs=['lint.sh'])\n"}, "WORKSPACE", "bazel run //:lint"},
+        {"no lint target", map[string]string{"WORKSPACE": "", "BUILD.bazel": "sh_binary(name = \"linter\", srcs = [\"l.sh\"])\n"}, "WORKSPACE", ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            metadata, err := NewCCToolsServer().detectProjectMetadata(writeProject(t, tt.files))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if metadata.ProjectType != "bazel" || len(metadata.ConfigFiles) == 0 || metadata.ConfigFiles[0] != tt.marker {
+                t.Fatalf("type=%q config files=%v, want bazel and %s", metadata.ProjectType, metadata.ConfigFiles, tt.marker)
+            }
+            if metadata.Commands["build"] != "bazel build //..." || metadata.Commands["test"] != "bazel test //..." {
+                t.Errorf("commands = %v", metadata.Commands)
+            }
+            if metadata.Commands["lint"] != tt.lint {
+                t.Errorf("lint = %q, want %q", metadata.Commands["lint"], tt.lint)
+            }
+        })
+    }
+}
+
+func TestBazelValidatorsHonorTimeoutAndOutputCap(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("MAX_OUTPUT_BYTES", "1024")
+    ts := newTestServer(t, serverConfig{})
+    root := writeProject(t, map[string]string{"WORKSPACE": ""})
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot: root,
+        TimeoutMs:   500,
+        OverrideCommands: map[string]string{
+            "build": "sh -c 'head -c 1048576 /dev/zero | tr \"\\0\" x'",
+            "test":  "sleep 30",
+        },
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    if build := resultsByName(resp)["build"]; !build.GetSuccess() || !build.GetTruncated() || len(build.GetOutput()) > 1100 {
+        t.Errorf("build: success=%v truncated=%v %d bytes, want a truncated success", build.GetSuccess(), build.GetTruncated(), len(build.GetOutput()))
+    }
+    if test := resultsByName(resp)["test"]; test.GetFailureReason() != pb.FailureReason_FAILURE_REASON_TIMEOUT {
+        t.Errorf("test = %v, want a timeout", test)
+    }
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+    "sort"
+
+    "gopkg.in/yaml.v3"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// devflowConfigFiles are the per-project config files, in the order they
+// are tried; only the first one found is read
+var devflowConfigFiles = []string{".devflow.yaml", ".devflow.yml", ".devflow.json"}
+
+// devflowConfig is the document in a project's .devflow.yaml or .json.
+// Values are decoded loosely so bad entries can be reported one by one.
+type devflowConfig struct {
+    Commands map[string]interface{} `yaml:"commands" json:"commands"`
+}
+
+// applyDevflowConfig merges the commands of the project's devflow config,
+// kept at the project root, over the detected ones. Keys the config does
+// not name keep their detected command; ProjectType and Language always
+// come from detection. Keys outside validatorStages, such as coverage, add
+// validators that ValidateProject runs after the built-in ones.
+// A missing file is not an error; an unreadable or invalid one, and any
+// command that is not a non-empty string, only adds a warning.
+func applyDevflowConfig(metadata *pb.ProjectMetadata) {
+    for _, name := range devflowConfigFiles {
+        data, err := os.ReadFile(filepath.Join(metadata.ProjectRoot, name))
+        if os.IsNotExist(err) {
+            continue
+        }
+        if err != nil {
+            metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("%s: %v", name, err))
+            return
+        }
+        metadata.ConfigFiles = append(metadata.ConfigFiles, name)
+
+        var config devflowConfig
+        if filepath.Ext(name) == ".json" {
+            err = json.Unmarshal(data, &config)
+        } else {
+            err = yaml.Unmarshal(data, &config)
+        }
+        if err != nil {
+            metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("%s: %v", name, err))
+            return
+        }
+
+        keys := make([]string, 0, len(config.Commands))
+        for key := range config.Commands {
+            keys = append(keys, key)
+        }
+        sort.Strings(keys)
+        for _, key := range keys {
+            command, ok := config.Commands[key].(string)
+            if !ok || command == "" {
+                metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("%s: ignoring command %q: must be a non-empty string", name, key))
+                continue
+            }
+            metadata.Commands[key] = command
+        }
+        return
+    }
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestDevflowConfigOverridesAndAddsValidators(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    root := writeProject(t, map[string]string{
+        "Makefile":      "lint:\n\t@echo linted\ntest:\n\t@echo detected-test\nunit:\n\t@echo configured-test\ncoverage:\n\t@echo covered\n",
+        ".devflow.yaml": "commands:\n  test: make unit\n  coverage: make coverage\n",
+    })
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{ProjectRoot: root})
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    if !resp.Success {
+        t.Fatalf("Success = false, results: %v", resp.Results)
+    }
+    byName := resultsByName(resp)
+    if test := byName["test"]; test == nil || !strings.Contains(test.Output, "configured-test") {
+        t.Errorf("test = %v, want the configured make unit", test)
+    }
+    if coverage := byName["coverage"]; coverage == nil || !strings.Contains(coverage.Output, "covered") {
+        t.Errorf("coverage = %v, want the configured command run", coverage)
+    }
+
+    // Extra validators follow the built-in ones
+    var order []string
+    for _, result := range resp.Results {
+        order = append(order, result.Validator)
+    }
+    if got := strings.Join(order, ","); got != "lint,test,coverage" {
+        t.Errorf("result order = %s, want lint,test,coverage", got)
+    }
+}
+
+func TestValidatorNamesAppendsConfiguredCommands(t *testing.T) {
+    metadata := &pb.ProjectMetadata{Commands: map[string]string{"test": "make test", "coverage": "make cov", "bench": "make bench"}}
+    got := strings.Join(validatorNames(metadata), ",")
+    if want := strings.Join(validatorStages, ",") + ",bench,coverage"; got != want {
+        t.Errorf("validatorNames = %s, want %s", got, want)
+    }
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+    "bufio"
+    "encoding/json"
+    "regexp"
+    "strconv"
+    "strings"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Structured diagnostics
+//
+// For a few tools whose output format is known, the output is also parsed
+// into Diagnostics so clients can count findings and jump to file:line.
+// Recognition goes by the validator's own command (not the operator
+// prefix), so only output the command asks for in a machine-readable form
+// is parsed: ESLint with --format json (e.g. `npm run lint -- --format
+// json`), cargo with --message-format=json, and go vet and go test, whose
+// plain output is already line-oriented. Anything else gets no
+// diagnostics; the raw output is always kept.
+
+// maxDiagnostics bounds the diagnostics attached to one result
+const maxDiagnostics = 1000
+
+// diagnosticParser extracts diagnostics from a recognized tool's output
+type diagnosticParser struct {
+    matches func(args []string) bool
+    parse   func(result *pb.ValidationResult) []*pb.Diagnostic
+}
+
+var diagnosticParsers = []diagnosticParser{
+    {matches: isESLintJSON, parse: parseESLintJSON},
+    {matches: isCargoJSON, parse: parseCargoJSON},
+    {matches: isGoVetOrTest, parse: parseGoOutput},
+}
+
+// parseDiagnostics returns the diagnostics in the validator's output, or
+// nil when the tool is not recognized or never ran
+func parseDiagnostics(spec *validatorSpec, result *pb.ValidationResult) []*pb.Diagnostic {
+    if result.StartFailed || result.Skipped {
+        return nil
+    }
+    args := spec.args
+    if args == nil {
+        args, _ = shellSplit(spec.command)
+    }
+    for _, p := range diagnosticParsers {
+        if p.matches(args) {
+            diagnostics := p.parse(result)
+            if len(diagnostics) > maxDiagnostics {
+                diagnostics = diagnostics[:maxDiagnostics]
+            }
+            return diagnostics
+        }
+    }
+    return nil
+}
+
+// flagValue reports whether args set flag to value, as `--flag value` or
+// `--flag=value`
+func flagValue(args []string, value string, flags ...string) bool {
+    for i, arg := range args {
+        for _, flag := range flags {
+            if arg == flag+"="+value || arg == flag && i+1 < len(args) && args[i+1] == value {
+                return true
+            }
+        }
+    }
+    return false
+}
+
+func isESLintJSON(args []string) bool {
+    if len(args) == 0 {
+        return false
+    }
+    switch args[0] {
+    case "eslint", "npx", "npm", "pnpm", "yarn":
+        return flagValue(args, "json", "--format", "-f")
+    }
+    return false
+}
+
+func isCargoJSON(args []string) bool {
+    if len(args) == 0 || args[0] != "cargo" {
+        return false
+    }
+    for i, arg := range args {
+        format := strings.TrimPrefix(arg, "--message-format=")
+        if arg == "--message-format" && i+1 < len(args) {
+            format = args[i+1]
+        }
+        if format != arg && strings.HasPrefix(format, "json") {
+            return true
+        }
+    }
+    return false
+}
+
+func isGoVetOrTest(args []string) bool {
+    return len(args) > 1 && args[0] == "go" && (args[1] == "vet" || args[1] == "test")
+}
+
+// parseESLintJSON reads ESLint's JSON report. Package managers print their
+// own banner first, so the report is taken from the first line that opens
+// a JSON array.
+func parseESLintJSON(result *pb.ValidationResult) []*pb.Diagnostic {
+    stdout := result.Stdout
+    start := strings.Index(stdout, "\n[")
+    switch {
+    case strings.HasPrefix(stdout, "["):
+        start = 0
+    case start < 0:
+        return nil
+    }
+    var report []struct {
+        FilePath string `json:"filePath"`
+        Messages []struct {
+            Line     int32  `json:"line"`
+            Column   int32  `json:"column"`
+            Severity int    `json:"severity"`
+            Message  string `json:"message"`
+            RuleID   string `json:"ruleId"`
+        } `json:"messages"`
+    }
+    if err := json.NewDecoder(strings.NewReader(stdout[start:])).Decode(&report); err != nil {
+        return nil
+    }
+
+    var diagnostics []*pb.Diagnostic
+    for _, file := range report {
+        for _, m := range file.Messages {
+            severity := "warning"
+            if m.Severity == 2 {
+                severity = "error"
+            }
+            message := m.Message
+            if m.RuleID != "" {
+                message += " (" + m.RuleID + ")"
+            }
+            diagnostics = append(diagnostics, &pb.Diagnostic{
+                File:     file.FilePath,
+                Line:     m.Line,
+                Column:   m.Column,
+                Severity: severity,
+                Message:  message,
+            })
+        }
+    }
+    return diagnostics
+}
+
+// parseCargoJSON reads the compiler messages among cargo's JSON lines,
+// locating each at its primary span. Messages without one, such as the
+// closing "N warnings emitted", are summaries and are left out.
+func parseCargoJSON(result *pb.ValidationResult) []*pb.Diagnostic {
+    var diagnostics []*pb.Diagnostic
+    scanner := bufio.NewScanner(strings.NewReader(result.Stdout))
+    scanner.Buffer(nil, 4<<20)
+    for scanner.Scan() {
+        line := scanner.Bytes()
+        if len(line) == 0 || line[0] != '{' {
+            continue
+        }
+        var msg struct {
+            Reason  string `json:"reason"`
+            Message struct {
+                Message string `json:"message"`
+                Level   string `json:"level"`
+                Code    *struct {
+                    Code string `json:"code"`
+                } `json:"code"`
+                Spans []struct {
+                    FileName    string `json:"file_name"`
+                    LineStart   int32  `json:"line_start"`
+                    ColumnStart int32  `json:"column_start"`
+                    IsPrimary   bool   `json:"is_primary"`
+                } `json:"spans"`
+            } `json:"message"`
+        }
+        if json.Unmarshal(line, &msg) != nil || msg.Reason != "compiler-message" {
+            continue
+        }
+        for _, span := range msg.Message.Spans {
+            if !span.IsPrimary {
+                continue
+            }
+            message := msg.Message.Message
+            if msg.Message.Code != nil && msg.Message.Code.Code != "" {
+                message += " (" + msg.Message.Code.Code + ")"
+            }
+            diagnostics = append(diagnostics, &pb.Diagnostic{
+                File:     span.FileName,
+                Line:     span.LineStart,
+                Column:   span.ColumnStart,
+                Severity: cargoSeverity(msg.Message.Level),
+                Message:  message,
+            })
+            break
+        }
+    }
+    return diagnostics
+}
+
+// cargoSeverity maps rustc's levels onto error, warning and info
+func cargoSeverity(level string) string {
+    switch {
+    case strings.HasPrefix(level, "error"):
+        return "error"
+    case level == "warning":
+        return "warning"
+    }
+    return "info"
+}
+
+// goPositionPattern matches the file:line[:column]: message lines of go vet,
+// compile errors and go test failures (indented under their test)
+var goPositionPattern = regexp.MustCompile(`^(?:vet: )?(\S+\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+// parseGoOutput reads positioned lines from go vet and go test output
+func parseGoOutput(result *pb.ValidationResult) []*pb.Diagnostic {
+    var diagnostics []*pb.Diagnostic
+    for _, line := range strings.Split(result.Output, "\n") {
+        m := goPositionPattern.FindStringSubmatch(strings.TrimSpace(line))
+        if m == nil {
+            continue
+        }
+        lineNo, _ := strconv.Atoi(m[2])
+        column, _ := strconv.Atoi(m[3])
+        diagnostics = append(diagnostics, &pb.Diagnostic{
+            File:     m[1],
+            Line:     int32(lineNo),
+            Column:   int32(column),
+            Severity: "error",
+            Message:  m[4],
+        })
+    }
+    return diagnostics
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// formatDiagnostics renders diagnostics one per line for comparison
+func formatDiagnostics(diagnostics []*pb.Diagnostic) string {
+    lines := make([]string, 0, len(diagnostics))
+    for _, d := range diagnostics {
+        lines = append(lines, fmt.Sprintf("%s:%d:%d %s %s", d.File, d.Line, d.Column, d.Severity, d.Message))
+    }
+    return strings.Join(lines, "\n")
+}
+
+const eslintReport = `[{"filePath":"/p/src/a.js","messages":[` +
+    `{"ruleId":"no-unused-vars","severity":2,"message":"'x' is defined but never used.","line":3,"column":7},` +
+    `{"ruleId":null,"severity":1,"message":"Unexpected console statement.","line":9,"column":1}]},` +
+    `{"filePath":"/p/src/b.js","messages":[]}]`
+
+func TestParseESLintJSON(t *testing.T) {
+    want := "/p/src/a.js:3:7 error 'x' is defined but never used. (no-unused-vars)\n" +
+        "/p/src/a.js:9:1 warning Unexpected console statement."
+    tests := []struct {
+        name   string
+        stdout string
+        want   string
+    }{
+        {"bare report", eslintReport, want},
+        {"package manager banner", "\n> web@1.0.0 lint\n> eslint . --format json\n\n" + eslintReport + "\n", want},
+        {"clean project", "[]", ""},
+        {"no report", "> eslint .\nOops! Something went wrong!\n", ""},
+        {"malformed report", "> eslint .\n[{\"filePath\": ", ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := formatDiagnostics(parseESLintJSON(&pb.ValidationResult{Stdout: tt.stdout}))
+            if got != tt.want {
+                t.Errorf("diagnostics:\n%s\nwant:\n%s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseCargoJSON(t *testing.T) {
+    warning := `{"reason":"compiler-message","message":{"message":"unused variable: ` + "`x`" + `","level":"warning",` +
+        `"code":{"code":"unused_variables"},"spans":[{"file_name":"src/main.rs","line_start":2,"column_start":9,"is_primary":true}]}}`
+    errorMsg := `{"reason":"compiler-message","message":{"message":"mismatched types","level":"error","code":{"code":"E0308"},` +
+        `"spans":[{"file_name":"src/lib.rs","line_start":1,"column_start":1,"is_primary":false},` +
+        `{"file_name":"src/lib.rs","line_start":14,"column_start":5,"is_primary":true}]}}`
+    summary := `{"reason":"compiler-message","message":{"message":"1 warning emitted","level":"warning","code":null,"spans":[]}}`
+    artifact := `{"reason":"compiler-artifact","target":{"name":"app"}}`
+    ice := `{"reason":"compiler-message","message":{"message":"internal compiler error","level":"error: internal compiler error",` +
+        `"code":null,"spans":[{"file_name":"src/x.rs","line_start":5,"column_start":2,"is_primary":true}]}}`
+    note := `{"reason":"compiler-message","message":{"message":"see here","level":"note","code":{"code":""},` +
+        `"spans":[{"file_name":"src/y.rs","line_start":7,"column_start":3,"is_primary":true}]}}`
+
+    tests := []struct {
+        name   string
+        stdout string
+        want   string
+    }{
+        {"warning", warning, "src/main.rs:2:9 warning unused variable: `x` (unused_variables)"},
+        {"primary span", errorMsg, "src/lib.rs:14:5 error mismatched types (E0308)"},
+        {"summary and artifacts skipped", artifact + "\n" + summary + "\n" + warning + "\n",
+            "src/main.rs:2:9 warning unused variable: `x` (unused_variables)"},
+        {"levels", ice + "\n" + note, "src/x.rs:5:2 error internal compiler error\nsrc/y.rs:7:3 info see here"},
+        {"interleaved text", "   Compiling app v0.1.0\n" + errorMsg + "\nerror: could not compile `app`\n",
+            "src/lib.rs:14:5 error mismatched types (E0308)"},
+        {"no json", "error: could not find `Cargo.toml`", ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := formatDiagnostics(parseCargoJSON(&pb.ValidationResult{Stdout: tt.stdout}))
+            if got != tt.want {
+                t.Errorf("diagnostics:\n%s\nwant:\n%s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseGoOutput(t *testing.T) {
+    tests := []struct {
+        name   string
+        output string
+        want   string
+    }{
+        {
+            "go vet",
+            "# example.com/app\n./main.go:12:2: fmt.Printf format %d has arg s of wrong type string\n",
+            "./main.go:12:2 error fmt.Printf format %d has arg s of wrong type string",
+        },
+        {
+            "vet prefix",
+            "vet: internal/x/x.go:3:1: expected declaration, found foo\n",
+            "internal/x/x.go:3:1 error expected declaration, found foo",
+        },
+        {
+            "go test failure without column",
+            "--- FAIL: TestAdd (0.00s)\n    add_test.go:9: Add(1, 2) = 4, want 3\nFAIL\nFAIL\texample.com/app\t0.002s\n",
+            "add_test.go:9:0 error Add(1, 2) = 4, want 3",
+        },
+        {
+            "compile error",
+            "# example.com/app [example.com/app.test]\n./add.go:5:9: undefined: y\nFAIL\texample.com/app [build failed]\n",
+            "./add.go:5:9 error undefined: y",
+        },
+        {"passing", "ok  \texample.com/app\t0.002s\n", ""},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            got := formatDiagnostics(parseGoOutput(&pb.ValidationResult{Output: tt.output}))
+            if got != tt.want {
+                t.Errorf("diagnostics:\n%s\nwant:\n%s", got, tt.want)
+            }
+        })
+    }
+}
+
+func TestParseDiagnosticsRecognizesCommand(t *testing.T) {
+    tests := []struct {
+        command string
+        parsed  bool
+    }{
+        {"npm run lint -- --format json", true},
+        {"eslint -f=json .", true},
+        {"npm run lint", false},
+        {"cargo clippy --message-format=json", true},
+        {"cargo build --message-format json-diagnostic-short", true},
+        {"cargo test", false},
+        {"go vet ./...", true},
+        {"go build ./...", false},
+    }
+    output := "./main.go:1:1: problem\n"
+    for _, tt := range tests {
+        result := &pb.ValidationResult{Output: output, Stdout: eslintReport}
+        if strings.HasPrefix(tt.command, "cargo") {
+            result.Stdout = `{"reason":"compiler-message","message":{"message":"m","level":"error","spans":[{"file_name":"a.rs","line_start":1,"column_start":1,"is_primary":true}]}}`
+        }
+        got := parseDiagnostics(&validatorSpec{command: tt.command}, result)
+        if (len(got) > 0) != tt.parsed {
+            t.Errorf("%q: %d diagnostics, want parsed=%v", tt.command, len(got), tt.parsed)
+        }
+    }
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package main
+
+// freeDiskMB is not implemented on this platform; the pre-flight check is skipped
+func freeDiskMB(path string) (int64, bool) {
+    return 0, false
+}
@@ -0,0 +1,16 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package main
+
+import "syscall"
+
+// freeDiskMB returns the space available to unprivileged users on the
+// filesystem holding path, in megabytes
+func freeDiskMB(path string) (int64, bool) {
+    var st syscall.Statfs_t
+    if err := syscall.Statfs(path, &st); err != nil {
+        return 0, false
+    }
+    return int64(st.Bavail * uint64(st.Bsize) / (1024 * 1024)), true
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+    "context"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// dryRun answers a ValidationRequest with dry_run set: every validator the
+// request resolves to is reported with the argv and directory it would run
+// with, but nothing is executed, so it is safe to point at an untrusted
+// checkout. A command that would be refused before starting (unparsable,
+// empty, or outside ALLOWED_COMMANDS) is reported as the failure a real
+// run would return; every other planned validator is reported as skipped
+// with SKIP_REASON_DRY_RUN.
+func (s *CCToolsServer) dryRun(ctx context.Context, req *pb.ValidationRequest, metadata *pb.ProjectMetadata, stream *validationStream, startTime time.Time) *pb.ValidationResponse {
+    specs := s.resolveValidators(req, metadata)
+    var manifest *pb.RunManifest
+    if req.IncludeManifest {
+        manifest = s.buildRunManifest(ctx, req, metadata, specs)
+    }
+
+    success := true
+    results := make([]*pb.ValidationResult, 0, len(specs))
+    for _, spec := range specs {
+        parts := spec.argv()
+        result := s.checkCommand(spec, parts)
+        if result == nil {
+            result = skippedResult(spec.name, pb.SkipReason_SKIP_REASON_DRY_RUN, "")
+            result.ResolvedArgv = parts
+            result.CommandForm = spec.commandForm()
+            result.WorkDir = spec.workDir
+        }
+        result.DryRun = true
+        success = success && result.Success
+        stream.finish(nil, result)
+        results = append(results, result)
+    }
+    for _, result := range excludedValidators(req, metadata) {
+        result.DryRun = true
+        stream.finish(nil, result)
+        results = append(results, result)
+    }
+    sortResults(results)
+
+    return &pb.ValidationResponse{
+        Success:         success,
+        Results:         results,
+        Metadata:        metadata,
+        ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+        Summary:         summarizeResults(results),
+        Manifest:        manifest,
+    }
+}
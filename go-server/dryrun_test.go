@@ -0,0 +1,67 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestDryRunReportsPlannedValidatorsAsSkipped(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{"lint": "touch linted", "test": "touch tested"})
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{ProjectRoot: root, DryRun: true})
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    if !resp.Success || resp.Summary.Skipped != int32(len(resp.Results)) {
+        t.Errorf("success=%v summary=%v, want every planned validator skipped", resp.Success, resp.Summary)
+    }
+    byName := resultsByName(resp)
+    for _, validator := range []string{"lint", "test"} {
+        result := byName[validator]
+        if result == nil {
+            t.Fatalf("no %s result in %v", validator, resp.Results)
+        }
+        if !result.DryRun || !result.Skipped || result.SkipReason != pb.SkipReason_SKIP_REASON_DRY_RUN {
+            t.Errorf("%s = %v, want DryRun, Skipped and SKIP_REASON_DRY_RUN", validator, result)
+        }
+        if got := strings.Join(result.ResolvedArgv, " "); got != "make "+validator {
+            t.Errorf("%s argv = %q, want the detected make %s", validator, got, validator)
+        }
+        if result.WorkDir != resolveRoot(root) && result.WorkDir != root {
+            t.Errorf("%s work dir = %q, want %q", validator, result.WorkDir, root)
+        }
+    }
+    for _, marker := range []string{"linted", "tested"} {
+        if _, err := os.Stat(filepath.Join(root, marker)); err == nil {
+            t.Errorf("%s exists: a dry run executed a command", marker)
+        }
+    }
+}
+
+func TestDryRunReportsRefusedCommandAsFailure(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", defaultAllowedCommands)
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{"test": "true"})
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot:      root,
+        DryRun:           true,
+        OverrideCommands: map[string]string{"lint": "rm -rf ."},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    lint := resultsByName(resp)["lint"]
+    if lint == nil || lint.Success || lint.Skipped || lint.FailureReason != pb.FailureReason_FAILURE_REASON_NOT_PERMITTED {
+        t.Errorf("lint = %v, want a NOT_PERMITTED failure that is not skipped", lint)
+    }
+    if resp.Success {
+        t.Error("Success = true with a refused command")
+    }
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+    "log/slog"
+    "os"
+    "strings"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// noValidatorsRan is reported when a validation executed nothing
+const noValidatorsRan = "No validators ran"
+
+// loadEmptyRunPolicy reads EMPTY_RUN_POLICY (SUCCESS, ERROR or WARN),
+// defaulting to SUCCESS so existing clients keep passing empty runs
+func loadEmptyRunPolicy() pb.EmptyRunPolicy {
+    v := strings.ToUpper(strings.TrimSpace(os.Getenv("EMPTY_RUN_POLICY")))
+    if v == "" {
+        return pb.EmptyRunPolicy_EMPTY_RUN_POLICY_SUCCESS
+    }
+    policy, ok := pb.EmptyRunPolicy_value["EMPTY_RUN_POLICY_"+v]
+    if !ok || policy == int32(pb.EmptyRunPolicy_EMPTY_RUN_POLICY_UNSPECIFIED) {
+        slog.Warn("Ignoring unknown EMPTY_RUN_POLICY", "policy", v, "using", "SUCCESS")
+        return pb.EmptyRunPolicy_EMPTY_RUN_POLICY_SUCCESS
+    }
+    return pb.EmptyRunPolicy(policy)
+}
+
+// emptyRunPolicy resolves the policy for a request, falling back to the server default
+func (s *CCToolsServer) emptyRunPolicy(req *pb.ValidationRequest) pb.EmptyRunPolicy {
+    if req.EmptyRunPolicy != pb.EmptyRunPolicy_EMPTY_RUN_POLICY_UNSPECIFIED {
+        return req.EmptyRunPolicy
+    }
+    return s.emptyRunDefault
+}
+
+// ranAny reports whether at least one validator was executed rather than skipped
+func ranAny(results []*pb.ValidationResult) bool {
+    for _, result := range results {
+        if !result.Skipped {
+            return true
+        }
+    }
+    return false
+}
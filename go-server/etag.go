@@ -0,0 +1,73 @@
+package main
+
+import (
+    "crypto/sha256"
+    "encoding/hex"
+    "fmt"
+    "hash"
+    "os"
+    "path/filepath"
+    "sort"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// metadataETag fingerprints detected metadata so polling clients can send
+// it back as if_none_match and receive a not-modified reply. It covers the
+// detection result and the content and mtime of every config file, so
+// editing a manifest changes the tag even when the detected commands do not.
+func metadataETag(projectRoot string, metadata *pb.ProjectMetadata) string {
+    h := sha256.New()
+    fmt.Fprintf(h, "type=%s\nmarker=%s\n", metadata.ProjectType, metadata.MarkerDir)
+    writeSorted(h, "command", metadata.Commands)
+
+    available := make(map[string]string, len(metadata.CommandAvailable))
+    for k, v := range metadata.CommandAvailable {
+        available[k] = fmt.Sprint(v)
+    }
+    writeSorted(h, "available", available)
+
+    for _, file := range metadata.ConfigFiles {
+        fmt.Fprintf(h, "file=%s\n", file)
+        path := filepath.Join(projectRoot, file)
+        if info, err := os.Stat(path); err == nil {
+            fmt.Fprintf(h, "mtime=%d\n", info.ModTime().UnixNano())
+        }
+        if data, err := os.ReadFile(path); err == nil {
+            h.Write(data)
+        }
+    }
+    return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// configFingerprint hashes the contents of the config files in path order.
+// Unlike the ETag it ignores mtimes and the detection result, so it only
+// changes when a config file is edited, added or removed.
+func configFingerprint(projectRoot string, configFiles []string) string {
+    files := append([]string(nil), configFiles...)
+    sort.Strings(files)
+
+    h := sha256.New()
+    for _, file := range files {
+        data, err := os.ReadFile(filepath.Join(projectRoot, file))
+        if err != nil {
+            fmt.Fprintf(h, "file=%q unreadable\n", file)
+            continue
+        }
+        fmt.Fprintf(h, "file=%q size=%d\n", file, len(data))
+        h.Write(data)
+    }
+    return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// writeSorted hashes a map in key order
+func writeSorted(h hash.Hash, label string, m map[string]string) {
+    keys := make([]string, 0, len(m))
+    for k := range m {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    for _, k := range keys {
+        fmt.Fprintf(h, "%s=%q:%q\n", label, k, m[k])
+    }
+}
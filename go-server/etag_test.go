@@ -0,0 +1,77 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestFingerprintFollowsConfigContent(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+    makefile := filepath.Join(root, "Makefile")
+
+    // fingerprint returns the fingerprint GetProjectMetadata reports, after
+    // checking ValidateProject reports the same one
+    fingerprint := func() string {
+        t.Helper()
+        metadata, err := ts.client.GetProjectMetadata(ctx, &pb.ValidationRequest{ProjectRoot: root})
+        if err != nil {
+            t.Fatalf("GetProjectMetadata: %v", err)
+        }
+        resp, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root})
+        if err != nil {
+            t.Fatalf("ValidateProject: %v", err)
+        }
+        if resp.Metadata.GetFingerprint() != metadata.Fingerprint {
+            t.Errorf("ValidateProject fingerprint %q, GetProjectMetadata %q", resp.Metadata.GetFingerprint(), metadata.Fingerprint)
+        }
+        if metadata.Fingerprint == "" {
+            t.Fatal("no fingerprint")
+        }
+        return metadata.Fingerprint
+    }
+    write := func(name, content string) {
+        t.Helper()
+        if err := os.MkdirAll(filepath.Dir(filepath.Join(root, name)), 0o755); err != nil {
+            t.Fatal(err)
+        }
+        if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o644); err != nil {
+            t.Fatal(err)
+        }
+    }
+    original := fingerprint()
+
+    // Touching the config file or changing other files leaves it alone
+    later := time.Now().Add(time.Hour)
+    if err := os.Chtimes(makefile, later, later); err != nil {
+        t.Fatal(err)
+    }
+    write("src/main.c", "int main(void) { return 0; }\n")
+    write("README.md", "notes\n")
+    if got := fingerprint(); got != original {
+        t.Errorf("fingerprint changed from %s to %s without a config edit", original, got)
+    }
+
+    // Editing the config file changes it
+    data, err := os.ReadFile(makefile)
+    if err != nil {
+        t.Fatal(err)
+    }
+    write("Makefile", "# build rules\n"+string(data))
+    edited := fingerprint()
+    if edited == original {
+        t.Error("fingerprint unchanged after editing the Makefile")
+    }
+
+    // Restoring the content restores the fingerprint
+    write("Makefile", string(data))
+    if got := fingerprint(); got != original {
+        t.Errorf("fingerprint %s after restoring the Makefile, want %s", got, original)
+    }
+}
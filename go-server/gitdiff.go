@@ -0,0 +1,74 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "os/exec"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+// changedFilesEnv lists the files a run should focus on, newline-separated,
+// for validators that can limit themselves to changed files
+const changedFilesEnv = "DEVFLOW_CHANGED_FILES"
+
+// gitTimeout bounds each git invocation used to resolve a diff range
+const gitTimeout = 30 * time.Second
+
+// changedFilesInRange returns the files changed between base and head
+// (head defaults to HEAD), relative to projectRoot and limited to it.
+// Invalid refs yield InvalidArgument; a project outside a git work tree
+// yields FailedPrecondition.
+func changedFilesInRange(ctx context.Context, projectRoot, base, head string) ([]string, error) {
+    if base == "" {
+        return nil, status.Error(codes.InvalidArgument, "git_base_ref is required when git_head_ref is set")
+    }
+    if head == "" {
+        head = "HEAD"
+    }
+
+    ctx, cancel := context.WithTimeout(ctx, gitTimeout)
+    defer cancel()
+
+    if _, err := runGit(ctx, projectRoot, "rev-parse", "--is-inside-work-tree"); err != nil {
+        return nil, status.Errorf(codes.FailedPrecondition, "%s is not a git repository: %v", projectRoot, err)
+    }
+    for _, ref := range []string{base, head} {
+        if _, err := runGit(ctx, projectRoot, "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}"); err != nil {
+            return nil, status.Errorf(codes.InvalidArgument, "git ref %q does not name a commit", ref)
+        }
+    }
+
+    out, err := runGit(ctx, projectRoot, "diff", "--name-only", "--relative", "-z", base+".."+head, "--")
+    if err != nil {
+        return nil, status.Errorf(codes.Internal, "git diff %s..%s failed: %v", base, head, err)
+    }
+
+    files := make([]string, 0)
+    for _, name := range strings.Split(string(out), "\x00") {
+        if name != "" {
+            files = append(files, name)
+        }
+    }
+    return files, nil
+}
+
+// runGit runs a git subcommand in dir, folding stderr into the error
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+    cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+    var stderr bytes.Buffer
+    cmd.Stderr = &stderr
+
+    out, err := cmd.Output()
+    if err != nil {
+        if msg := strings.TrimSpace(stderr.String()); msg != "" {
+            return nil, fmt.Errorf("%w: %s", err, msg)
+        }
+        return nil, err
+    }
+    return out, nil
+}
@@ -21,16 +21,294 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// What a validation that executed no validators means
+type EmptyRunPolicy int32
+
+const (
+	EmptyRunPolicy_EMPTY_RUN_POLICY_UNSPECIFIED EmptyRunPolicy = 0 // Use the server default
+	EmptyRunPolicy_EMPTY_RUN_POLICY_SUCCESS     EmptyRunPolicy = 1 // Succeed silently
+	EmptyRunPolicy_EMPTY_RUN_POLICY_ERROR       EmptyRunPolicy = 2 // Fail with "No validators ran"
+	EmptyRunPolicy_EMPTY_RUN_POLICY_WARN        EmptyRunPolicy = 3 // Succeed with a warning
+)
+
+// Enum value maps for EmptyRunPolicy.
+var (
+	EmptyRunPolicy_name = map[int32]string{
+		0: "EMPTY_RUN_POLICY_UNSPECIFIED",
+		1: "EMPTY_RUN_POLICY_SUCCESS",
+		2: "EMPTY_RUN_POLICY_ERROR",
+		3: "EMPTY_RUN_POLICY_WARN",
+	}
+	EmptyRunPolicy_value = map[string]int32{
+		"EMPTY_RUN_POLICY_UNSPECIFIED": 0,
+		"EMPTY_RUN_POLICY_SUCCESS":     1,
+		"EMPTY_RUN_POLICY_ERROR":       2,
+		"EMPTY_RUN_POLICY_WARN":        3,
+	}
+)
+
+func (x EmptyRunPolicy) Enum() *EmptyRunPolicy {
+	p := new(EmptyRunPolicy)
+	*p = x
+	return p
+}
+
+func (x EmptyRunPolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EmptyRunPolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_cc_tools_integration_proto_enumTypes[0].Descriptor()
+}
+
+func (EmptyRunPolicy) Type() protoreflect.EnumType {
+	return &file_proto_cc_tools_integration_proto_enumTypes[0]
+}
+
+func (x EmptyRunPolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EmptyRunPolicy.Descriptor instead.
+func (EmptyRunPolicy) EnumDescriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{0}
+}
+
+// Why a validator was not executed
+type SkipReason int32
+
+const (
+	SkipReason_SKIP_REASON_UNSPECIFIED SkipReason = 0 // Not skipped
+	SkipReason_SKIP_REASON_UNCHANGED   SkipReason = 1 // Inputs unchanged since a previous run; served from the result cache with that run's verdict
+	SkipReason_SKIP_REASON_EXCLUDED    SkipReason = 2 // Excluded by the request
+	SkipReason_SKIP_REASON_FAIL_FAST   SkipReason = 3 // Not started because an earlier validator failed
+	SkipReason_SKIP_REASON_DRY_RUN     SkipReason = 4 // Planned only; the command was not executed
+	SkipReason_SKIP_REASON_TOO_SLOW    SkipReason = 5 // Typical duration exceeds the request's max_duration_hint_ms
+)
+
+// Enum value maps for SkipReason.
+var (
+	SkipReason_name = map[int32]string{
+		0: "SKIP_REASON_UNSPECIFIED",
+		1: "SKIP_REASON_UNCHANGED",
+		2: "SKIP_REASON_EXCLUDED",
+		3: "SKIP_REASON_FAIL_FAST",
+		4: "SKIP_REASON_DRY_RUN",
+		5: "SKIP_REASON_TOO_SLOW",
+	}
+	SkipReason_value = map[string]int32{
+		"SKIP_REASON_UNSPECIFIED": 0,
+		"SKIP_REASON_UNCHANGED":   1,
+		"SKIP_REASON_EXCLUDED":    2,
+		"SKIP_REASON_FAIL_FAST":   3,
+		"SKIP_REASON_DRY_RUN":     4,
+		"SKIP_REASON_TOO_SLOW":    5,
+	}
+)
+
+func (x SkipReason) Enum() *SkipReason {
+	p := new(SkipReason)
+	*p = x
+	return p
+}
+
+func (x SkipReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SkipReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_cc_tools_integration_proto_enumTypes[1].Descriptor()
+}
+
+func (SkipReason) Type() protoreflect.EnumType {
+	return &file_proto_cc_tools_integration_proto_enumTypes[1]
+}
+
+func (x SkipReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SkipReason.Descriptor instead.
+func (SkipReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{1}
+}
+
+// Why an executed validator failed
+type FailureReason int32
+
+const (
+	FailureReason_FAILURE_REASON_UNSPECIFIED       FailureReason = 0 // Succeeded, or no specific reason recorded
+	FailureReason_FAILURE_REASON_ABORTED           FailureReason = 1 // Killed by AbortAll, CancelValidationsByProject or server shutdown (SHUTDOWN_POLICY)
+	FailureReason_FAILURE_REASON_RUNAWAY_OUTPUT    FailureReason = 2 // Killed for sustained output above RUNAWAY_OUTPUT_LINES_PER_SEC
+	FailureReason_FAILURE_REASON_CANCELLED         FailureReason = 3 // Killed because the caller cancelled the call, its deadline passed or CancelValidation named the run
+	FailureReason_FAILURE_REASON_TIMEOUT           FailureReason = 4 // Killed after running longer than timeout_ms; worth retrying
+	FailureReason_FAILURE_REASON_COMMAND_NOT_FOUND FailureReason = 5 // Program not found (see start_failure_reason)
+	FailureReason_FAILURE_REASON_EXIT_CODE         FailureReason = 6 // Ran and exited nonzero (see exit_code): a genuine failure
+	FailureReason_FAILURE_REASON_SIGNALED          FailureReason = 7 // Killed by a signal the server did not send (e.g. OOM killer, segfault)
+	FailureReason_FAILURE_REASON_NOT_PERMITTED     FailureReason = 8 // Program not in ALLOWED_COMMANDS; never started
+)
+
+// Enum value maps for FailureReason.
+var (
+	FailureReason_name = map[int32]string{
+		0: "FAILURE_REASON_UNSPECIFIED",
+		1: "FAILURE_REASON_ABORTED",
+		2: "FAILURE_REASON_RUNAWAY_OUTPUT",
+		3: "FAILURE_REASON_CANCELLED",
+		4: "FAILURE_REASON_TIMEOUT",
+		5: "FAILURE_REASON_COMMAND_NOT_FOUND",
+		6: "FAILURE_REASON_EXIT_CODE",
+		7: "FAILURE_REASON_SIGNALED",
+		8: "FAILURE_REASON_NOT_PERMITTED",
+	}
+	FailureReason_value = map[string]int32{
+		"FAILURE_REASON_UNSPECIFIED":       0,
+		"FAILURE_REASON_ABORTED":           1,
+		"FAILURE_REASON_RUNAWAY_OUTPUT":    2,
+		"FAILURE_REASON_CANCELLED":         3,
+		"FAILURE_REASON_TIMEOUT":           4,
+		"FAILURE_REASON_COMMAND_NOT_FOUND": 5,
+		"FAILURE_REASON_EXIT_CODE":         6,
+		"FAILURE_REASON_SIGNALED":          7,
+		"FAILURE_REASON_NOT_PERMITTED":     8,
+	}
+)
+
+func (x FailureReason) Enum() *FailureReason {
+	p := new(FailureReason)
+	*p = x
+	return p
+}
+
+func (x FailureReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FailureReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_cc_tools_integration_proto_enumTypes[2].Descriptor()
+}
+
+func (FailureReason) Type() protoreflect.EnumType {
+	return &file_proto_cc_tools_integration_proto_enumTypes[2]
+}
+
+func (x FailureReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FailureReason.Descriptor instead.
+func (FailureReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{2}
+}
+
+// Why a command could not be started
+type StartFailureReason int32
+
+const (
+	StartFailureReason_START_FAILURE_REASON_UNSPECIFIED       StartFailureReason = 0 // The command started
+	StartFailureReason_START_FAILURE_REASON_NOT_FOUND         StartFailureReason = 1 // Program not on PATH or missing (shell exit 127)
+	StartFailureReason_START_FAILURE_REASON_PERMISSION_DENIED StartFailureReason = 2 // Program not executable by the server user
+	StartFailureReason_START_FAILURE_REASON_NOT_EXECUTABLE    StartFailureReason = 3 // Not a valid executable format (shell exit 126)
+	StartFailureReason_START_FAILURE_REASON_BAD_WORKING_DIR   StartFailureReason = 4 // Working directory missing or inaccessible
+	StartFailureReason_START_FAILURE_REASON_OTHER             StartFailureReason = 5 // Any other error before the process started
+)
+
+// Enum value maps for StartFailureReason.
+var (
+	StartFailureReason_name = map[int32]string{
+		0: "START_FAILURE_REASON_UNSPECIFIED",
+		1: "START_FAILURE_REASON_NOT_FOUND",
+		2: "START_FAILURE_REASON_PERMISSION_DENIED",
+		3: "START_FAILURE_REASON_NOT_EXECUTABLE",
+		4: "START_FAILURE_REASON_BAD_WORKING_DIR",
+		5: "START_FAILURE_REASON_OTHER",
+	}
+	StartFailureReason_value = map[string]int32{
+		"START_FAILURE_REASON_UNSPECIFIED":       0,
+		"START_FAILURE_REASON_NOT_FOUND":         1,
+		"START_FAILURE_REASON_PERMISSION_DENIED": 2,
+		"START_FAILURE_REASON_NOT_EXECUTABLE":    3,
+		"START_FAILURE_REASON_BAD_WORKING_DIR":   4,
+		"START_FAILURE_REASON_OTHER":             5,
+	}
+)
+
+func (x StartFailureReason) Enum() *StartFailureReason {
+	p := new(StartFailureReason)
+	*p = x
+	return p
+}
+
+func (x StartFailureReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (StartFailureReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_cc_tools_integration_proto_enumTypes[3].Descriptor()
+}
+
+func (StartFailureReason) Type() protoreflect.EnumType {
+	return &file_proto_cc_tools_integration_proto_enumTypes[3]
+}
+
+func (x StartFailureReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use StartFailureReason.Descriptor instead.
+func (StartFailureReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{3}
+}
+
 // Validation request message
 type ValidationRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectRoot   string                 `protobuf:"bytes,1,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"`                                                // Root directory of the project
-	HookType      string                 `protobuf:"bytes,2,opt,name=hook_type,json=hookType,proto3" json:"hook_type,omitempty"`                                                         // Type of hook being validated (pre-commit, pre-push, etc.)
-	FilePaths     []string               `protobuf:"bytes,3,rep,name=file_paths,json=filePaths,proto3" json:"file_paths,omitempty"`                                                      // Files to be validated
-	Context       map[string]string      `protobuf:"bytes,4,rep,name=context,proto3" json:"context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional context data
-	TimeoutMs     int32                  `protobuf:"varint,5,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`                                                     // Timeout for validation in milliseconds
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ProjectRoot string                 `protobuf:"bytes,1,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"`                                                // Root directory of the project
+	HookType    string                 `protobuf:"bytes,2,opt,name=hook_type,json=hookType,proto3" json:"hook_type,omitempty"`                                                         // Type of hook being validated (pre-commit, pre-push, etc.)
+	FilePaths   []string               `protobuf:"bytes,3,rep,name=file_paths,json=filePaths,proto3" json:"file_paths,omitempty"`                                                      // Files to be validated
+	Context     map[string]string      `protobuf:"bytes,4,rep,name=context,proto3" json:"context,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional context data
+	TimeoutMs   int32                  `protobuf:"varint,5,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`                                                     // Timeout for each validator in milliseconds (0 = DEFAULT_TIMEOUT_MS, 30s unless configured)
+	// Run every validator through a login shell (`bash -lc`) so profile-sourced
+	// shims (asdf, rbenv, nvm) are initialized. Sourcing the profiles costs an
+	// extra shell startup per validator, typically tens to hundreds of ms.
+	LoginShell           bool     `protobuf:"varint,6,opt,name=login_shell,json=loginShell,proto3" json:"login_shell,omitempty"`
+	LoginShellValidators []string `protobuf:"bytes,7,rep,name=login_shell_validators,json=loginShellValidators,proto3" json:"login_shell_validators,omitempty"` // Run only these validators through a login shell
+	// Quick-check mode: skip validators whose median recorded duration for this
+	// project exceeds the hint. Validators without history always run, so the
+	// first call on a project runs everything. Hint-skipped validators are
+	// reported with SKIP_REASON_TOO_SLOW and never count as failures.
+	MaxDurationHintMs        int64                   `protobuf:"varint,8,opt,name=max_duration_hint_ms,json=maxDurationHintMs,proto3" json:"max_duration_hint_ms,omitempty"`
+	OverrideCommands         map[string]string       `protobuf:"bytes,9,rep,name=override_commands,json=overrideCommands,proto3" json:"override_commands,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`              // Replace detected commands by validator name; an empty value disables the validator (reported skipped with SKIP_REASON_EXCLUDED)
+	OverrideArgv             map[string]*CommandArgv `protobuf:"bytes,10,rep,name=override_argv,json=overrideArgv,proto3" json:"override_argv,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                         // Same as override_commands as an explicit argv; wins when both are set
+	VerifyTooling            bool                    `protobuf:"varint,11,opt,name=verify_tooling,json=verifyTooling,proto3" json:"verify_tooling,omitempty"`                                                                                               // GetProjectMetadata: check each command's program is on PATH (adds latency)
+	Retries                  int32                   `protobuf:"varint,12,opt,name=retries,proto3" json:"retries,omitempty"`                                                                                                                                // Re-runs allowed for failures classified as transient, with exponential backoff between attempts (RETRY_BACKOFF_MS, RETRY_BACKOFF_MAX_MS)
+	FailuresOnly             bool                    `protobuf:"varint,13,opt,name=failures_only,json=failuresOnly,proto3" json:"failures_only,omitempty"`                                                                                                  // Drop passing and skipped results from the response (they are still counted in the summary)
+	Env                      map[string]string       `protobuf:"bytes,14,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                                                               // Validator environment; overrides the project-type defaults, which override the server environment
+	GitBaseRef               string                  `protobuf:"bytes,15,opt,name=git_base_ref,json=gitBaseRef,proto3" json:"git_base_ref,omitempty"`                                                                                                       // With git_head_ref, add the files changed in base..head to file_paths
+	GitHeadRef               string                  `protobuf:"bytes,16,opt,name=git_head_ref,json=gitHeadRef,proto3" json:"git_head_ref,omitempty"`                                                                                                       // End of the diff range (defaults to HEAD when git_base_ref is set)
+	StreamFlushLines         int32                   `protobuf:"varint,17,opt,name=stream_flush_lines,json=streamFlushLines,proto3" json:"stream_flush_lines,omitempty"`                                                                                    // StreamValidation: send output once this many lines are buffered (0 = server default)
+	StreamFlushIntervalMs    int32                   `protobuf:"varint,18,opt,name=stream_flush_interval_ms,json=streamFlushIntervalMs,proto3" json:"stream_flush_interval_ms,omitempty"`                                                                   // StreamValidation: send buffered output after this long (0 = server default)
+	EmptyRunPolicy           EmptyRunPolicy          `protobuf:"varint,19,opt,name=empty_run_policy,json=emptyRunPolicy,proto3,enum=cc_tools_integration.EmptyRunPolicy" json:"empty_run_policy,omitempty"`                                                 // Outcome when no validator runs (UNSPECIFIED = server default, EMPTY_RUN_POLICY)
+	ArtifactGlobs            []string                `protobuf:"bytes,20,rep,name=artifact_globs,json=artifactGlobs,proto3" json:"artifact_globs,omitempty"`                                                                                                // Files to return after each validator, relative to project_root ("**" matches any directories)
+	CheckToolchain           bool                    `protobuf:"varint,21,opt,name=check_toolchain,json=checkToolchain,proto3" json:"check_toolchain,omitempty"`                                                                                            // Run the built-in "toolchain-version" validator first; on failure the others are skipped
+	Stdin                    []byte                  `protobuf:"bytes,22,opt,name=stdin,proto3" json:"stdin,omitempty"`                                                                                                                                     // Written to each validator's stdin, which is then closed (e.g. for `gofmt` or `black -`)
+	StdinFile                string                  `protobuf:"bytes,23,opt,name=stdin_file,json=stdinFile,proto3" json:"stdin_file,omitempty"`                                                                                                            // File under project_root to pipe to stdin instead of stdin; mutually exclusive with stdin
+	ChattyValidators         []string                `protobuf:"bytes,24,rep,name=chatty_validators,json=chattyValidators,proto3" json:"chatty_validators,omitempty"`                                                                                       // Exempt these validators from the runaway output guard
+	IfNoneMatch              string                  `protobuf:"bytes,25,opt,name=if_none_match,json=ifNoneMatch,proto3" json:"if_none_match,omitempty"`                                                                                                    // GetProjectMetadata: etag from an earlier reply; an unchanged project returns only etag and not_modified
+	StreamProgressIntervalMs int32                   `protobuf:"varint,26,opt,name=stream_progress_interval_ms,json=streamProgressIntervalMs,proto3" json:"stream_progress_interval_ms,omitempty"`                                                          // StreamValidation: how often running validators report progress (0 = server default)
+	ForceColor               bool                    `protobuf:"varint,27,opt,name=force_color,json=forceColor,proto3" json:"force_color,omitempty"`                                                                                                        // Set FORCE_COLOR=1, CLICOLOR_FORCE=1 and CARGO_TERM_COLOR=always so tools emit ANSI color
+	IncludeManifest          bool                    `protobuf:"varint,28,opt,name=include_manifest,json=includeManifest,proto3" json:"include_manifest,omitempty"`                                                                                         // Return a RunManifest describing how to reproduce the run (probes each program's version)
+	PrefixOutput             bool                    `protobuf:"varint,29,opt,name=prefix_output,json=prefixOutput,proto3" json:"prefix_output,omitempty"`                                                                                                  // StreamValidation: prefix streamed lines with the validator name; result output stays raw
+	OutputPrefixFormat       string                  `protobuf:"bytes,30,opt,name=output_prefix_format,json=outputPrefixFormat,proto3" json:"output_prefix_format,omitempty"`                                                                               // Prefix for prefix_output; {validator} and {run} (job id) are expanded (default OUTPUT_PREFIX_FORMAT, "[{validator}] ")
+	ContentionKeys           map[string]string       `protobuf:"bytes,31,rep,name=contention_keys,json=contentionKeys,proto3" json:"contention_keys,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                   // Validators sharing a key run one at a time across all requests; "" opts a validator out (default "<project type>:<working dir>:<validator>")
+	MaxParallelValidators    int32                   `protobuf:"varint,32,opt,name=max_parallel_validators,json=maxParallelValidators,proto3" json:"max_parallel_validators,omitempty"`                                                                     // Run at most this many validators at once (0 = VALIDATOR_PARALLELISM; 1 runs them in order)
+	UseCache                 bool                    `protobuf:"varint,33,opt,name=use_cache,json=useCache,proto3" json:"use_cache,omitempty"`                                                                                                              // ValidateProject: reuse the response of an identical earlier request if no project file changed since (not for git ranges or streams)
+	DryRun                   bool                    `protobuf:"varint,34,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                                                                                                    // ValidateProject: resolve the validators and return what would run without executing anything (no check_toolchain, manifest versions or git range)
+	RetryExitCodes           []int32                 `protobuf:"varint,35,rep,packed,name=retry_exit_codes,json=retryExitCodes,proto3" json:"retry_exit_codes,omitempty"`                                                                                   // Exit codes also treated as transient for this request, on top of RETRY_TRANSIENT_EXIT_CODES (e.g. 1 to retry flaky test failures)
+	WorkingDir               string                  `protobuf:"bytes,36,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`                                                                                                         // Run validators from this directory, relative to project_root, instead of the marker directory; must resolve inside project_root
+	ValidatorTimeoutsMs      map[string]int32        `protobuf:"bytes,37,rep,name=validator_timeouts_ms,json=validatorTimeoutsMs,proto3" json:"validator_timeouts_ms,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Per-validator timeouts (e.g. test: 300000); take precedence over timeout_ms, which falls back to DEFAULT_TIMEOUT_MS
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
 }
 
 func (x *ValidationRequest) Reset() {
@@ -98,33 +376,2613 @@ func (x *ValidationRequest) GetTimeoutMs() int32 {
 	return 0
 }
 
-// Project metadata message
-type ProjectMetadata struct {
+func (x *ValidationRequest) GetLoginShell() bool {
+	if x != nil {
+		return x.LoginShell
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetLoginShellValidators() []string {
+	if x != nil {
+		return x.LoginShellValidators
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetMaxDurationHintMs() int64 {
+	if x != nil {
+		return x.MaxDurationHintMs
+	}
+	return 0
+}
+
+func (x *ValidationRequest) GetOverrideCommands() map[string]string {
+	if x != nil {
+		return x.OverrideCommands
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetOverrideArgv() map[string]*CommandArgv {
+	if x != nil {
+		return x.OverrideArgv
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetVerifyTooling() bool {
+	if x != nil {
+		return x.VerifyTooling
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *ValidationRequest) GetFailuresOnly() bool {
+	if x != nil {
+		return x.FailuresOnly
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetGitBaseRef() string {
+	if x != nil {
+		return x.GitBaseRef
+	}
+	return ""
+}
+
+func (x *ValidationRequest) GetGitHeadRef() string {
+	if x != nil {
+		return x.GitHeadRef
+	}
+	return ""
+}
+
+func (x *ValidationRequest) GetStreamFlushLines() int32 {
+	if x != nil {
+		return x.StreamFlushLines
+	}
+	return 0
+}
+
+func (x *ValidationRequest) GetStreamFlushIntervalMs() int32 {
+	if x != nil {
+		return x.StreamFlushIntervalMs
+	}
+	return 0
+}
+
+func (x *ValidationRequest) GetEmptyRunPolicy() EmptyRunPolicy {
+	if x != nil {
+		return x.EmptyRunPolicy
+	}
+	return EmptyRunPolicy_EMPTY_RUN_POLICY_UNSPECIFIED
+}
+
+func (x *ValidationRequest) GetArtifactGlobs() []string {
+	if x != nil {
+		return x.ArtifactGlobs
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetCheckToolchain() bool {
+	if x != nil {
+		return x.CheckToolchain
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetStdin() []byte {
+	if x != nil {
+		return x.Stdin
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetStdinFile() string {
+	if x != nil {
+		return x.StdinFile
+	}
+	return ""
+}
+
+func (x *ValidationRequest) GetChattyValidators() []string {
+	if x != nil {
+		return x.ChattyValidators
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetIfNoneMatch() string {
+	if x != nil {
+		return x.IfNoneMatch
+	}
+	return ""
+}
+
+func (x *ValidationRequest) GetStreamProgressIntervalMs() int32 {
+	if x != nil {
+		return x.StreamProgressIntervalMs
+	}
+	return 0
+}
+
+func (x *ValidationRequest) GetForceColor() bool {
+	if x != nil {
+		return x.ForceColor
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetIncludeManifest() bool {
+	if x != nil {
+		return x.IncludeManifest
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetPrefixOutput() bool {
+	if x != nil {
+		return x.PrefixOutput
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetOutputPrefixFormat() string {
+	if x != nil {
+		return x.OutputPrefixFormat
+	}
+	return ""
+}
+
+func (x *ValidationRequest) GetContentionKeys() map[string]string {
+	if x != nil {
+		return x.ContentionKeys
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetMaxParallelValidators() int32 {
+	if x != nil {
+		return x.MaxParallelValidators
+	}
+	return 0
+}
+
+func (x *ValidationRequest) GetUseCache() bool {
+	if x != nil {
+		return x.UseCache
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ValidationRequest) GetRetryExitCodes() []int32 {
+	if x != nil {
+		return x.RetryExitCodes
+	}
+	return nil
+}
+
+func (x *ValidationRequest) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *ValidationRequest) GetValidatorTimeoutsMs() map[string]int32 {
+	if x != nil {
+		return x.ValidatorTimeoutsMs
+	}
+	return nil
+}
+
+// Explicit argument vector for a command, executed without shell-style tokenization
+type CommandArgv struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectType   string                 `protobuf:"bytes,1,opt,name=project_type,json=projectType,proto3" json:"project_type,omitempty"`                                                  // Detected project type (npm, cargo, make, etc.)
-	ProjectRoot   string                 `protobuf:"bytes,2,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"`                                                  // Root directory
-	ConfigFiles   []string               `protobuf:"bytes,3,rep,name=config_files,json=configFiles,proto3" json:"config_files,omitempty"`                                                  // Configuration files found
-	Commands      map[string]string      `protobuf:"bytes,4,rep,name=commands,proto3" json:"commands,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Available commands (lint, test, build)
-	Language      string                 `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`                                                                           // Primary programming language
+	Args          []string               `protobuf:"bytes,1,rep,name=args,proto3" json:"args,omitempty"` // Program followed by its arguments
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProjectMetadata) Reset() {
-	*x = ProjectMetadata{}
+func (x *CommandArgv) Reset() {
+	*x = CommandArgv{}
 	mi := &file_proto_cc_tools_integration_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ProjectMetadata) String() string {
+func (x *CommandArgv) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandArgv) ProtoMessage() {}
+
+func (x *CommandArgv) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandArgv.ProtoReflect.Descriptor instead.
+func (*CommandArgv) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CommandArgv) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+// Project metadata message
+type ProjectMetadata struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ProjectType      string                 `protobuf:"bytes,1,opt,name=project_type,json=projectType,proto3" json:"project_type,omitempty"`                                                                                           // Detected project type (bazel, composer, deno, npm, cargo, mix, zig, gomod, python, maven, gradle, cmake, make, unknown)
+	ProjectRoot      string                 `protobuf:"bytes,2,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"`                                                                                           // Root directory
+	ConfigFiles      []string               `protobuf:"bytes,3,rep,name=config_files,json=configFiles,proto3" json:"config_files,omitempty"`                                                                                           // Configuration files found
+	Commands         map[string]string      `protobuf:"bytes,4,rep,name=commands,proto3" json:"commands,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`                                          // Available commands; ValidateProject runs format, lint, typecheck, configure, build and test when present, then any other command a .devflow config adds (e.g. coverage); configure runs alone first
+	Language         string                 `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`                                                                                                                    // Primary programming language
+	MarkerDir        string                 `protobuf:"bytes,6,opt,name=marker_dir,json=markerDir,proto3" json:"marker_dir,omitempty"`                                                                                                 // Directory holding the marker file, relative to project_root ("." for the root)
+	CommandAvailable map[string]bool        `protobuf:"bytes,7,rep,name=command_available,json=commandAvailable,proto3" json:"command_available,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // Whether each command's program exists on this node (only with verify_tooling)
+	Etag             string                 `protobuf:"bytes,8,opt,name=etag,proto3" json:"etag,omitempty"`                                                                                                                            // Fingerprint of the metadata and config files, for if_none_match
+	NotModified      bool                   `protobuf:"varint,9,opt,name=not_modified,json=notModified,proto3" json:"not_modified,omitempty"`                                                                                          // Matched if_none_match; all other fields are empty
+	Warnings         []string               `protobuf:"bytes,10,rep,name=warnings,proto3" json:"warnings,omitempty"`                                                                                                                   // Errors met during detection; the classification is best-effort when set
+	ProjectName      string                 `protobuf:"bytes,11,opt,name=project_name,json=projectName,proto3" json:"project_name,omitempty"`                                                                                          // Name declared in the manifest (package.json name, Cargo.toml package.name, mix.exs app, go.mod module, pyproject.toml name, pom.xml artifactId, Gradle rootProject.name, composer.json name)
+	Binaries         []string               `protobuf:"bytes,12,rep,name=binaries,proto3" json:"binaries,omitempty"`                                                                                                                   // Executables the project builds or installs (npm bin, cargo bin targets, Go main packages, Python console scripts)
+	ModulePath       string                 `protobuf:"bytes,13,opt,name=module_path,json=modulePath,proto3" json:"module_path,omitempty"`                                                                                             // Go module path from the module line of go.mod (gomod only)
+	PackageManager   string                 `protobuf:"bytes,14,opt,name=package_manager,json=packageManager,proto3" json:"package_manager,omitempty"`                                                                                 // npm only: pnpm, yarn or npm, from the lockfile present (pnpm-lock.yaml > yarn.lock > package-lock.json; npm without one)
+	Fingerprint      string                 `protobuf:"bytes,15,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`                                                                                                             // Hash of the contents of config_files; unchanged while no config file is edited, so cached metadata can be reused
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ProjectMetadata) Reset() {
+	*x = ProjectMetadata{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectMetadata) ProtoMessage() {}
+
+func (x *ProjectMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectMetadata.ProtoReflect.Descriptor instead.
+func (*ProjectMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProjectMetadata) GetProjectType() string {
+	if x != nil {
+		return x.ProjectType
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetProjectRoot() string {
+	if x != nil {
+		return x.ProjectRoot
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetConfigFiles() []string {
+	if x != nil {
+		return x.ConfigFiles
+	}
+	return nil
+}
+
+func (x *ProjectMetadata) GetCommands() map[string]string {
+	if x != nil {
+		return x.Commands
+	}
+	return nil
+}
+
+func (x *ProjectMetadata) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetMarkerDir() string {
+	if x != nil {
+		return x.MarkerDir
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetCommandAvailable() map[string]bool {
+	if x != nil {
+		return x.CommandAvailable
+	}
+	return nil
+}
+
+func (x *ProjectMetadata) GetEtag() string {
+	if x != nil {
+		return x.Etag
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetNotModified() bool {
+	if x != nil {
+		return x.NotModified
+	}
+	return false
+}
+
+func (x *ProjectMetadata) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ProjectMetadata) GetProjectName() string {
+	if x != nil {
+		return x.ProjectName
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetBinaries() []string {
+	if x != nil {
+		return x.Binaries
+	}
+	return nil
+}
+
+func (x *ProjectMetadata) GetModulePath() string {
+	if x != nil {
+		return x.ModulePath
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetPackageManager() string {
+	if x != nil {
+		return x.PackageManager
+	}
+	return ""
+}
+
+func (x *ProjectMetadata) GetFingerprint() string {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return ""
+}
+
+// Lock status message
+type LockStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LockId        string                 `protobuf:"bytes,1,opt,name=lock_id,json=lockId,proto3" json:"lock_id,omitempty"`                 // Unique lock identifier
+	ProjectPath   string                 `protobuf:"bytes,2,opt,name=project_path,json=projectPath,proto3" json:"project_path,omitempty"`  // Path being locked
+	ProcessId     int32                  `protobuf:"varint,3,opt,name=process_id,json=processId,proto3" json:"process_id,omitempty"`       // Process ID holding the lock
+	AcquiredAt    int64                  `protobuf:"varint,4,opt,name=acquired_at,json=acquiredAt,proto3" json:"acquired_at,omitempty"`    // Timestamp when lock was acquired
+	IsLocked      bool                   `protobuf:"varint,5,opt,name=is_locked,json=isLocked,proto3" json:"is_locked,omitempty"`          // Current lock status
+	Owner         string                 `protobuf:"bytes,6,opt,name=owner,proto3" json:"owner,omitempty"`                                 // Human-readable lock holder
+	Namespace     string                 `protobuf:"bytes,7,opt,name=namespace,proto3" json:"namespace,omitempty"`                         // Namespace the lock lives in (empty = default)
+	ExpiresAt     int64                  `protobuf:"varint,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`       // Unix time the lock expires (0 = no expiry)
+	HolderAlive   bool                   `protobuf:"varint,9,opt,name=holder_alive,json=holderAlive,proto3" json:"holder_alive,omitempty"` // ListLocks: whether the holding process is still running
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockStatus) Reset() {
+	*x = LockStatus{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockStatus) ProtoMessage() {}
+
+func (x *LockStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockStatus.ProtoReflect.Descriptor instead.
+func (*LockStatus) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LockStatus) GetLockId() string {
+	if x != nil {
+		return x.LockId
+	}
+	return ""
+}
+
+func (x *LockStatus) GetProjectPath() string {
+	if x != nil {
+		return x.ProjectPath
+	}
+	return ""
+}
+
+func (x *LockStatus) GetProcessId() int32 {
+	if x != nil {
+		return x.ProcessId
+	}
+	return 0
+}
+
+func (x *LockStatus) GetAcquiredAt() int64 {
+	if x != nil {
+		return x.AcquiredAt
+	}
+	return 0
+}
+
+func (x *LockStatus) GetIsLocked() bool {
+	if x != nil {
+		return x.IsLocked
+	}
+	return false
+}
+
+func (x *LockStatus) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *LockStatus) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *LockStatus) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *LockStatus) GetHolderAlive() bool {
+	if x != nil {
+		return x.HolderAlive
+	}
+	return false
+}
+
+// Request to list held locks
+type ListLocksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`                               // Namespace to list; defaults like LockRequest.namespace
+	AllNamespaces bool                   `protobuf:"varint,2,opt,name=all_namespaces,json=allNamespaces,proto3" json:"all_namespaces,omitempty"` // List every namespace (admin only, requires ADMIN_TOKEN)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocksRequest) Reset() {
+	*x = ListLocksRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocksRequest) ProtoMessage() {}
+
+func (x *ListLocksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocksRequest.ProtoReflect.Descriptor instead.
+func (*ListLocksRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListLocksRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListLocksRequest) GetAllNamespaces() bool {
+	if x != nil {
+		return x.AllNamespaces
+	}
+	return false
+}
+
+// Locks held through this server, sorted by lock_id
+type ListLocksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Locks         []*LockStatus          `protobuf:"bytes,1,rep,name=locks,proto3" json:"locks,omitempty"` // Includes dead or expired holders, with is_locked false
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLocksResponse) Reset() {
+	*x = ListLocksResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLocksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLocksResponse) ProtoMessage() {}
+
+func (x *ListLocksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLocksResponse.ProtoReflect.Descriptor instead.
+func (*ListLocksResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListLocksResponse) GetLocks() []*LockStatus {
+	if x != nil {
+		return x.Locks
+	}
+	return nil
+}
+
+// Aggregate counts over all validators in a run
+type ValidationSummary struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Total          int32                  `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`                                         // Validators considered
+	Passed         int32                  `protobuf:"varint,2,opt,name=passed,proto3" json:"passed,omitempty"`                                       // Executed and succeeded
+	Failed         int32                  `protobuf:"varint,3,opt,name=failed,proto3" json:"failed,omitempty"`                                       // Executed and failed
+	Skipped        int32                  `protobuf:"varint,4,opt,name=skipped,proto3" json:"skipped,omitempty"`                                     // Not executed (see skip_reason)
+	MaxConcurrency int32                  `protobuf:"varint,5,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"` // Most of this run's validators running at once
+	QueueWaitMs    int64                  `protobuf:"varint,6,opt,name=queue_wait_ms,json=queueWaitMs,proto3" json:"queue_wait_ms,omitempty"`        // Total time validators waited for a slot under MAX_CONCURRENT_VALIDATORS
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ValidationSummary) Reset() {
+	*x = ValidationSummary{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationSummary) ProtoMessage() {}
+
+func (x *ValidationSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationSummary.ProtoReflect.Descriptor instead.
+func (*ValidationSummary) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ValidationSummary) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetPassed() int32 {
+	if x != nil {
+		return x.Passed
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetFailed() int32 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+func (x *ValidationSummary) GetQueueWaitMs() int64 {
+	if x != nil {
+		return x.QueueWaitMs
+	}
+	return 0
+}
+
+// Validation response message
+type ValidationResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`                                          // Overall validation success
+	Results         []*ValidationResult    `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`                                           // Individual validation results: toolchain-version, format, build, lint, typecheck, test, then others by name
+	Metadata        *ProjectMetadata       `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`                                         // Project metadata
+	ExecutionTimeMs int64                  `protobuf:"varint,4,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"` // Total execution time
+	ErrorMessage    string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`             // Error message if failed
+	Summary         *ValidationSummary     `protobuf:"bytes,6,opt,name=summary,proto3" json:"summary,omitempty"`                                           // Counts over all validators
+	ChangedFiles    []string               `protobuf:"bytes,7,rep,name=changed_files,json=changedFiles,proto3" json:"changed_files,omitempty"`             // Files changed between git_base_ref and git_head_ref
+	Warnings        []string               `protobuf:"bytes,8,rep,name=warnings,proto3" json:"warnings,omitempty"`                                         // Non-fatal problems with the run
+	Manifest        *RunManifest           `protobuf:"bytes,9,opt,name=manifest,proto3" json:"manifest,omitempty"`                                         // How the run was executed (only with include_manifest)
+	ErrorCode       string                 `protobuf:"bytes,10,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`                     // gRPC status code name when a batch entry could not be run at all (e.g. INVALID_ARGUMENT)
+	CacheHit        bool                   `protobuf:"varint,11,opt,name=cache_hit,json=cacheHit,proto3" json:"cache_hit,omitempty"`                       // Served from the result cache (use_cache); results are those of the earlier run
+	RunId           string                 `protobuf:"bytes,12,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`                                 // Id of the run, also sent up front in the x-run-id response header for CancelValidation
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ValidationResponse) Reset() {
+	*x = ValidationResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationResponse) ProtoMessage() {}
+
+func (x *ValidationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationResponse.ProtoReflect.Descriptor instead.
+func (*ValidationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ValidationResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ValidationResponse) GetResults() []*ValidationResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *ValidationResponse) GetMetadata() *ProjectMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ValidationResponse) GetExecutionTimeMs() int64 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+func (x *ValidationResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *ValidationResponse) GetSummary() *ValidationSummary {
+	if x != nil {
+		return x.Summary
+	}
+	return nil
+}
+
+func (x *ValidationResponse) GetChangedFiles() []string {
+	if x != nil {
+		return x.ChangedFiles
+	}
+	return nil
+}
+
+func (x *ValidationResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+func (x *ValidationResponse) GetManifest() *RunManifest {
+	if x != nil {
+		return x.Manifest
+	}
+	return nil
+}
+
+func (x *ValidationResponse) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *ValidationResponse) GetCacheHit() bool {
+	if x != nil {
+		return x.CacheHit
+	}
+	return false
+}
+
+func (x *ValidationResponse) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+// Everything needed to reproduce a validation run elsewhere
+type RunManifest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ProjectRoot       string                 `protobuf:"bytes,1,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"`                                                                                             // Project validated
+	ProjectType       string                 `protobuf:"bytes,2,opt,name=project_type,json=projectType,proto3" json:"project_type,omitempty"`                                                                                             // Detected project type
+	Validators        []*ValidatorDefinition `protobuf:"bytes,3,rep,name=validators,proto3" json:"validators,omitempty"`                                                                                                                  // Resolved validators in execution order (secrets redacted)
+	ToolchainVersions map[string]string      `protobuf:"bytes,4,rep,name=toolchain_versions,json=toolchainVersions,proto3" json:"toolchain_versions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // First line of `<program> --version` per validator program ("" if it did not answer)
+	Host              *HostInfo              `protobuf:"bytes,5,opt,name=host,proto3" json:"host,omitempty"`                                                                                                                              // Machine the run executed on
+	StartedAt         int64                  `protobuf:"varint,6,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`                                                                                                  // Unix time the validators started
+	FilePaths         []string               `protobuf:"bytes,7,rep,name=file_paths,json=filePaths,proto3" json:"file_paths,omitempty"`                                                                                                   // Changed files passed to validators, including the git range
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RunManifest) Reset() {
+	*x = RunManifest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunManifest) ProtoMessage() {}
+
+func (x *RunManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunManifest.ProtoReflect.Descriptor instead.
+func (*RunManifest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RunManifest) GetProjectRoot() string {
+	if x != nil {
+		return x.ProjectRoot
+	}
+	return ""
+}
+
+func (x *RunManifest) GetProjectType() string {
+	if x != nil {
+		return x.ProjectType
+	}
+	return ""
+}
+
+func (x *RunManifest) GetValidators() []*ValidatorDefinition {
+	if x != nil {
+		return x.Validators
+	}
+	return nil
+}
+
+func (x *RunManifest) GetToolchainVersions() map[string]string {
+	if x != nil {
+		return x.ToolchainVersions
+	}
+	return nil
+}
+
+func (x *RunManifest) GetHost() *HostInfo {
+	if x != nil {
+		return x.Host
+	}
+	return nil
+}
+
+func (x *RunManifest) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *RunManifest) GetFilePaths() []string {
+	if x != nil {
+		return x.FilePaths
+	}
+	return nil
+}
+
+// The machine a run executed on
+type HostInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hostname      string                 `protobuf:"bytes,1,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Os            string                 `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`     // GOOS
+	Arch          string                 `protobuf:"bytes,3,opt,name=arch,proto3" json:"arch,omitempty"` // GOARCH
+	NumCpu        int32                  `protobuf:"varint,4,opt,name=num_cpu,json=numCpu,proto3" json:"num_cpu,omitempty"`
+	GoVersion     string                 `protobuf:"bytes,5,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"` // Go runtime the server was built with
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostInfo) Reset() {
+	*x = HostInfo{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostInfo) ProtoMessage() {}
+
+func (x *HostInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostInfo.ProtoReflect.Descriptor instead.
+func (*HostInfo) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *HostInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *HostInfo) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *HostInfo) GetArch() string {
+	if x != nil {
+		return x.Arch
+	}
+	return ""
+}
+
+func (x *HostInfo) GetNumCpu() int32 {
+	if x != nil {
+		return x.NumCpu
+	}
+	return 0
+}
+
+func (x *HostInfo) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+// Individual validation result
+type ValidationResult struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Validator          string                 `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`                                                                                              // Name of the validator (lint, test, etc.)
+	Success            bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`                                                                                                 // Validation success (true for skipped validators, except UNCHANGED ones, which keep the cached verdict)
+	Output             string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`                                                                                                    // Command output: stdout and stderr interleaved as read (order across the two streams is approximate)
+	Error              string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                                                                                                      // Error message if failed
+	ExecutionTimeMs    int64                  `protobuf:"varint,5,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"`                                                        // Execution time for this validator
+	Skipped            bool                   `protobuf:"varint,6,opt,name=skipped,proto3" json:"skipped,omitempty"`                                                                                                 // Validator did not execute; see skip_reason
+	SkipReason         SkipReason             `protobuf:"varint,7,opt,name=skip_reason,json=skipReason,proto3,enum=cc_tools_integration.SkipReason" json:"skip_reason,omitempty"`                                    // Why the validator was skipped
+	FailureReason      FailureReason          `protobuf:"varint,8,opt,name=failure_reason,json=failureReason,proto3,enum=cc_tools_integration.FailureReason" json:"failure_reason,omitempty"`                        // Why the validator failed, when known
+	ResolvedArgv       []string               `protobuf:"bytes,9,rep,name=resolved_argv,json=resolvedArgv,proto3" json:"resolved_argv,omitempty"`                                                                    // Argument vector that was executed
+	CommandForm        string                 `protobuf:"bytes,10,opt,name=command_form,json=commandForm,proto3" json:"command_form,omitempty"`                                                                      // How the command was specified: "string" or "argv"
+	AttemptCount       int32                  `protobuf:"varint,11,opt,name=attempt_count,json=attemptCount,proto3" json:"attempt_count,omitempty"`                                                                  // Attempts made, including retries
+	Transient          bool                   `protobuf:"varint,12,opt,name=transient,proto3" json:"transient,omitempty"`                                                                                            // Failure matched the transient classification (network, lock contention, OOM kill, timeout, retry_exit_codes)
+	StartFailed        bool                   `protobuf:"varint,13,opt,name=start_failed,json=startFailed,proto3" json:"start_failed,omitempty"`                                                                     // The command never ran (as opposed to running and exiting nonzero)
+	StartFailureReason StartFailureReason     `protobuf:"varint,14,opt,name=start_failure_reason,json=startFailureReason,proto3,enum=cc_tools_integration.StartFailureReason" json:"start_failure_reason,omitempty"` // Why the command could not be started
+	Artifacts          []*Artifact            `protobuf:"bytes,15,rep,name=artifacts,proto3" json:"artifacts,omitempty"`                                                                                             // Files matching artifact_globs written while the validator ran
+	ExitCode           int32                  `protobuf:"varint,16,opt,name=exit_code,json=exitCode,proto3" json:"exit_code,omitempty"`                                                                              // Exit status of the last attempt; -1 when it did not exit normally (not started, killed)
+	Stdout             string                 `protobuf:"bytes,17,opt,name=stdout,proto3" json:"stdout,omitempty"`                                                                                                   // Standard output of the last attempt alone
+	Stderr             string                 `protobuf:"bytes,18,opt,name=stderr,proto3" json:"stderr,omitempty"`                                                                                                   // Standard error of the last attempt alone
+	Truncated          bool                   `protobuf:"varint,19,opt,name=truncated,proto3" json:"truncated,omitempty"`                                                                                            // output, stdout or stderr exceeded MAX_OUTPUT_BYTES and kept only its tail
+	DryRun             bool                   `protobuf:"varint,20,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`                                                                                    // Planned by a dry run and not executed; resolved_argv and work_dir show what would run
+	WorkDir            string                 `protobuf:"bytes,21,opt,name=work_dir,json=workDir,proto3" json:"work_dir,omitempty"`                                                                                  // Directory the command runs (or would run) in
+	Diagnostics        []*Diagnostic          `protobuf:"bytes,22,rep,name=diagnostics,proto3" json:"diagnostics,omitempty"`                                                                                         // Findings parsed from the output when the tool and its output format are recognized (ESLint --format json, cargo --message-format=json, go vet and go test); output is kept as-is
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ValidationResult) Reset() {
+	*x = ValidationResult{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationResult) ProtoMessage() {}
+
+func (x *ValidationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationResult.ProtoReflect.Descriptor instead.
+func (*ValidationResult) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ValidationResult) GetValidator() string {
+	if x != nil {
+		return x.Validator
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ValidationResult) GetOutput() string {
+	if x != nil {
+		return x.Output
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetExecutionTimeMs() int64 {
+	if x != nil {
+		return x.ExecutionTimeMs
+	}
+	return 0
+}
+
+func (x *ValidationResult) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+func (x *ValidationResult) GetSkipReason() SkipReason {
+	if x != nil {
+		return x.SkipReason
+	}
+	return SkipReason_SKIP_REASON_UNSPECIFIED
+}
+
+func (x *ValidationResult) GetFailureReason() FailureReason {
+	if x != nil {
+		return x.FailureReason
+	}
+	return FailureReason_FAILURE_REASON_UNSPECIFIED
+}
+
+func (x *ValidationResult) GetResolvedArgv() []string {
+	if x != nil {
+		return x.ResolvedArgv
+	}
+	return nil
+}
+
+func (x *ValidationResult) GetCommandForm() string {
+	if x != nil {
+		return x.CommandForm
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetAttemptCount() int32 {
+	if x != nil {
+		return x.AttemptCount
+	}
+	return 0
+}
+
+func (x *ValidationResult) GetTransient() bool {
+	if x != nil {
+		return x.Transient
+	}
+	return false
+}
+
+func (x *ValidationResult) GetStartFailed() bool {
+	if x != nil {
+		return x.StartFailed
+	}
+	return false
+}
+
+func (x *ValidationResult) GetStartFailureReason() StartFailureReason {
+	if x != nil {
+		return x.StartFailureReason
+	}
+	return StartFailureReason_START_FAILURE_REASON_UNSPECIFIED
+}
+
+func (x *ValidationResult) GetArtifacts() []*Artifact {
+	if x != nil {
+		return x.Artifacts
+	}
+	return nil
+}
+
+func (x *ValidationResult) GetExitCode() int32 {
+	if x != nil {
+		return x.ExitCode
+	}
+	return 0
+}
+
+func (x *ValidationResult) GetStdout() string {
+	if x != nil {
+		return x.Stdout
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetStderr() string {
+	if x != nil {
+		return x.Stderr
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *ValidationResult) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ValidationResult) GetWorkDir() string {
+	if x != nil {
+		return x.WorkDir
+	}
+	return ""
+}
+
+func (x *ValidationResult) GetDiagnostics() []*Diagnostic {
+	if x != nil {
+		return x.Diagnostics
+	}
+	return nil
+}
+
+// A finding reported by a validator, parsed from its output
+type Diagnostic struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          string                 `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`         // As the tool reports it; relative paths are relative to work_dir, except go test failures, which name the file within its package
+	Line          int32                  `protobuf:"varint,2,opt,name=line,proto3" json:"line,omitempty"`        // 1-based; 0 when not reported
+	Column        int32                  `protobuf:"varint,3,opt,name=column,proto3" json:"column,omitempty"`    // 1-based; 0 when not reported
+	Severity      string                 `protobuf:"bytes,4,opt,name=severity,proto3" json:"severity,omitempty"` // error, warning or info
+	Message       string                 `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`   // The tool's message
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Diagnostic) Reset() {
+	*x = Diagnostic{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Diagnostic) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Diagnostic) ProtoMessage() {}
+
+func (x *Diagnostic) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Diagnostic.ProtoReflect.Descriptor instead.
+func (*Diagnostic) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Diagnostic) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *Diagnostic) GetSeverity() string {
+	if x != nil {
+		return x.Severity
+	}
+	return ""
+}
+
+func (x *Diagnostic) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// A file produced by a validator (JUnit XML, coverage report, ...)
+type Artifact struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Path           string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`                                            // Relative to project_root, slash-separated
+	SizeBytes      int64                  `protobuf:"varint,2,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`                // File size
+	Content        []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                                      // File content, unless omitted
+	ContentOmitted bool                   `protobuf:"varint,4,opt,name=content_omitted,json=contentOmitted,proto3" json:"content_omitted,omitempty"` // Over the per-file or per-validator size limit
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Artifact) Reset() {
+	*x = Artifact{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Artifact) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Artifact) ProtoMessage() {}
+
+func (x *Artifact) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Artifact.ProtoReflect.Descriptor instead.
+func (*Artifact) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Artifact) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *Artifact) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Artifact) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Artifact) GetContentOmitted() bool {
+	if x != nil {
+		return x.ContentOmitted
+	}
+	return false
+}
+
+// Lock request message
+type LockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectPath   string                 `protobuf:"bytes,1,opt,name=project_path,json=projectPath,proto3" json:"project_path,omitempty"`     // Path to lock
+	TimeoutMs     int32                  `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`          // AcquireLock: how long to wait for a held lock (0 = return the holder immediately)
+	ForceRelease  bool                   `protobuf:"varint,3,opt,name=force_release,json=forceRelease,proto3" json:"force_release,omitempty"` // Force release if locked by dead process
+	Owner         string                 `protobuf:"bytes,4,opt,name=owner,proto3" json:"owner,omitempty"`                                    // Who is taking the lock (recorded in .devflow.lock); derived when empty
+	Namespace     string                 `protobuf:"bytes,5,opt,name=namespace,proto3" json:"namespace,omitempty"`                            // Isolates tenants; defaults to the caller's TLS identity, then LOCK_NAMESPACE
+	Hostname      string                 `protobuf:"bytes,6,opt,name=hostname,proto3" json:"hostname,omitempty"`                              // Caller's hostname, used as the owner when none is given (see LOCK_OWNER_SOURCES)
+	TtlSeconds    int32                  `protobuf:"varint,7,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`       // AcquireLock: the lock expires this long after it is taken, even if its holder lives (0 = never)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockRequest) Reset() {
+	*x = LockRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockRequest) ProtoMessage() {}
+
+func (x *LockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockRequest.ProtoReflect.Descriptor instead.
+func (*LockRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LockRequest) GetProjectPath() string {
+	if x != nil {
+		return x.ProjectPath
+	}
+	return ""
+}
+
+func (x *LockRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *LockRequest) GetForceRelease() bool {
+	if x != nil {
+		return x.ForceRelease
+	}
+	return false
+}
+
+func (x *LockRequest) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *LockRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *LockRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *LockRequest) GetTtlSeconds() int32 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+// Lock, validate and unlock in one call
+type LockAndValidateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lock          *LockRequest           `protobuf:"bytes,1,opt,name=lock,proto3" json:"lock,omitempty"`             // Lock to hold while validating; project_path defaults to validation.project_root
+	Validation    *ValidationRequest     `protobuf:"bytes,2,opt,name=validation,proto3" json:"validation,omitempty"` // Validation to run under the lock
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockAndValidateRequest) Reset() {
+	*x = LockAndValidateRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockAndValidateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockAndValidateRequest) ProtoMessage() {}
+
+func (x *LockAndValidateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockAndValidateRequest.ProtoReflect.Descriptor instead.
+func (*LockAndValidateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *LockAndValidateRequest) GetLock() *LockRequest {
+	if x != nil {
+		return x.Lock
+	}
+	return nil
+}
+
+func (x *LockAndValidateRequest) GetValidation() *ValidationRequest {
+	if x != nil {
+		return x.Validation
+	}
+	return nil
+}
+
+// Outcome of LockAndValidate
+type LockAndValidateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Lock          *LockStatus            `protobuf:"bytes,1,opt,name=lock,proto3" json:"lock,omitempty"`             // The lock taken, or the current holder when not acquired
+	Acquired      bool                   `protobuf:"varint,2,opt,name=acquired,proto3" json:"acquired,omitempty"`    // Whether the lock was taken; validators only run when true
+	Validation    *ValidationResponse    `protobuf:"bytes,3,opt,name=validation,proto3" json:"validation,omitempty"` // Validation result (unset when not acquired)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LockAndValidateResponse) Reset() {
+	*x = LockAndValidateResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LockAndValidateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LockAndValidateResponse) ProtoMessage() {}
+
+func (x *LockAndValidateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LockAndValidateResponse.ProtoReflect.Descriptor instead.
+func (*LockAndValidateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LockAndValidateResponse) GetLock() *LockStatus {
+	if x != nil {
+		return x.Lock
+	}
+	return nil
+}
+
+func (x *LockAndValidateResponse) GetAcquired() bool {
+	if x != nil {
+		return x.Acquired
+	}
+	return false
+}
+
+func (x *LockAndValidateResponse) GetValidation() *ValidationResponse {
+	if x != nil {
+		return x.Validation
+	}
+	return nil
+}
+
+// Batch request covering several projects
+type BatchValidationRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Requests       []*ValidationRequest   `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`                                    // One entry per project
+	MaxConcurrency int32                  `protobuf:"varint,2,opt,name=max_concurrency,json=maxConcurrency,proto3" json:"max_concurrency,omitempty"` // Entries processed in parallel (0 = NumCPU, clamped to the server max)
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *BatchValidationRequest) Reset() {
+	*x = BatchValidationRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchValidationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchValidationRequest) ProtoMessage() {}
+
+func (x *BatchValidationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchValidationRequest.ProtoReflect.Descriptor instead.
+func (*BatchValidationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BatchValidationRequest) GetRequests() []*ValidationRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *BatchValidationRequest) GetMaxConcurrency() int32 {
+	if x != nil {
+		return x.MaxConcurrency
+	}
+	return 0
+}
+
+// Batch validation response message
+type BatchValidationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Responses     []*ValidationResponse  `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"` // Responses in request order; unfinished entries carry an error_message
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchValidationResponse) Reset() {
+	*x = BatchValidationResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchValidationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchValidationResponse) ProtoMessage() {}
+
+func (x *BatchValidationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchValidationResponse.ProtoReflect.Descriptor instead.
+func (*BatchValidationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BatchValidationResponse) GetResponses() []*ValidationResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+// Metadata detection outcome for a single batch entry
+type ProjectMetadataResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Metadata      *ProjectMetadata       `protobuf:"bytes,1,opt,name=metadata,proto3" json:"metadata,omitempty"` // Detected metadata (unset on error)
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`       // Error message if detection failed or did not finish in time
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProjectMetadataResult) Reset() {
+	*x = ProjectMetadataResult{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectMetadataResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectMetadataResult) ProtoMessage() {}
+
+func (x *ProjectMetadataResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectMetadataResult.ProtoReflect.Descriptor instead.
+func (*ProjectMetadataResult) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ProjectMetadataResult) GetMetadata() *ProjectMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *ProjectMetadataResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Batch metadata response message
+type ProjectMetadataBatchResponse struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	Results       []*ProjectMetadataResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"` // Results in request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProjectMetadataBatchResponse) Reset() {
+	*x = ProjectMetadataBatchResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectMetadataBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectMetadataBatchResponse) ProtoMessage() {}
+
+func (x *ProjectMetadataBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectMetadataBatchResponse.ProtoReflect.Descriptor instead.
+func (*ProjectMetadataBatchResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ProjectMetadataBatchResponse) GetResults() []*ProjectMetadataResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Request to find the projects nested under a root
+type DiscoverSubProjectsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectRoot   string                 `protobuf:"bytes,1,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"` // Tree to search
+	MaxDepth      int32                  `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`         // Directory levels below project_root to search (0 or above the cap = SUBPROJECT_MAX_DEPTH, 4)
+	MaxResults    int32                  `protobuf:"varint,3,opt,name=max_results,json=maxResults,proto3" json:"max_results,omitempty"`   // Stop after this many projects (0 or above the cap = SUBPROJECT_MAX_RESULTS, 200)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverSubProjectsRequest) Reset() {
+	*x = DiscoverSubProjectsRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverSubProjectsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverSubProjectsRequest) ProtoMessage() {}
+
+func (x *DiscoverSubProjectsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverSubProjectsRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverSubProjectsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DiscoverSubProjectsRequest) GetProjectRoot() string {
+	if x != nil {
+		return x.ProjectRoot
+	}
+	return ""
+}
+
+func (x *DiscoverSubProjectsRequest) GetMaxDepth() int32 {
+	if x != nil {
+		return x.MaxDepth
+	}
+	return 0
+}
+
+func (x *DiscoverSubProjectsRequest) GetMaxResults() int32 {
+	if x != nil {
+		return x.MaxResults
+	}
+	return 0
+}
+
+// A project found by DiscoverSubProjects
+type SubProject struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Path          string                 `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`         // Directory relative to project_root, slash-separated ("." for the root)
+	Metadata      *ProjectMetadata       `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"` // Detection run with the directory as project_root
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubProject) Reset() {
+	*x = SubProject{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubProject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubProject) ProtoMessage() {}
+
+func (x *SubProject) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubProject.ProtoReflect.Descriptor instead.
+func (*SubProject) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SubProject) GetPath() string {
+	if x != nil {
+		return x.Path
+	}
+	return ""
+}
+
+func (x *SubProject) GetMetadata() *ProjectMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// Response for DiscoverSubProjects
+type DiscoverSubProjectsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Projects      []*SubProject          `protobuf:"bytes,1,rep,name=projects,proto3" json:"projects,omitempty"`    // Breadth-first: shallower directories first, then lexical order
+	Truncated     bool                   `protobuf:"varint,2,opt,name=truncated,proto3" json:"truncated,omitempty"` // max_results was reached before the search finished
+	Warnings      []string               `protobuf:"bytes,3,rep,name=warnings,proto3" json:"warnings,omitempty"`    // Directories that could not be read
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverSubProjectsResponse) Reset() {
+	*x = DiscoverSubProjectsResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverSubProjectsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverSubProjectsResponse) ProtoMessage() {}
+
+func (x *DiscoverSubProjectsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverSubProjectsResponse.ProtoReflect.Descriptor instead.
+func (*DiscoverSubProjectsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *DiscoverSubProjectsResponse) GetProjects() []*SubProject {
+	if x != nil {
+		return x.Projects
+	}
+	return nil
+}
+
+func (x *DiscoverSubProjectsResponse) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *DiscoverSubProjectsResponse) GetWarnings() []string {
+	if x != nil {
+		return x.Warnings
+	}
+	return nil
+}
+
+// Admin request to abort every running validation
+type AbortAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"` // Operator note, recorded in the server log
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbortAllRequest) Reset() {
+	*x = AbortAllRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbortAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbortAllRequest) ProtoMessage() {}
+
+func (x *AbortAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbortAllRequest.ProtoReflect.Descriptor instead.
+func (*AbortAllRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AbortAllRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Admin response for AbortAll
+type AbortAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Aborted       int32                  `protobuf:"varint,1,opt,name=aborted,proto3" json:"aborted,omitempty"` // Number of validation runs aborted
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AbortAllResponse) Reset() {
+	*x = AbortAllResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AbortAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AbortAllResponse) ProtoMessage() {}
+
+func (x *AbortAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AbortAllResponse.ProtoReflect.Descriptor instead.
+func (*AbortAllResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AbortAllResponse) GetAborted() int32 {
+	if x != nil {
+		return x.Aborted
+	}
+	return 0
+}
+
+// Admin request to cancel the validations of one project
+type CancelValidationsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectRoot   string                 `protobuf:"bytes,1,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"` // Project whose runs are cancelled; compared as a cleaned absolute path
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`                              // Operator note, recorded in the server log
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`               // Only list the matching runs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelValidationsRequest) Reset() {
+	*x = CancelValidationsRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelValidationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelValidationsRequest) ProtoMessage() {}
+
+func (x *CancelValidationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelValidationsRequest.ProtoReflect.Descriptor instead.
+func (*CancelValidationsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CancelValidationsRequest) GetProjectRoot() string {
+	if x != nil {
+		return x.ProjectRoot
+	}
+	return ""
+}
+
+func (x *CancelValidationsRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelValidationsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+// Admin response for CancelValidationsByProject
+type CancelValidationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobIds        []string               `protobuf:"bytes,1,rep,name=job_ids,json=jobIds,proto3" json:"job_ids,omitempty"` // Runs cancelled (or, with dry_run, that would be)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelValidationsResponse) Reset() {
+	*x = CancelValidationsResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelValidationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelValidationsResponse) ProtoMessage() {}
+
+func (x *CancelValidationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelValidationsResponse.ProtoReflect.Descriptor instead.
+func (*CancelValidationsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CancelValidationsResponse) GetJobIds() []string {
+	if x != nil {
+		return x.JobIds
+	}
+	return nil
+}
+
+// Request to cancel one running validation
+type CancelValidationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"` // From the x-run-id header, ValidationResponse.run_id or ValidationEvent.run_id
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`            // Note recorded in the server log
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelValidationRequest) Reset() {
+	*x = CancelValidationRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelValidationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelValidationRequest) ProtoMessage() {}
+
+func (x *CancelValidationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelValidationRequest.ProtoReflect.Descriptor instead.
+func (*CancelValidationRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CancelValidationRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *CancelValidationRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+// Response for CancelValidation
+type CancelValidationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProjectRoot   string                 `protobuf:"bytes,1,opt,name=project_root,json=projectRoot,proto3" json:"project_root,omitempty"` // Project the cancelled run was validating
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelValidationResponse) Reset() {
+	*x = CancelValidationResponse{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelValidationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelValidationResponse) ProtoMessage() {}
+
+func (x *CancelValidationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelValidationResponse.ProtoReflect.Descriptor instead.
+func (*CancelValidationResponse) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CancelValidationResponse) GetProjectRoot() string {
+	if x != nil {
+		return x.ProjectRoot
+	}
+	return ""
+}
+
+// Event sent by StreamValidation
+type ValidationEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Validator     string                 `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`                            // Validator the event belongs to (empty on the final event)
+	Lines         []string               `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`                                    // Output lines produced since the previous event
+	Result        *ValidationResult      `protobuf:"bytes,3,opt,name=result,proto3" json:"result,omitempty"`                                  // Set once the validator has finished
+	Response      *ValidationResponse    `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`                              // Set on the final event: the full response, as ValidateProject returns it
+	Progress      *Progress              `protobuf:"bytes,5,opt,name=progress,proto3" json:"progress,omitempty"`                              // Set periodically while the validator runs
+	RunId         string                 `protobuf:"bytes,6,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`                       // Run the event belongs to; names the run for GetValidationLog
+	DroppedLines  int64                  `protobuf:"varint,7,opt,name=dropped_lines,json=droppedLines,proto3" json:"dropped_lines,omitempty"` // Output lines not streamed since the previous event because the client fell behind (see GetValidationLog)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationEvent) Reset() {
+	*x = ValidationEvent{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationEvent) ProtoMessage() {}
+
+func (x *ValidationEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationEvent.ProtoReflect.Descriptor instead.
+func (*ValidationEvent) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ValidationEvent) GetValidator() string {
+	if x != nil {
+		return x.Validator
+	}
+	return ""
+}
+
+func (x *ValidationEvent) GetLines() []string {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *ValidationEvent) GetResult() *ValidationResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *ValidationEvent) GetResponse() *ValidationResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *ValidationEvent) GetProgress() *Progress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *ValidationEvent) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ValidationEvent) GetDroppedLines() int64 {
+	if x != nil {
+		return x.DroppedLines
+	}
+	return 0
+}
+
+// Request for the persisted output of one validator of a streamed run
+type ValidationLogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`           // run_id from the run's ValidationEvents
+	Validator     string                 `protobuf:"bytes,2,opt,name=validator,proto3" json:"validator,omitempty"`                // Validator whose output is returned
+	Offset        int64                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`                     // Byte offset to start reading at
+	MaxBytes      int64                  `protobuf:"varint,4,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"` // Bytes to return at most (0 = 1 MiB)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationLogRequest) Reset() {
+	*x = ValidationLogRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationLogRequest) ProtoMessage() {}
+
+func (x *ValidationLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationLogRequest.ProtoReflect.Descriptor instead.
+func (*ValidationLogRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ValidationLogRequest) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ValidationLogRequest) GetValidator() string {
+	if x != nil {
+		return x.Validator
+	}
+	return ""
+}
+
+func (x *ValidationLogRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ValidationLogRequest) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+// Persisted output of one validator, exactly as the validator wrote it
+type ValidationLog struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RunId         string                 `protobuf:"bytes,1,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	Validator     string                 `protobuf:"bytes,2,opt,name=validator,proto3" json:"validator,omitempty"`
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`                          // Output from offset, up to max_bytes
+	TotalBytes    int64                  `protobuf:"varint,4,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"` // Size of the whole log so far; it grows while the validator runs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValidationLog) Reset() {
+	*x = ValidationLog{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidationLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidationLog) ProtoMessage() {}
+
+func (x *ValidationLog) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidationLog.ProtoReflect.Descriptor instead.
+func (*ValidationLog) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ValidationLog) GetRunId() string {
+	if x != nil {
+		return x.RunId
+	}
+	return ""
+}
+
+func (x *ValidationLog) GetValidator() string {
+	if x != nil {
+		return x.Validator
+	}
+	return ""
+}
+
+func (x *ValidationLog) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ValidationLog) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+// How far along a running validator is
+type Progress struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ElapsedMs       int64                  `protobuf:"varint,1,opt,name=elapsed_ms,json=elapsedMs,proto3" json:"elapsed_ms,omitempty"`                   // Time since the validator started
+	TypicalMs       int64                  `protobuf:"varint,2,opt,name=typical_ms,json=typicalMs,proto3" json:"typical_ms,omitempty"`                   // Median duration of recent runs for this project (0 = no history)
+	PercentEstimate int32                  `protobuf:"varint,3,opt,name=percent_estimate,json=percentEstimate,proto3" json:"percent_estimate,omitempty"` // elapsed_ms against typical_ms, capped at 99 (0 when typical_ms is 0)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Progress) Reset() {
+	*x = Progress{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Progress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Progress) ProtoMessage() {}
+
+func (x *Progress) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Progress.ProtoReflect.Descriptor instead.
+func (*Progress) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Progress) GetElapsedMs() int64 {
+	if x != nil {
+		return x.ElapsedMs
+	}
+	return 0
+}
+
+func (x *Progress) GetTypicalMs() int64 {
+	if x != nil {
+		return x.TypicalMs
+	}
+	return 0
+}
+
+func (x *Progress) GetPercentEstimate() int32 {
+	if x != nil {
+		return x.PercentEstimate
+	}
+	return 0
+}
+
+// Request for the supported project types
+type SupportedProjectTypesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SupportedProjectTypesRequest) Reset() {
+	*x = SupportedProjectTypesRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SupportedProjectTypesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SupportedProjectTypesRequest) ProtoMessage() {}
+
+func (x *SupportedProjectTypesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SupportedProjectTypesRequest.ProtoReflect.Descriptor instead.
+func (*SupportedProjectTypesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{33}
+}
+
+// One project type the server can detect
+type ProjectTypeDescriptor struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ProjectType     string                 `protobuf:"bytes,1,opt,name=project_type,json=projectType,proto3" json:"project_type,omitempty"`                                                                                       // Name reported in ProjectMetadata.project_type
+	Language        string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`                                                                                                                // Primary language (empty for make)
+	ConfigFiles     []string               `protobuf:"bytes,3,rep,name=config_files,json=configFiles,proto3" json:"config_files,omitempty"`                                                                                       // Marker files that identify the type, in the order they are tried
+	DefaultCommands map[string]string      `protobuf:"bytes,4,rep,name=default_commands,json=defaultCommands,proto3" json:"default_commands,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Commands before refinement from lockfiles and tool configs (e.g. pnpm, ruff vs flake8, gradlew)
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ProjectTypeDescriptor) Reset() {
+	*x = ProjectTypeDescriptor{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectTypeDescriptor) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProjectMetadata) ProtoMessage() {}
+func (*ProjectTypeDescriptor) ProtoMessage() {}
 
-func (x *ProjectMetadata) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[1]
+func (x *ProjectTypeDescriptor) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -135,73 +2993,64 @@ func (x *ProjectMetadata) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProjectMetadata.ProtoReflect.Descriptor instead.
-func (*ProjectMetadata) Descriptor() ([]byte, []int) {
-	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use ProjectTypeDescriptor.ProtoReflect.Descriptor instead.
+func (*ProjectTypeDescriptor) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{34}
 }
 
-func (x *ProjectMetadata) GetProjectType() string {
+func (x *ProjectTypeDescriptor) GetProjectType() string {
 	if x != nil {
 		return x.ProjectType
 	}
 	return ""
 }
 
-func (x *ProjectMetadata) GetProjectRoot() string {
+func (x *ProjectTypeDescriptor) GetLanguage() string {
 	if x != nil {
-		return x.ProjectRoot
+		return x.Language
 	}
 	return ""
 }
 
-func (x *ProjectMetadata) GetConfigFiles() []string {
+func (x *ProjectTypeDescriptor) GetConfigFiles() []string {
 	if x != nil {
 		return x.ConfigFiles
 	}
 	return nil
 }
 
-func (x *ProjectMetadata) GetCommands() map[string]string {
+func (x *ProjectTypeDescriptor) GetDefaultCommands() map[string]string {
 	if x != nil {
-		return x.Commands
+		return x.DefaultCommands
 	}
 	return nil
 }
 
-func (x *ProjectMetadata) GetLanguage() string {
-	if x != nil {
-		return x.Language
-	}
-	return ""
-}
-
-// Lock status message
-type LockStatus struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LockId        string                 `protobuf:"bytes,1,opt,name=lock_id,json=lockId,proto3" json:"lock_id,omitempty"`                // Unique lock identifier
-	ProjectPath   string                 `protobuf:"bytes,2,opt,name=project_path,json=projectPath,proto3" json:"project_path,omitempty"` // Path being locked
-	ProcessId     int32                  `protobuf:"varint,3,opt,name=process_id,json=processId,proto3" json:"process_id,omitempty"`      // Process ID holding the lock
-	AcquiredAt    int64                  `protobuf:"varint,4,opt,name=acquired_at,json=acquiredAt,proto3" json:"acquired_at,omitempty"`   // Timestamp when lock was acquired
-	IsLocked      bool                   `protobuf:"varint,5,opt,name=is_locked,json=isLocked,proto3" json:"is_locked,omitempty"`         // Current lock status
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Project types the server can detect
+type SupportedProjectTypes struct {
+	state           protoimpl.MessageState   `protogen:"open.v1"`
+	ProjectTypes    []string                 `protobuf:"bytes,1,rep,name=project_types,json=projectTypes,proto3" json:"project_types,omitempty"`          // In detection order, excluding DISABLED_DETECTORS
+	Types           []*ProjectTypeDescriptor `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`                                            // Same order, with the markers and defaults of each
+	ValidatorStages []string                 `protobuf:"bytes,3,rep,name=validator_stages,json=validatorStages,proto3" json:"validator_stages,omitempty"` // Commands ValidateProject runs when a project has them, in order
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *LockStatus) Reset() {
-	*x = LockStatus{}
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[2]
+func (x *SupportedProjectTypes) Reset() {
+	*x = SupportedProjectTypes{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LockStatus) String() string {
+func (x *SupportedProjectTypes) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LockStatus) ProtoMessage() {}
+func (*SupportedProjectTypes) ProtoMessage() {}
 
-func (x *LockStatus) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[2]
+func (x *SupportedProjectTypes) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -212,73 +3061,97 @@ func (x *LockStatus) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LockStatus.ProtoReflect.Descriptor instead.
-func (*LockStatus) Descriptor() ([]byte, []int) {
-	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use SupportedProjectTypes.ProtoReflect.Descriptor instead.
+func (*SupportedProjectTypes) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *LockStatus) GetLockId() string {
+func (x *SupportedProjectTypes) GetProjectTypes() []string {
 	if x != nil {
-		return x.LockId
+		return x.ProjectTypes
 	}
-	return ""
+	return nil
 }
 
-func (x *LockStatus) GetProjectPath() string {
+func (x *SupportedProjectTypes) GetTypes() []*ProjectTypeDescriptor {
 	if x != nil {
-		return x.ProjectPath
+		return x.Types
 	}
-	return ""
+	return nil
 }
 
-func (x *LockStatus) GetProcessId() int32 {
+func (x *SupportedProjectTypes) GetValidatorStages() []string {
 	if x != nil {
-		return x.ProcessId
+		return x.ValidatorStages
 	}
-	return 0
+	return nil
 }
 
-func (x *LockStatus) GetAcquiredAt() int64 {
-	if x != nil {
-		return x.AcquiredAt
-	}
-	return 0
+// Request for server statistics
+type StatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LockStatus) GetIsLocked() bool {
+func (x *StatsRequest) Reset() {
+	*x = StatsRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatsRequest) ProtoMessage() {}
+
+func (x *StatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[36]
 	if x != nil {
-		return x.IsLocked
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-// Validation response message
-type ValidationResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`                                          // Overall validation success
-	Results         []*ValidationResult    `protobuf:"bytes,2,rep,name=results,proto3" json:"results,omitempty"`                                           // Individual validation results
-	Metadata        *ProjectMetadata       `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`                                         // Project metadata
-	ExecutionTimeMs int64                  `protobuf:"varint,4,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"` // Total execution time
-	ErrorMessage    string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`             // Error message if failed
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+// Deprecated: Use StatsRequest.ProtoReflect.Descriptor instead.
+func (*StatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *ValidationResponse) Reset() {
-	*x = ValidationResponse{}
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[3]
+// Lock contention for one project
+type LockContention struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	ProjectPath       string                 `protobuf:"bytes,1,opt,name=project_path,json=projectPath,proto3" json:"project_path,omitempty"`                      // Project, or "_other" once the tracked limit is reached
+	Contended         int64                  `protobuf:"varint,2,opt,name=contended,proto3" json:"contended,omitempty"`                                            // AcquireLock calls that found the lock held
+	AcquiredAfterWait int64                  `protobuf:"varint,3,opt,name=acquired_after_wait,json=acquiredAfterWait,proto3" json:"acquired_after_wait,omitempty"` // Of those, calls that waited and then got the lock
+	GaveUp            int64                  `protobuf:"varint,4,opt,name=gave_up,json=gaveUp,proto3" json:"gave_up,omitempty"`                                    // Of those, calls that timed out, were cancelled or did not wait
+	TotalWaitMs       int64                  `protobuf:"varint,5,opt,name=total_wait_ms,json=totalWaitMs,proto3" json:"total_wait_ms,omitempty"`                   // Time spent waiting across all calls
+	MaxWaitMs         int64                  `protobuf:"varint,6,opt,name=max_wait_ms,json=maxWaitMs,proto3" json:"max_wait_ms,omitempty"`                         // Longest single wait
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *LockContention) Reset() {
+	*x = LockContention{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidationResponse) String() string {
+func (x *LockContention) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidationResponse) ProtoMessage() {}
+func (*LockContention) ProtoMessage() {}
 
-func (x *ValidationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[3]
+func (x *LockContention) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -289,73 +3162,76 @@ func (x *ValidationResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidationResponse.ProtoReflect.Descriptor instead.
-func (*ValidationResponse) Descriptor() ([]byte, []int) {
-	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use LockContention.ProtoReflect.Descriptor instead.
+func (*LockContention) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{37}
 }
 
-func (x *ValidationResponse) GetSuccess() bool {
+func (x *LockContention) GetProjectPath() string {
 	if x != nil {
-		return x.Success
+		return x.ProjectPath
 	}
-	return false
+	return ""
 }
 
-func (x *ValidationResponse) GetResults() []*ValidationResult {
+func (x *LockContention) GetContended() int64 {
 	if x != nil {
-		return x.Results
+		return x.Contended
 	}
-	return nil
+	return 0
 }
 
-func (x *ValidationResponse) GetMetadata() *ProjectMetadata {
+func (x *LockContention) GetAcquiredAfterWait() int64 {
 	if x != nil {
-		return x.Metadata
+		return x.AcquiredAfterWait
 	}
-	return nil
+	return 0
 }
 
-func (x *ValidationResponse) GetExecutionTimeMs() int64 {
+func (x *LockContention) GetGaveUp() int64 {
 	if x != nil {
-		return x.ExecutionTimeMs
+		return x.GaveUp
 	}
 	return 0
 }
 
-func (x *ValidationResponse) GetErrorMessage() string {
+func (x *LockContention) GetTotalWaitMs() int64 {
 	if x != nil {
-		return x.ErrorMessage
+		return x.TotalWaitMs
 	}
-	return ""
+	return 0
 }
 
-// Individual validation result
-type ValidationResult struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	Validator       string                 `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`                                       // Name of the validator (lint, test, etc.)
-	Success         bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`                                          // Validation success
-	Output          string                 `protobuf:"bytes,3,opt,name=output,proto3" json:"output,omitempty"`                                             // Command output
-	Error           string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`                                               // Error message if failed
-	ExecutionTimeMs int64                  `protobuf:"varint,5,opt,name=execution_time_ms,json=executionTimeMs,proto3" json:"execution_time_ms,omitempty"` // Execution time for this validator
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *LockContention) GetMaxWaitMs() int64 {
+	if x != nil {
+		return x.MaxWaitMs
+	}
+	return 0
 }
 
-func (x *ValidationResult) Reset() {
-	*x = ValidationResult{}
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[4]
+// Server statistics
+type ServerStats struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	LockContention []*LockContention      `protobuf:"bytes,1,rep,name=lock_contention,json=lockContention,proto3" json:"lock_contention,omitempty"` // Most waited-on projects first
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ServerStats) Reset() {
+	*x = ServerStats{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ValidationResult) String() string {
+func (x *ServerStats) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ValidationResult) ProtoMessage() {}
+func (*ServerStats) ProtoMessage() {}
 
-func (x *ValidationResult) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[4]
+func (x *ServerStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -366,71 +3242,103 @@ func (x *ValidationResult) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ValidationResult.ProtoReflect.Descriptor instead.
-func (*ValidationResult) Descriptor() ([]byte, []int) {
-	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use ServerStats.ProtoReflect.Descriptor instead.
+func (*ServerStats) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{38}
 }
 
-func (x *ValidationResult) GetValidator() string {
+func (x *ServerStats) GetLockContention() []*LockContention {
 	if x != nil {
-		return x.Validator
+		return x.LockContention
 	}
-	return ""
+	return nil
 }
 
-func (x *ValidationResult) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Request for a single validator's resolved definition
+type ValidatorDefinitionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       *ValidationRequest     `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`     // Project and options, as they would be sent to ValidateProject
+	Validator     string                 `protobuf:"bytes,2,opt,name=validator,proto3" json:"validator,omitempty"` // Validator name (lint, test, etc.)
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ValidationResult) GetOutput() string {
+func (x *ValidatorDefinitionRequest) Reset() {
+	*x = ValidatorDefinitionRequest{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidatorDefinitionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidatorDefinitionRequest) ProtoMessage() {}
+
+func (x *ValidatorDefinitionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[39]
 	if x != nil {
-		return x.Output
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *ValidationResult) GetError() string {
+// Deprecated: Use ValidatorDefinitionRequest.ProtoReflect.Descriptor instead.
+func (*ValidatorDefinitionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *ValidatorDefinitionRequest) GetRequest() *ValidationRequest {
 	if x != nil {
-		return x.Error
+		return x.Request
 	}
-	return ""
+	return nil
 }
 
-func (x *ValidationResult) GetExecutionTimeMs() int64 {
+func (x *ValidatorDefinitionRequest) GetValidator() string {
 	if x != nil {
-		return x.ExecutionTimeMs
+		return x.Validator
 	}
-	return 0
+	return ""
 }
 
-// Lock request message
-type LockRequest struct {
+// Fully resolved specification of a validator, exactly as it would run
+type ValidatorDefinition struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProjectPath   string                 `protobuf:"bytes,1,opt,name=project_path,json=projectPath,proto3" json:"project_path,omitempty"`     // Path to lock
-	TimeoutMs     int32                  `protobuf:"varint,2,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`          // Lock timeout
-	ForceRelease  bool                   `protobuf:"varint,3,opt,name=force_release,json=forceRelease,proto3" json:"force_release,omitempty"` // Force release if locked by dead process
+	Validator     string                 `protobuf:"bytes,1,opt,name=validator,proto3" json:"validator,omitempty"`                                                               // Validator name
+	ProjectType   string                 `protobuf:"bytes,2,opt,name=project_type,json=projectType,proto3" json:"project_type,omitempty"`                                        // Detected project type
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`                                                                   // Command string after detection
+	Argv          []string               `protobuf:"bytes,4,rep,name=argv,proto3" json:"argv,omitempty"`                                                                         // Argument vector that will be executed
+	WorkingDir    string                 `protobuf:"bytes,5,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`                                           // Directory the command runs in
+	TimeoutMs     int64                  `protobuf:"varint,6,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`                                             // Effective timeout
+	LoginShell    bool                   `protobuf:"varint,7,opt,name=login_shell,json=loginShell,proto3" json:"login_shell,omitempty"`                                          // Runs through `bash -lc`
+	Env           map[string]string      `protobuf:"bytes,8,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Variables set on top of the server environment (secrets redacted)
+	CommandForm   string                 `protobuf:"bytes,9,opt,name=command_form,json=commandForm,proto3" json:"command_form,omitempty"`                                        // How the command was specified: "string" or "argv"
+	Retries       int32                  `protobuf:"varint,10,opt,name=retries,proto3" json:"retries,omitempty"`                                                                 // Re-runs allowed for transient failures
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LockRequest) Reset() {
-	*x = LockRequest{}
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[5]
+func (x *ValidatorDefinition) Reset() {
+	*x = ValidatorDefinition{}
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LockRequest) String() string {
+func (x *ValidatorDefinition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LockRequest) ProtoMessage() {}
+func (*ValidatorDefinition) ProtoMessage() {}
 
-func (x *LockRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_cc_tools_integration_proto_msgTypes[5]
+func (x *ValidatorDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_cc_tools_integration_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -441,37 +3349,86 @@ func (x *LockRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LockRequest.ProtoReflect.Descriptor instead.
-func (*LockRequest) Descriptor() ([]byte, []int) {
-	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ValidatorDefinition.ProtoReflect.Descriptor instead.
+func (*ValidatorDefinition) Descriptor() ([]byte, []int) {
+	return file_proto_cc_tools_integration_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *LockRequest) GetProjectPath() string {
+func (x *ValidatorDefinition) GetValidator() string {
 	if x != nil {
-		return x.ProjectPath
+		return x.Validator
 	}
 	return ""
 }
 
-func (x *LockRequest) GetTimeoutMs() int32 {
+func (x *ValidatorDefinition) GetProjectType() string {
+	if x != nil {
+		return x.ProjectType
+	}
+	return ""
+}
+
+func (x *ValidatorDefinition) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *ValidatorDefinition) GetArgv() []string {
+	if x != nil {
+		return x.Argv
+	}
+	return nil
+}
+
+func (x *ValidatorDefinition) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *ValidatorDefinition) GetTimeoutMs() int64 {
 	if x != nil {
 		return x.TimeoutMs
 	}
 	return 0
 }
 
-func (x *LockRequest) GetForceRelease() bool {
+func (x *ValidatorDefinition) GetLoginShell() bool {
 	if x != nil {
-		return x.ForceRelease
+		return x.LoginShell
 	}
 	return false
 }
 
+func (x *ValidatorDefinition) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ValidatorDefinition) GetCommandForm() string {
+	if x != nil {
+		return x.CommandForm
+	}
+	return ""
+}
+
+func (x *ValidatorDefinition) GetRetries() int32 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
 var File_proto_cc_tools_integration_proto protoreflect.FileDescriptor
 
 const file_proto_cc_tools_integration_proto_rawDesc = "" +
 	"\n" +
-	" proto/cc_tools_integration.proto\x12\x14cc_tools_integration\"\x9d\x02\n" +
+	" proto/cc_tools_integration.proto\x12\x14cc_tools_integration\"\xcf\x11\n" +
 	"\x11ValidationRequest\x12!\n" +
 	"\fproject_root\x18\x01 \x01(\tR\vprojectRoot\x12\x1b\n" +
 	"\thook_type\x18\x02 \x01(\tR\bhookType\x12\x1d\n" +
@@ -479,19 +3436,91 @@ const file_proto_cc_tools_integration_proto_rawDesc = "" +
 	"file_paths\x18\x03 \x03(\tR\tfilePaths\x12N\n" +
 	"\acontext\x18\x04 \x03(\v24.cc_tools_integration.ValidationRequest.ContextEntryR\acontext\x12\x1d\n" +
 	"\n" +
-	"timeout_ms\x18\x05 \x01(\x05R\ttimeoutMs\x1a:\n" +
+	"timeout_ms\x18\x05 \x01(\x05R\ttimeoutMs\x12\x1f\n" +
+	"\vlogin_shell\x18\x06 \x01(\bR\n" +
+	"loginShell\x124\n" +
+	"\x16login_shell_validators\x18\a \x03(\tR\x14loginShellValidators\x12/\n" +
+	"\x14max_duration_hint_ms\x18\b \x01(\x03R\x11maxDurationHintMs\x12j\n" +
+	"\x11override_commands\x18\t \x03(\v2=.cc_tools_integration.ValidationRequest.OverrideCommandsEntryR\x10overrideCommands\x12^\n" +
+	"\roverride_argv\x18\n" +
+	" \x03(\v29.cc_tools_integration.ValidationRequest.OverrideArgvEntryR\foverrideArgv\x12%\n" +
+	"\x0everify_tooling\x18\v \x01(\bR\rverifyTooling\x12\x18\n" +
+	"\aretries\x18\f \x01(\x05R\aretries\x12#\n" +
+	"\rfailures_only\x18\r \x01(\bR\ffailuresOnly\x12B\n" +
+	"\x03env\x18\x0e \x03(\v20.cc_tools_integration.ValidationRequest.EnvEntryR\x03env\x12 \n" +
+	"\fgit_base_ref\x18\x0f \x01(\tR\n" +
+	"gitBaseRef\x12 \n" +
+	"\fgit_head_ref\x18\x10 \x01(\tR\n" +
+	"gitHeadRef\x12,\n" +
+	"\x12stream_flush_lines\x18\x11 \x01(\x05R\x10streamFlushLines\x127\n" +
+	"\x18stream_flush_interval_ms\x18\x12 \x01(\x05R\x15streamFlushIntervalMs\x12N\n" +
+	"\x10empty_run_policy\x18\x13 \x01(\x0e2$.cc_tools_integration.EmptyRunPolicyR\x0eemptyRunPolicy\x12%\n" +
+	"\x0eartifact_globs\x18\x14 \x03(\tR\rartifactGlobs\x12'\n" +
+	"\x0fcheck_toolchain\x18\x15 \x01(\bR\x0echeckToolchain\x12\x14\n" +
+	"\x05stdin\x18\x16 \x01(\fR\x05stdin\x12\x1d\n" +
+	"\n" +
+	"stdin_file\x18\x17 \x01(\tR\tstdinFile\x12+\n" +
+	"\x11chatty_validators\x18\x18 \x03(\tR\x10chattyValidators\x12\"\n" +
+	"\rif_none_match\x18\x19 \x01(\tR\vifNoneMatch\x12=\n" +
+	"\x1bstream_progress_interval_ms\x18\x1a \x01(\x05R\x18streamProgressIntervalMs\x12\x1f\n" +
+	"\vforce_color\x18\x1b \x01(\bR\n" +
+	"forceColor\x12)\n" +
+	"\x10include_manifest\x18\x1c \x01(\bR\x0fincludeManifest\x12#\n" +
+	"\rprefix_output\x18\x1d \x01(\bR\fprefixOutput\x120\n" +
+	"\x14output_prefix_format\x18\x1e \x01(\tR\x12outputPrefixFormat\x12d\n" +
+	"\x0fcontention_keys\x18\x1f \x03(\v2;.cc_tools_integration.ValidationRequest.ContentionKeysEntryR\x0econtentionKeys\x126\n" +
+	"\x17max_parallel_validators\x18  \x01(\x05R\x15maxParallelValidators\x12\x1b\n" +
+	"\tuse_cache\x18! \x01(\bR\buseCache\x12\x17\n" +
+	"\adry_run\x18\" \x01(\bR\x06dryRun\x12(\n" +
+	"\x10retry_exit_codes\x18# \x03(\x05R\x0eretryExitCodes\x12\x1f\n" +
+	"\vworking_dir\x18$ \x01(\tR\n" +
+	"workingDir\x12t\n" +
+	"\x15validator_timeouts_ms\x18% \x03(\v2@.cc_tools_integration.ValidationRequest.ValidatorTimeoutsMsEntryR\x13validatorTimeoutsMs\x1a:\n" +
 	"\fContextEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa4\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15OverrideCommandsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1ab\n" +
+	"\x11OverrideArgvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x127\n" +
+	"\x05value\x18\x02 \x01(\v2!.cc_tools_integration.CommandArgvR\x05value:\x028\x01\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aA\n" +
+	"\x13ContentionKeysEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aF\n" +
+	"\x18ValidatorTimeoutsMsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\"!\n" +
+	"\vCommandArgv\x12\x12\n" +
+	"\x04args\x18\x01 \x03(\tR\x04args\"\xf0\x05\n" +
 	"\x0fProjectMetadata\x12!\n" +
 	"\fproject_type\x18\x01 \x01(\tR\vprojectType\x12!\n" +
 	"\fproject_root\x18\x02 \x01(\tR\vprojectRoot\x12!\n" +
 	"\fconfig_files\x18\x03 \x03(\tR\vconfigFiles\x12O\n" +
 	"\bcommands\x18\x04 \x03(\v23.cc_tools_integration.ProjectMetadata.CommandsEntryR\bcommands\x12\x1a\n" +
-	"\blanguage\x18\x05 \x01(\tR\blanguage\x1a;\n" +
+	"\blanguage\x18\x05 \x01(\tR\blanguage\x12\x1d\n" +
+	"\n" +
+	"marker_dir\x18\x06 \x01(\tR\tmarkerDir\x12h\n" +
+	"\x11command_available\x18\a \x03(\v2;.cc_tools_integration.ProjectMetadata.CommandAvailableEntryR\x10commandAvailable\x12\x12\n" +
+	"\x04etag\x18\b \x01(\tR\x04etag\x12!\n" +
+	"\fnot_modified\x18\t \x01(\bR\vnotModified\x12\x1a\n" +
+	"\bwarnings\x18\n" +
+	" \x03(\tR\bwarnings\x12!\n" +
+	"\fproject_name\x18\v \x01(\tR\vprojectName\x12\x1a\n" +
+	"\bbinaries\x18\f \x03(\tR\bbinaries\x12\x1f\n" +
+	"\vmodule_path\x18\r \x01(\tR\n" +
+	"modulePath\x12'\n" +
+	"\x0fpackage_manager\x18\x0e \x01(\tR\x0epackageManager\x12 \n" +
+	"\vfingerprint\x18\x0f \x01(\tR\vfingerprint\x1a;\n" +
 	"\rCommandsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa5\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15CommandAvailableEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\bR\x05value:\x028\x01\"\x9b\x02\n" +
 	"\n" +
 	"LockStatus\x12\x17\n" +
 	"\alock_id\x18\x01 \x01(\tR\x06lockId\x12!\n" +
@@ -500,30 +3529,276 @@ const file_proto_cc_tools_integration_proto_rawDesc = "" +
 	"process_id\x18\x03 \x01(\x05R\tprocessId\x12\x1f\n" +
 	"\vacquired_at\x18\x04 \x01(\x03R\n" +
 	"acquiredAt\x12\x1b\n" +
-	"\tis_locked\x18\x05 \x01(\bR\bisLocked\"\x84\x02\n" +
+	"\tis_locked\x18\x05 \x01(\bR\bisLocked\x12\x14\n" +
+	"\x05owner\x18\x06 \x01(\tR\x05owner\x12\x1c\n" +
+	"\tnamespace\x18\a \x01(\tR\tnamespace\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\x03R\texpiresAt\x12!\n" +
+	"\fholder_alive\x18\t \x01(\bR\vholderAlive\"W\n" +
+	"\x10ListLocksRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12%\n" +
+	"\x0eall_namespaces\x18\x02 \x01(\bR\rallNamespaces\"K\n" +
+	"\x11ListLocksResponse\x126\n" +
+	"\x05locks\x18\x01 \x03(\v2 .cc_tools_integration.LockStatusR\x05locks\"\xc0\x01\n" +
+	"\x11ValidationSummary\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\x12\x16\n" +
+	"\x06passed\x18\x02 \x01(\x05R\x06passed\x12\x16\n" +
+	"\x06failed\x18\x03 \x01(\x05R\x06failed\x12\x18\n" +
+	"\askipped\x18\x04 \x01(\x05R\askipped\x12'\n" +
+	"\x0fmax_concurrency\x18\x05 \x01(\x05R\x0emaxConcurrency\x12\"\n" +
+	"\rqueue_wait_ms\x18\x06 \x01(\x03R\vqueueWaitMs\"\x9a\x04\n" +
 	"\x12ValidationResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12@\n" +
 	"\aresults\x18\x02 \x03(\v2&.cc_tools_integration.ValidationResultR\aresults\x12A\n" +
 	"\bmetadata\x18\x03 \x01(\v2%.cc_tools_integration.ProjectMetadataR\bmetadata\x12*\n" +
 	"\x11execution_time_ms\x18\x04 \x01(\x03R\x0fexecutionTimeMs\x12#\n" +
-	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\"\xa4\x01\n" +
+	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\x12A\n" +
+	"\asummary\x18\x06 \x01(\v2'.cc_tools_integration.ValidationSummaryR\asummary\x12#\n" +
+	"\rchanged_files\x18\a \x03(\tR\fchangedFiles\x12\x1a\n" +
+	"\bwarnings\x18\b \x03(\tR\bwarnings\x12=\n" +
+	"\bmanifest\x18\t \x01(\v2!.cc_tools_integration.RunManifestR\bmanifest\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\n" +
+	" \x01(\tR\terrorCode\x12\x1b\n" +
+	"\tcache_hit\x18\v \x01(\bR\bcacheHit\x12\x15\n" +
+	"\x06run_id\x18\f \x01(\tR\x05runId\"\xbf\x03\n" +
+	"\vRunManifest\x12!\n" +
+	"\fproject_root\x18\x01 \x01(\tR\vprojectRoot\x12!\n" +
+	"\fproject_type\x18\x02 \x01(\tR\vprojectType\x12I\n" +
+	"\n" +
+	"validators\x18\x03 \x03(\v2).cc_tools_integration.ValidatorDefinitionR\n" +
+	"validators\x12g\n" +
+	"\x12toolchain_versions\x18\x04 \x03(\v28.cc_tools_integration.RunManifest.ToolchainVersionsEntryR\x11toolchainVersions\x122\n" +
+	"\x04host\x18\x05 \x01(\v2\x1e.cc_tools_integration.HostInfoR\x04host\x12\x1d\n" +
+	"\n" +
+	"started_at\x18\x06 \x01(\x03R\tstartedAt\x12\x1d\n" +
+	"\n" +
+	"file_paths\x18\a \x03(\tR\tfilePaths\x1aD\n" +
+	"\x16ToolchainVersionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x82\x01\n" +
+	"\bHostInfo\x12\x1a\n" +
+	"\bhostname\x18\x01 \x01(\tR\bhostname\x12\x0e\n" +
+	"\x02os\x18\x02 \x01(\tR\x02os\x12\x12\n" +
+	"\x04arch\x18\x03 \x01(\tR\x04arch\x12\x17\n" +
+	"\anum_cpu\x18\x04 \x01(\x05R\x06numCpu\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x05 \x01(\tR\tgoVersion\"\xf8\x06\n" +
 	"\x10ValidationResult\x12\x1c\n" +
 	"\tvalidator\x18\x01 \x01(\tR\tvalidator\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x16\n" +
 	"\x06output\x18\x03 \x01(\tR\x06output\x12\x14\n" +
 	"\x05error\x18\x04 \x01(\tR\x05error\x12*\n" +
-	"\x11execution_time_ms\x18\x05 \x01(\x03R\x0fexecutionTimeMs\"t\n" +
+	"\x11execution_time_ms\x18\x05 \x01(\x03R\x0fexecutionTimeMs\x12\x18\n" +
+	"\askipped\x18\x06 \x01(\bR\askipped\x12A\n" +
+	"\vskip_reason\x18\a \x01(\x0e2 .cc_tools_integration.SkipReasonR\n" +
+	"skipReason\x12J\n" +
+	"\x0efailure_reason\x18\b \x01(\x0e2#.cc_tools_integration.FailureReasonR\rfailureReason\x12#\n" +
+	"\rresolved_argv\x18\t \x03(\tR\fresolvedArgv\x12!\n" +
+	"\fcommand_form\x18\n" +
+	" \x01(\tR\vcommandForm\x12#\n" +
+	"\rattempt_count\x18\v \x01(\x05R\fattemptCount\x12\x1c\n" +
+	"\ttransient\x18\f \x01(\bR\ttransient\x12!\n" +
+	"\fstart_failed\x18\r \x01(\bR\vstartFailed\x12Z\n" +
+	"\x14start_failure_reason\x18\x0e \x01(\x0e2(.cc_tools_integration.StartFailureReasonR\x12startFailureReason\x12<\n" +
+	"\tartifacts\x18\x0f \x03(\v2\x1e.cc_tools_integration.ArtifactR\tartifacts\x12\x1b\n" +
+	"\texit_code\x18\x10 \x01(\x05R\bexitCode\x12\x16\n" +
+	"\x06stdout\x18\x11 \x01(\tR\x06stdout\x12\x16\n" +
+	"\x06stderr\x18\x12 \x01(\tR\x06stderr\x12\x1c\n" +
+	"\ttruncated\x18\x13 \x01(\bR\ttruncated\x12\x17\n" +
+	"\adry_run\x18\x14 \x01(\bR\x06dryRun\x12\x19\n" +
+	"\bwork_dir\x18\x15 \x01(\tR\aworkDir\x12B\n" +
+	"\vdiagnostics\x18\x16 \x03(\v2 .cc_tools_integration.DiagnosticR\vdiagnostics\"\x82\x01\n" +
+	"\n" +
+	"Diagnostic\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x02 \x01(\x05R\x04line\x12\x16\n" +
+	"\x06column\x18\x03 \x01(\x05R\x06column\x12\x1a\n" +
+	"\bseverity\x18\x04 \x01(\tR\bseverity\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\"\x80\x01\n" +
+	"\bArtifact\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x02 \x01(\x03R\tsizeBytes\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\x12'\n" +
+	"\x0fcontent_omitted\x18\x04 \x01(\bR\x0econtentOmitted\"\xe5\x01\n" +
 	"\vLockRequest\x12!\n" +
 	"\fproject_path\x18\x01 \x01(\tR\vprojectPath\x12\x1d\n" +
 	"\n" +
 	"timeout_ms\x18\x02 \x01(\x05R\ttimeoutMs\x12#\n" +
-	"\rforce_release\x18\x03 \x01(\bR\fforceRelease2\xda\x03\n" +
+	"\rforce_release\x18\x03 \x01(\bR\fforceRelease\x12\x14\n" +
+	"\x05owner\x18\x04 \x01(\tR\x05owner\x12\x1c\n" +
+	"\tnamespace\x18\x05 \x01(\tR\tnamespace\x12\x1a\n" +
+	"\bhostname\x18\x06 \x01(\tR\bhostname\x12\x1f\n" +
+	"\vttl_seconds\x18\a \x01(\x05R\n" +
+	"ttlSeconds\"\x98\x01\n" +
+	"\x16LockAndValidateRequest\x125\n" +
+	"\x04lock\x18\x01 \x01(\v2!.cc_tools_integration.LockRequestR\x04lock\x12G\n" +
+	"\n" +
+	"validation\x18\x02 \x01(\v2'.cc_tools_integration.ValidationRequestR\n" +
+	"validation\"\xb5\x01\n" +
+	"\x17LockAndValidateResponse\x124\n" +
+	"\x04lock\x18\x01 \x01(\v2 .cc_tools_integration.LockStatusR\x04lock\x12\x1a\n" +
+	"\bacquired\x18\x02 \x01(\bR\bacquired\x12H\n" +
+	"\n" +
+	"validation\x18\x03 \x01(\v2(.cc_tools_integration.ValidationResponseR\n" +
+	"validation\"\x86\x01\n" +
+	"\x16BatchValidationRequest\x12C\n" +
+	"\brequests\x18\x01 \x03(\v2'.cc_tools_integration.ValidationRequestR\brequests\x12'\n" +
+	"\x0fmax_concurrency\x18\x02 \x01(\x05R\x0emaxConcurrency\"a\n" +
+	"\x17BatchValidationResponse\x12F\n" +
+	"\tresponses\x18\x01 \x03(\v2(.cc_tools_integration.ValidationResponseR\tresponses\"p\n" +
+	"\x15ProjectMetadataResult\x12A\n" +
+	"\bmetadata\x18\x01 \x01(\v2%.cc_tools_integration.ProjectMetadataR\bmetadata\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"e\n" +
+	"\x1cProjectMetadataBatchResponse\x12E\n" +
+	"\aresults\x18\x01 \x03(\v2+.cc_tools_integration.ProjectMetadataResultR\aresults\"}\n" +
+	"\x1aDiscoverSubProjectsRequest\x12!\n" +
+	"\fproject_root\x18\x01 \x01(\tR\vprojectRoot\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\x12\x1f\n" +
+	"\vmax_results\x18\x03 \x01(\x05R\n" +
+	"maxResults\"c\n" +
+	"\n" +
+	"SubProject\x12\x12\n" +
+	"\x04path\x18\x01 \x01(\tR\x04path\x12A\n" +
+	"\bmetadata\x18\x02 \x01(\v2%.cc_tools_integration.ProjectMetadataR\bmetadata\"\x95\x01\n" +
+	"\x1bDiscoverSubProjectsResponse\x12<\n" +
+	"\bprojects\x18\x01 \x03(\v2 .cc_tools_integration.SubProjectR\bprojects\x12\x1c\n" +
+	"\ttruncated\x18\x02 \x01(\bR\ttruncated\x12\x1a\n" +
+	"\bwarnings\x18\x03 \x03(\tR\bwarnings\")\n" +
+	"\x0fAbortAllRequest\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\",\n" +
+	"\x10AbortAllResponse\x12\x18\n" +
+	"\aaborted\x18\x01 \x01(\x05R\aaborted\"n\n" +
+	"\x18CancelValidationsRequest\x12!\n" +
+	"\fproject_root\x18\x01 \x01(\tR\vprojectRoot\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"4\n" +
+	"\x19CancelValidationsResponse\x12\x17\n" +
+	"\ajob_ids\x18\x01 \x03(\tR\x06jobIds\"H\n" +
+	"\x17CancelValidationRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"=\n" +
+	"\x18CancelValidationResponse\x12!\n" +
+	"\fproject_root\x18\x01 \x01(\tR\vprojectRoot\"\xc3\x02\n" +
+	"\x0fValidationEvent\x12\x1c\n" +
+	"\tvalidator\x18\x01 \x01(\tR\tvalidator\x12\x14\n" +
+	"\x05lines\x18\x02 \x03(\tR\x05lines\x12>\n" +
+	"\x06result\x18\x03 \x01(\v2&.cc_tools_integration.ValidationResultR\x06result\x12D\n" +
+	"\bresponse\x18\x04 \x01(\v2(.cc_tools_integration.ValidationResponseR\bresponse\x12:\n" +
+	"\bprogress\x18\x05 \x01(\v2\x1e.cc_tools_integration.ProgressR\bprogress\x12\x15\n" +
+	"\x06run_id\x18\x06 \x01(\tR\x05runId\x12#\n" +
+	"\rdropped_lines\x18\a \x01(\x03R\fdroppedLines\"\x80\x01\n" +
+	"\x14ValidationLogRequest\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1c\n" +
+	"\tvalidator\x18\x02 \x01(\tR\tvalidator\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x03R\x06offset\x12\x1b\n" +
+	"\tmax_bytes\x18\x04 \x01(\x03R\bmaxBytes\"\x7f\n" +
+	"\rValidationLog\x12\x15\n" +
+	"\x06run_id\x18\x01 \x01(\tR\x05runId\x12\x1c\n" +
+	"\tvalidator\x18\x02 \x01(\tR\tvalidator\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent\x12\x1f\n" +
+	"\vtotal_bytes\x18\x04 \x01(\x03R\n" +
+	"totalBytes\"s\n" +
+	"\bProgress\x12\x1d\n" +
+	"\n" +
+	"elapsed_ms\x18\x01 \x01(\x03R\telapsedMs\x12\x1d\n" +
+	"\n" +
+	"typical_ms\x18\x02 \x01(\x03R\ttypicalMs\x12)\n" +
+	"\x10percent_estimate\x18\x03 \x01(\x05R\x0fpercentEstimate\"\x1e\n" +
+	"\x1cSupportedProjectTypesRequest\"\xaa\x02\n" +
+	"\x15ProjectTypeDescriptor\x12!\n" +
+	"\fproject_type\x18\x01 \x01(\tR\vprojectType\x12\x1a\n" +
+	"\blanguage\x18\x02 \x01(\tR\blanguage\x12!\n" +
+	"\fconfig_files\x18\x03 \x03(\tR\vconfigFiles\x12k\n" +
+	"\x10default_commands\x18\x04 \x03(\v2@.cc_tools_integration.ProjectTypeDescriptor.DefaultCommandsEntryR\x0fdefaultCommands\x1aB\n" +
+	"\x14DefaultCommandsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xaa\x01\n" +
+	"\x15SupportedProjectTypes\x12#\n" +
+	"\rproject_types\x18\x01 \x03(\tR\fprojectTypes\x12A\n" +
+	"\x05types\x18\x02 \x03(\v2+.cc_tools_integration.ProjectTypeDescriptorR\x05types\x12)\n" +
+	"\x10validator_stages\x18\x03 \x03(\tR\x0fvalidatorStages\"\x0e\n" +
+	"\fStatsRequest\"\xde\x01\n" +
+	"\x0eLockContention\x12!\n" +
+	"\fproject_path\x18\x01 \x01(\tR\vprojectPath\x12\x1c\n" +
+	"\tcontended\x18\x02 \x01(\x03R\tcontended\x12.\n" +
+	"\x13acquired_after_wait\x18\x03 \x01(\x03R\x11acquiredAfterWait\x12\x17\n" +
+	"\agave_up\x18\x04 \x01(\x03R\x06gaveUp\x12\"\n" +
+	"\rtotal_wait_ms\x18\x05 \x01(\x03R\vtotalWaitMs\x12\x1e\n" +
+	"\vmax_wait_ms\x18\x06 \x01(\x03R\tmaxWaitMs\"\\\n" +
+	"\vServerStats\x12M\n" +
+	"\x0flock_contention\x18\x01 \x03(\v2$.cc_tools_integration.LockContentionR\x0elockContention\"}\n" +
+	"\x1aValidatorDefinitionRequest\x12A\n" +
+	"\arequest\x18\x01 \x01(\v2'.cc_tools_integration.ValidationRequestR\arequest\x12\x1c\n" +
+	"\tvalidator\x18\x02 \x01(\tR\tvalidator\"\xa0\x03\n" +
+	"\x13ValidatorDefinition\x12\x1c\n" +
+	"\tvalidator\x18\x01 \x01(\tR\tvalidator\x12!\n" +
+	"\fproject_type\x18\x02 \x01(\tR\vprojectType\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x12\n" +
+	"\x04argv\x18\x04 \x03(\tR\x04argv\x12\x1f\n" +
+	"\vworking_dir\x18\x05 \x01(\tR\n" +
+	"workingDir\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\x06 \x01(\x03R\ttimeoutMs\x12\x1f\n" +
+	"\vlogin_shell\x18\a \x01(\bR\n" +
+	"loginShell\x12D\n" +
+	"\x03env\x18\b \x03(\v22.cc_tools_integration.ValidatorDefinition.EnvEntryR\x03env\x12!\n" +
+	"\fcommand_form\x18\t \x01(\tR\vcommandForm\x12\x18\n" +
+	"\aretries\x18\n" +
+	" \x01(\x05R\aretries\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\x87\x01\n" +
+	"\x0eEmptyRunPolicy\x12 \n" +
+	"\x1cEMPTY_RUN_POLICY_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18EMPTY_RUN_POLICY_SUCCESS\x10\x01\x12\x1a\n" +
+	"\x16EMPTY_RUN_POLICY_ERROR\x10\x02\x12\x19\n" +
+	"\x15EMPTY_RUN_POLICY_WARN\x10\x03*\xac\x01\n" +
+	"\n" +
+	"SkipReason\x12\x1b\n" +
+	"\x17SKIP_REASON_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15SKIP_REASON_UNCHANGED\x10\x01\x12\x18\n" +
+	"\x14SKIP_REASON_EXCLUDED\x10\x02\x12\x19\n" +
+	"\x15SKIP_REASON_FAIL_FAST\x10\x03\x12\x17\n" +
+	"\x13SKIP_REASON_DRY_RUN\x10\x04\x12\x18\n" +
+	"\x14SKIP_REASON_TOO_SLOW\x10\x05*\xab\x02\n" +
+	"\rFailureReason\x12\x1e\n" +
+	"\x1aFAILURE_REASON_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16FAILURE_REASON_ABORTED\x10\x01\x12!\n" +
+	"\x1dFAILURE_REASON_RUNAWAY_OUTPUT\x10\x02\x12\x1c\n" +
+	"\x18FAILURE_REASON_CANCELLED\x10\x03\x12\x1a\n" +
+	"\x16FAILURE_REASON_TIMEOUT\x10\x04\x12$\n" +
+	" FAILURE_REASON_COMMAND_NOT_FOUND\x10\x05\x12\x1c\n" +
+	"\x18FAILURE_REASON_EXIT_CODE\x10\x06\x12\x1b\n" +
+	"\x17FAILURE_REASON_SIGNALED\x10\a\x12 \n" +
+	"\x1cFAILURE_REASON_NOT_PERMITTED\x10\b*\xfd\x01\n" +
+	"\x12StartFailureReason\x12$\n" +
+	" START_FAILURE_REASON_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eSTART_FAILURE_REASON_NOT_FOUND\x10\x01\x12*\n" +
+	"&START_FAILURE_REASON_PERMISSION_DENIED\x10\x02\x12'\n" +
+	"#START_FAILURE_REASON_NOT_EXECUTABLE\x10\x03\x12(\n" +
+	"$START_FAILURE_REASON_BAD_WORKING_DIR\x10\x04\x12\x1e\n" +
+	"\x1aSTART_FAILURE_REASON_OTHER\x10\x052\xf1\x0e\n" +
 	"\x12CCToolsIntegration\x12d\n" +
 	"\x0fValidateProject\x12'.cc_tools_integration.ValidationRequest\x1a(.cc_tools_integration.ValidationResponse\x12d\n" +
 	"\x12GetProjectMetadata\x12'.cc_tools_integration.ValidationRequest\x1a%.cc_tools_integration.ProjectMetadata\x12R\n" +
 	"\vAcquireLock\x12!.cc_tools_integration.LockRequest\x1a .cc_tools_integration.LockStatus\x12R\n" +
 	"\vReleaseLock\x12!.cc_tools_integration.LockRequest\x1a .cc_tools_integration.LockStatus\x12P\n" +
-	"\tCheckLock\x12!.cc_tools_integration.LockRequest\x1a .cc_tools_integration.LockStatusB*Z(github.com/devflow/cc-tools-server/protob\x06proto3"
+	"\tCheckLock\x12!.cc_tools_integration.LockRequest\x1a .cc_tools_integration.LockStatus\x12\\\n" +
+	"\tListLocks\x12&.cc_tools_integration.ListLocksRequest\x1a'.cc_tools_integration.ListLocksResponse\x12n\n" +
+	"\x0fLockAndValidate\x12,.cc_tools_integration.LockAndValidateRequest\x1a-.cc_tools_integration.LockAndValidateResponse\x12d\n" +
+	"\x10StreamValidation\x12'.cc_tools_integration.ValidationRequest\x1a%.cc_tools_integration.ValidationEvent0\x01\x12c\n" +
+	"\x10GetValidationLog\x12*.cc_tools_integration.ValidationLogRequest\x1a#.cc_tools_integration.ValidationLog\x12o\n" +
+	"\x10ValidateProjects\x12,.cc_tools_integration.BatchValidationRequest\x1a-.cc_tools_integration.BatchValidationResponse\x12{\n" +
+	"\x17GetProjectMetadataBatch\x12,.cc_tools_integration.BatchValidationRequest\x1a2.cc_tools_integration.ProjectMetadataBatchResponse\x12z\n" +
+	"\x13DiscoverSubProjects\x120.cc_tools_integration.DiscoverSubProjectsRequest\x1a1.cc_tools_integration.DiscoverSubProjectsResponse\x12Y\n" +
+	"\bAbortAll\x12%.cc_tools_integration.AbortAllRequest\x1a&.cc_tools_integration.AbortAllResponse\x12}\n" +
+	"\x1aCancelValidationsByProject\x12..cc_tools_integration.CancelValidationsRequest\x1a/.cc_tools_integration.CancelValidationsResponse\x12q\n" +
+	"\x10CancelValidation\x12-.cc_tools_integration.CancelValidationRequest\x1a..cc_tools_integration.CancelValidationResponse\x12u\n" +
+	"\x16GetValidatorDefinition\x120.cc_tools_integration.ValidatorDefinitionRequest\x1a).cc_tools_integration.ValidatorDefinition\x12{\n" +
+	"\x18GetSupportedProjectTypes\x122.cc_tools_integration.SupportedProjectTypesRequest\x1a+.cc_tools_integration.SupportedProjectTypes\x12Q\n" +
+	"\bGetStats\x12\".cc_tools_integration.StatsRequest\x1a!.cc_tools_integration.ServerStatsB*Z(github.com/devflow/cc-tools-server/protob\x06proto3"
 
 var (
 	file_proto_cc_tools_integration_proto_rawDescOnce sync.Once
@@ -537,37 +3812,149 @@ func file_proto_cc_tools_integration_proto_rawDescGZIP() []byte {
 	return file_proto_cc_tools_integration_proto_rawDescData
 }
 
-var file_proto_cc_tools_integration_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_cc_tools_integration_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_proto_cc_tools_integration_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_proto_cc_tools_integration_proto_goTypes = []any{
-	(*ValidationRequest)(nil),  // 0: cc_tools_integration.ValidationRequest
-	(*ProjectMetadata)(nil),    // 1: cc_tools_integration.ProjectMetadata
-	(*LockStatus)(nil),         // 2: cc_tools_integration.LockStatus
-	(*ValidationResponse)(nil), // 3: cc_tools_integration.ValidationResponse
-	(*ValidationResult)(nil),   // 4: cc_tools_integration.ValidationResult
-	(*LockRequest)(nil),        // 5: cc_tools_integration.LockRequest
-	nil,                        // 6: cc_tools_integration.ValidationRequest.ContextEntry
-	nil,                        // 7: cc_tools_integration.ProjectMetadata.CommandsEntry
+	(EmptyRunPolicy)(0),                  // 0: cc_tools_integration.EmptyRunPolicy
+	(SkipReason)(0),                      // 1: cc_tools_integration.SkipReason
+	(FailureReason)(0),                   // 2: cc_tools_integration.FailureReason
+	(StartFailureReason)(0),              // 3: cc_tools_integration.StartFailureReason
+	(*ValidationRequest)(nil),            // 4: cc_tools_integration.ValidationRequest
+	(*CommandArgv)(nil),                  // 5: cc_tools_integration.CommandArgv
+	(*ProjectMetadata)(nil),              // 6: cc_tools_integration.ProjectMetadata
+	(*LockStatus)(nil),                   // 7: cc_tools_integration.LockStatus
+	(*ListLocksRequest)(nil),             // 8: cc_tools_integration.ListLocksRequest
+	(*ListLocksResponse)(nil),            // 9: cc_tools_integration.ListLocksResponse
+	(*ValidationSummary)(nil),            // 10: cc_tools_integration.ValidationSummary
+	(*ValidationResponse)(nil),           // 11: cc_tools_integration.ValidationResponse
+	(*RunManifest)(nil),                  // 12: cc_tools_integration.RunManifest
+	(*HostInfo)(nil),                     // 13: cc_tools_integration.HostInfo
+	(*ValidationResult)(nil),             // 14: cc_tools_integration.ValidationResult
+	(*Diagnostic)(nil),                   // 15: cc_tools_integration.Diagnostic
+	(*Artifact)(nil),                     // 16: cc_tools_integration.Artifact
+	(*LockRequest)(nil),                  // 17: cc_tools_integration.LockRequest
+	(*LockAndValidateRequest)(nil),       // 18: cc_tools_integration.LockAndValidateRequest
+	(*LockAndValidateResponse)(nil),      // 19: cc_tools_integration.LockAndValidateResponse
+	(*BatchValidationRequest)(nil),       // 20: cc_tools_integration.BatchValidationRequest
+	(*BatchValidationResponse)(nil),      // 21: cc_tools_integration.BatchValidationResponse
+	(*ProjectMetadataResult)(nil),        // 22: cc_tools_integration.ProjectMetadataResult
+	(*ProjectMetadataBatchResponse)(nil), // 23: cc_tools_integration.ProjectMetadataBatchResponse
+	(*DiscoverSubProjectsRequest)(nil),   // 24: cc_tools_integration.DiscoverSubProjectsRequest
+	(*SubProject)(nil),                   // 25: cc_tools_integration.SubProject
+	(*DiscoverSubProjectsResponse)(nil),  // 26: cc_tools_integration.DiscoverSubProjectsResponse
+	(*AbortAllRequest)(nil),              // 27: cc_tools_integration.AbortAllRequest
+	(*AbortAllResponse)(nil),             // 28: cc_tools_integration.AbortAllResponse
+	(*CancelValidationsRequest)(nil),     // 29: cc_tools_integration.CancelValidationsRequest
+	(*CancelValidationsResponse)(nil),    // 30: cc_tools_integration.CancelValidationsResponse
+	(*CancelValidationRequest)(nil),      // 31: cc_tools_integration.CancelValidationRequest
+	(*CancelValidationResponse)(nil),     // 32: cc_tools_integration.CancelValidationResponse
+	(*ValidationEvent)(nil),              // 33: cc_tools_integration.ValidationEvent
+	(*ValidationLogRequest)(nil),         // 34: cc_tools_integration.ValidationLogRequest
+	(*ValidationLog)(nil),                // 35: cc_tools_integration.ValidationLog
+	(*Progress)(nil),                     // 36: cc_tools_integration.Progress
+	(*SupportedProjectTypesRequest)(nil), // 37: cc_tools_integration.SupportedProjectTypesRequest
+	(*ProjectTypeDescriptor)(nil),        // 38: cc_tools_integration.ProjectTypeDescriptor
+	(*SupportedProjectTypes)(nil),        // 39: cc_tools_integration.SupportedProjectTypes
+	(*StatsRequest)(nil),                 // 40: cc_tools_integration.StatsRequest
+	(*LockContention)(nil),               // 41: cc_tools_integration.LockContention
+	(*ServerStats)(nil),                  // 42: cc_tools_integration.ServerStats
+	(*ValidatorDefinitionRequest)(nil),   // 43: cc_tools_integration.ValidatorDefinitionRequest
+	(*ValidatorDefinition)(nil),          // 44: cc_tools_integration.ValidatorDefinition
+	nil,                                  // 45: cc_tools_integration.ValidationRequest.ContextEntry
+	nil,                                  // 46: cc_tools_integration.ValidationRequest.OverrideCommandsEntry
+	nil,                                  // 47: cc_tools_integration.ValidationRequest.OverrideArgvEntry
+	nil,                                  // 48: cc_tools_integration.ValidationRequest.EnvEntry
+	nil,                                  // 49: cc_tools_integration.ValidationRequest.ContentionKeysEntry
+	nil,                                  // 50: cc_tools_integration.ValidationRequest.ValidatorTimeoutsMsEntry
+	nil,                                  // 51: cc_tools_integration.ProjectMetadata.CommandsEntry
+	nil,                                  // 52: cc_tools_integration.ProjectMetadata.CommandAvailableEntry
+	nil,                                  // 53: cc_tools_integration.RunManifest.ToolchainVersionsEntry
+	nil,                                  // 54: cc_tools_integration.ProjectTypeDescriptor.DefaultCommandsEntry
+	nil,                                  // 55: cc_tools_integration.ValidatorDefinition.EnvEntry
 }
 var file_proto_cc_tools_integration_proto_depIdxs = []int32{
-	6, // 0: cc_tools_integration.ValidationRequest.context:type_name -> cc_tools_integration.ValidationRequest.ContextEntry
-	7, // 1: cc_tools_integration.ProjectMetadata.commands:type_name -> cc_tools_integration.ProjectMetadata.CommandsEntry
-	4, // 2: cc_tools_integration.ValidationResponse.results:type_name -> cc_tools_integration.ValidationResult
-	1, // 3: cc_tools_integration.ValidationResponse.metadata:type_name -> cc_tools_integration.ProjectMetadata
-	0, // 4: cc_tools_integration.CCToolsIntegration.ValidateProject:input_type -> cc_tools_integration.ValidationRequest
-	0, // 5: cc_tools_integration.CCToolsIntegration.GetProjectMetadata:input_type -> cc_tools_integration.ValidationRequest
-	5, // 6: cc_tools_integration.CCToolsIntegration.AcquireLock:input_type -> cc_tools_integration.LockRequest
-	5, // 7: cc_tools_integration.CCToolsIntegration.ReleaseLock:input_type -> cc_tools_integration.LockRequest
-	5, // 8: cc_tools_integration.CCToolsIntegration.CheckLock:input_type -> cc_tools_integration.LockRequest
-	3, // 9: cc_tools_integration.CCToolsIntegration.ValidateProject:output_type -> cc_tools_integration.ValidationResponse
-	1, // 10: cc_tools_integration.CCToolsIntegration.GetProjectMetadata:output_type -> cc_tools_integration.ProjectMetadata
-	2, // 11: cc_tools_integration.CCToolsIntegration.AcquireLock:output_type -> cc_tools_integration.LockStatus
-	2, // 12: cc_tools_integration.CCToolsIntegration.ReleaseLock:output_type -> cc_tools_integration.LockStatus
-	2, // 13: cc_tools_integration.CCToolsIntegration.CheckLock:output_type -> cc_tools_integration.LockStatus
-	9, // [9:14] is the sub-list for method output_type
-	4, // [4:9] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	45, // 0: cc_tools_integration.ValidationRequest.context:type_name -> cc_tools_integration.ValidationRequest.ContextEntry
+	46, // 1: cc_tools_integration.ValidationRequest.override_commands:type_name -> cc_tools_integration.ValidationRequest.OverrideCommandsEntry
+	47, // 2: cc_tools_integration.ValidationRequest.override_argv:type_name -> cc_tools_integration.ValidationRequest.OverrideArgvEntry
+	48, // 3: cc_tools_integration.ValidationRequest.env:type_name -> cc_tools_integration.ValidationRequest.EnvEntry
+	0,  // 4: cc_tools_integration.ValidationRequest.empty_run_policy:type_name -> cc_tools_integration.EmptyRunPolicy
+	49, // 5: cc_tools_integration.ValidationRequest.contention_keys:type_name -> cc_tools_integration.ValidationRequest.ContentionKeysEntry
+	50, // 6: cc_tools_integration.ValidationRequest.validator_timeouts_ms:type_name -> cc_tools_integration.ValidationRequest.ValidatorTimeoutsMsEntry
+	51, // 7: cc_tools_integration.ProjectMetadata.commands:type_name -> cc_tools_integration.ProjectMetadata.CommandsEntry
+	52, // 8: cc_tools_integration.ProjectMetadata.command_available:type_name -> cc_tools_integration.ProjectMetadata.CommandAvailableEntry
+	7,  // 9: cc_tools_integration.ListLocksResponse.locks:type_name -> cc_tools_integration.LockStatus
+	14, // 10: cc_tools_integration.ValidationResponse.results:type_name -> cc_tools_integration.ValidationResult
+	6,  // 11: cc_tools_integration.ValidationResponse.metadata:type_name -> cc_tools_integration.ProjectMetadata
+	10, // 12: cc_tools_integration.ValidationResponse.summary:type_name -> cc_tools_integration.ValidationSummary
+	12, // 13: cc_tools_integration.ValidationResponse.manifest:type_name -> cc_tools_integration.RunManifest
+	44, // 14: cc_tools_integration.RunManifest.validators:type_name -> cc_tools_integration.ValidatorDefinition
+	53, // 15: cc_tools_integration.RunManifest.toolchain_versions:type_name -> cc_tools_integration.RunManifest.ToolchainVersionsEntry
+	13, // 16: cc_tools_integration.RunManifest.host:type_name -> cc_tools_integration.HostInfo
+	1,  // 17: cc_tools_integration.ValidationResult.skip_reason:type_name -> cc_tools_integration.SkipReason
+	2,  // 18: cc_tools_integration.ValidationResult.failure_reason:type_name -> cc_tools_integration.FailureReason
+	3,  // 19: cc_tools_integration.ValidationResult.start_failure_reason:type_name -> cc_tools_integration.StartFailureReason
+	16, // 20: cc_tools_integration.ValidationResult.artifacts:type_name -> cc_tools_integration.Artifact
+	15, // 21: cc_tools_integration.ValidationResult.diagnostics:type_name -> cc_tools_integration.Diagnostic
+	17, // 22: cc_tools_integration.LockAndValidateRequest.lock:type_name -> cc_tools_integration.LockRequest
+	4,  // 23: cc_tools_integration.LockAndValidateRequest.validation:type_name -> cc_tools_integration.ValidationRequest
+	7,  // 24: cc_tools_integration.LockAndValidateResponse.lock:type_name -> cc_tools_integration.LockStatus
+	11, // 25: cc_tools_integration.LockAndValidateResponse.validation:type_name -> cc_tools_integration.ValidationResponse
+	4,  // 26: cc_tools_integration.BatchValidationRequest.requests:type_name -> cc_tools_integration.ValidationRequest
+	11, // 27: cc_tools_integration.BatchValidationResponse.responses:type_name -> cc_tools_integration.ValidationResponse
+	6,  // 28: cc_tools_integration.ProjectMetadataResult.metadata:type_name -> cc_tools_integration.ProjectMetadata
+	22, // 29: cc_tools_integration.ProjectMetadataBatchResponse.results:type_name -> cc_tools_integration.ProjectMetadataResult
+	6,  // 30: cc_tools_integration.SubProject.metadata:type_name -> cc_tools_integration.ProjectMetadata
+	25, // 31: cc_tools_integration.DiscoverSubProjectsResponse.projects:type_name -> cc_tools_integration.SubProject
+	14, // 32: cc_tools_integration.ValidationEvent.result:type_name -> cc_tools_integration.ValidationResult
+	11, // 33: cc_tools_integration.ValidationEvent.response:type_name -> cc_tools_integration.ValidationResponse
+	36, // 34: cc_tools_integration.ValidationEvent.progress:type_name -> cc_tools_integration.Progress
+	54, // 35: cc_tools_integration.ProjectTypeDescriptor.default_commands:type_name -> cc_tools_integration.ProjectTypeDescriptor.DefaultCommandsEntry
+	38, // 36: cc_tools_integration.SupportedProjectTypes.types:type_name -> cc_tools_integration.ProjectTypeDescriptor
+	41, // 37: cc_tools_integration.ServerStats.lock_contention:type_name -> cc_tools_integration.LockContention
+	4,  // 38: cc_tools_integration.ValidatorDefinitionRequest.request:type_name -> cc_tools_integration.ValidationRequest
+	55, // 39: cc_tools_integration.ValidatorDefinition.env:type_name -> cc_tools_integration.ValidatorDefinition.EnvEntry
+	5,  // 40: cc_tools_integration.ValidationRequest.OverrideArgvEntry.value:type_name -> cc_tools_integration.CommandArgv
+	4,  // 41: cc_tools_integration.CCToolsIntegration.ValidateProject:input_type -> cc_tools_integration.ValidationRequest
+	4,  // 42: cc_tools_integration.CCToolsIntegration.GetProjectMetadata:input_type -> cc_tools_integration.ValidationRequest
+	17, // 43: cc_tools_integration.CCToolsIntegration.AcquireLock:input_type -> cc_tools_integration.LockRequest
+	17, // 44: cc_tools_integration.CCToolsIntegration.ReleaseLock:input_type -> cc_tools_integration.LockRequest
+	17, // 45: cc_tools_integration.CCToolsIntegration.CheckLock:input_type -> cc_tools_integration.LockRequest
+	8,  // 46: cc_tools_integration.CCToolsIntegration.ListLocks:input_type -> cc_tools_integration.ListLocksRequest
+	18, // 47: cc_tools_integration.CCToolsIntegration.LockAndValidate:input_type -> cc_tools_integration.LockAndValidateRequest
+	4,  // 48: cc_tools_integration.CCToolsIntegration.StreamValidation:input_type -> cc_tools_integration.ValidationRequest
+	34, // 49: cc_tools_integration.CCToolsIntegration.GetValidationLog:input_type -> cc_tools_integration.ValidationLogRequest
+	20, // 50: cc_tools_integration.CCToolsIntegration.ValidateProjects:input_type -> cc_tools_integration.BatchValidationRequest
+	20, // 51: cc_tools_integration.CCToolsIntegration.GetProjectMetadataBatch:input_type -> cc_tools_integration.BatchValidationRequest
+	24, // 52: cc_tools_integration.CCToolsIntegration.DiscoverSubProjects:input_type -> cc_tools_integration.DiscoverSubProjectsRequest
+	27, // 53: cc_tools_integration.CCToolsIntegration.AbortAll:input_type -> cc_tools_integration.AbortAllRequest
+	29, // 54: cc_tools_integration.CCToolsIntegration.CancelValidationsByProject:input_type -> cc_tools_integration.CancelValidationsRequest
+	31, // 55: cc_tools_integration.CCToolsIntegration.CancelValidation:input_type -> cc_tools_integration.CancelValidationRequest
+	43, // 56: cc_tools_integration.CCToolsIntegration.GetValidatorDefinition:input_type -> cc_tools_integration.ValidatorDefinitionRequest
+	37, // 57: cc_tools_integration.CCToolsIntegration.GetSupportedProjectTypes:input_type -> cc_tools_integration.SupportedProjectTypesRequest
+	40, // 58: cc_tools_integration.CCToolsIntegration.GetStats:input_type -> cc_tools_integration.StatsRequest
+	11, // 59: cc_tools_integration.CCToolsIntegration.ValidateProject:output_type -> cc_tools_integration.ValidationResponse
+	6,  // 60: cc_tools_integration.CCToolsIntegration.GetProjectMetadata:output_type -> cc_tools_integration.ProjectMetadata
+	7,  // 61: cc_tools_integration.CCToolsIntegration.AcquireLock:output_type -> cc_tools_integration.LockStatus
+	7,  // 62: cc_tools_integration.CCToolsIntegration.ReleaseLock:output_type -> cc_tools_integration.LockStatus
+	7,  // 63: cc_tools_integration.CCToolsIntegration.CheckLock:output_type -> cc_tools_integration.LockStatus
+	9,  // 64: cc_tools_integration.CCToolsIntegration.ListLocks:output_type -> cc_tools_integration.ListLocksResponse
+	19, // 65: cc_tools_integration.CCToolsIntegration.LockAndValidate:output_type -> cc_tools_integration.LockAndValidateResponse
+	33, // 66: cc_tools_integration.CCToolsIntegration.StreamValidation:output_type -> cc_tools_integration.ValidationEvent
+	35, // 67: cc_tools_integration.CCToolsIntegration.GetValidationLog:output_type -> cc_tools_integration.ValidationLog
+	21, // 68: cc_tools_integration.CCToolsIntegration.ValidateProjects:output_type -> cc_tools_integration.BatchValidationResponse
+	23, // 69: cc_tools_integration.CCToolsIntegration.GetProjectMetadataBatch:output_type -> cc_tools_integration.ProjectMetadataBatchResponse
+	26, // 70: cc_tools_integration.CCToolsIntegration.DiscoverSubProjects:output_type -> cc_tools_integration.DiscoverSubProjectsResponse
+	28, // 71: cc_tools_integration.CCToolsIntegration.AbortAll:output_type -> cc_tools_integration.AbortAllResponse
+	30, // 72: cc_tools_integration.CCToolsIntegration.CancelValidationsByProject:output_type -> cc_tools_integration.CancelValidationsResponse
+	32, // 73: cc_tools_integration.CCToolsIntegration.CancelValidation:output_type -> cc_tools_integration.CancelValidationResponse
+	44, // 74: cc_tools_integration.CCToolsIntegration.GetValidatorDefinition:output_type -> cc_tools_integration.ValidatorDefinition
+	39, // 75: cc_tools_integration.CCToolsIntegration.GetSupportedProjectTypes:output_type -> cc_tools_integration.SupportedProjectTypes
+	42, // 76: cc_tools_integration.CCToolsIntegration.GetStats:output_type -> cc_tools_integration.ServerStats
+	59, // [59:77] is the sub-list for method output_type
+	41, // [41:59] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_proto_cc_tools_integration_proto_init() }
@@ -580,13 +3967,14 @@ func file_proto_cc_tools_integration_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_cc_tools_integration_proto_rawDesc), len(file_proto_cc_tools_integration_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      4,
+			NumMessages:   52,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_cc_tools_integration_proto_goTypes,
 		DependencyIndexes: file_proto_cc_tools_integration_proto_depIdxs,
+		EnumInfos:         file_proto_cc_tools_integration_proto_enumTypes,
 		MessageInfos:      file_proto_cc_tools_integration_proto_msgTypes,
 	}.Build()
 	File_proto_cc_tools_integration_proto = out.File
@@ -0,0 +1,70 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "os"
+    "path"
+    "path/filepath"
+    "strconv"
+    "strings"
+)
+
+// goModDirective returns the argument of the first line of go.mod starting
+// with directive (e.g. "module", "go"), unquoted and without a trailing comment
+func goModDirective(data []byte, directive string) (string, bool) {
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if i := strings.Index(line, "//"); i >= 0 {
+            line = strings.TrimSpace(line[:i])
+        }
+        rest, ok := strings.CutPrefix(line, directive)
+        if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+            continue
+        }
+        value := strings.TrimSpace(rest)
+        if unquoted, err := strconv.Unquote(value); err == nil {
+            value = unquoted
+        }
+        return value, value != ""
+    }
+    return "", false
+}
+
+// goModToolchain reads the go directive, the minimum Go version the module needs
+func goModToolchain(dir string) (*toolchainRequirement, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+    if err != nil {
+        return nil, err
+    }
+    version, ok := goModDirective(data, "go")
+    if !ok {
+        return nil, nil
+    }
+    return &toolchainRequirement{tool: "go", source: "go.mod go directive", constraint: ">=" + version, probe: []string{"go", "version"}}, nil
+}
+
+// goModProjectName names the project after its module path. Binaries are
+// the main package at the module root and each directory under cmd/.
+func goModProjectName(dir string) (string, []string, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+    if err != nil {
+        return "", nil, err
+    }
+    module, _ := goModDirective(data, "module")
+
+    var binaries []string
+    if module != "" {
+        if _, err := os.Stat(filepath.Join(dir, "main.go")); err == nil {
+            binaries = append(binaries, path.Base(module))
+        }
+    }
+    entries, _ := os.ReadDir(filepath.Join(dir, "cmd"))
+    for _, entry := range entries {
+        if entry.IsDir() {
+            binaries = append(binaries, entry.Name())
+        }
+    }
+    return module, binaries, nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+    "crypto/tls"
+    "fmt"
+
+    "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+    "google.golang.org/grpc"
+    // Registers the gzip compressor; see newGRPCServer
+    _ "google.golang.org/grpc/encoding/gzip"
+    health "google.golang.org/grpc/health"
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/reflection"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// serverConfig holds what differs between the entrypoints
+type serverConfig struct {
+    // tls applies the TLS_* settings (see serverCredentials); without it
+    // the server always speaks plaintext
+    tls bool
+
+    // reflection registers the gRPC reflection service
+    reflection bool
+}
+
+// serverParts is a constructed server and what the entrypoint needs to
+// run it: the HTTP gateway shares the limits and the rate limiter, and
+// shutdown drives the health service
+type serverParts struct {
+    grpc      *grpc.Server
+    tools     *CCToolsServer
+    health    *health.Server
+    limits    requestLimits
+    limiter   *rateLimiter
+    transport string      // "plaintext", "TLS" or "mutual TLS"
+    tlsConfig *tls.Config // nil for plaintext
+}
+
+// newGRPCServer builds the gRPC server with its options and interceptors,
+// registers CCToolsIntegration and health, and starts reporting SERVING
+// (after the optional warm-up). Configuration comes from the environment.
+//
+// The server understands gzip. A client that sends with gzip, e.g. by
+// dialing with grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)),
+// gets gzip-compressed responses back, which shrinks large validation
+// output on the wire more cheaply than raising MAX_SEND_MSG_MB; clients
+// that do not ask are answered uncompressed. This applies to every service
+// on the server, health and reflection included.
+func newGRPCServer(cfg serverConfig) (*serverParts, error) {
+    parts := &serverParts{
+        limits:    loadRequestLimits(),
+        limiter:   loadRateLimiter(),
+        transport: "plaintext",
+    }
+    msgLimits, err := loadMessageLimits()
+    if err != nil {
+        return nil, err
+    }
+    opts := []grpc.ServerOption{
+        grpc.MaxRecvMsgSize(msgLimits.recvBytes()),
+        grpc.MaxSendMsgSize(msgLimits.sendBytes()),
+        grpc.KeepaliveParams(keepaliveParams()),
+        grpc.KeepaliveEnforcementPolicy(keepalivePolicy()),
+        grpc.StatsHandler(otelgrpc.NewServerHandler()),
+        grpc.ChainUnaryInterceptor(loggingUnaryInterceptor, loggingRecoveryUnaryInterceptor, rateLimitUnaryInterceptor(parts.limiter), requestLimitsUnaryInterceptor(parts.limits)),
+        grpc.ChainStreamInterceptor(loggingStreamInterceptor, loggingRecoveryStreamInterceptor, rateLimitStreamInterceptor(parts.limiter), requestLimitsStreamInterceptor(parts.limits)),
+    }
+    if cfg.tls {
+        creds, tlsConfig, mode, err := serverCredentials()
+        if err != nil {
+            return nil, fmt.Errorf("configure TLS: %w", err)
+        }
+        if creds != nil {
+            opts = append(opts, creds)
+        }
+        parts.transport = mode
+        parts.tlsConfig = tlsConfig
+    }
+
+    parts.grpc = grpc.NewServer(opts...)
+    parts.tools = NewCCToolsServer()
+    parts.tools.limits = parts.limits
+    pb.RegisterCCToolsIntegrationServer(parts.grpc, parts.tools)
+
+    parts.health = health.NewServer()
+    healthpb.RegisterHealthServer(parts.grpc, parts.health)
+    if cfg.reflection {
+        reflection.Register(parts.grpc)
+    }
+    startServing(parts.health, parts.tools)
+    return parts, nil
+}
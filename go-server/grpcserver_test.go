@@ -0,0 +1,324 @@
+package main
+
+import (
+    "context"
+    "net"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "sync"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/encoding/gzip"
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+    reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+    "google.golang.org/grpc/stats"
+    "google.golang.org/grpc/test/bufconn"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// testServer is a server built by newGRPCServer, served over an in-memory
+// bufconn listener, with a client connected to it
+type testServer struct {
+    parts  *serverParts
+    lis    *bufconn.Listener
+    conn   *grpc.ClientConn
+    client pb.CCToolsIntegrationClient
+}
+
+// newTestServer starts a server for the duration of the test. The
+// environment is the caller's (set it with t.Setenv first), except that
+// periodic readiness checks are off and validation logs go to a temp dir.
+// The server and the connection are shut down when the test ends.
+func newTestServer(t *testing.T, cfg serverConfig, dialOpts ...grpc.DialOption) *testServer {
+    t.Helper()
+    if _, ok := os.LookupEnv("READINESS_INTERVAL_SECONDS"); !ok {
+        t.Setenv("READINESS_INTERVAL_SECONDS", "0")
+    }
+    if _, ok := os.LookupEnv("VALIDATION_LOG_DIR"); !ok {
+        t.Setenv("VALIDATION_LOG_DIR", t.TempDir())
+    }
+
+    parts, err := newGRPCServer(cfg)
+    if err != nil {
+        t.Fatalf("newGRPCServer: %v", err)
+    }
+    ts := &testServer{parts: parts, lis: bufconn.Listen(1 << 20)}
+    go parts.grpc.Serve(ts.lis)
+    t.Cleanup(func() {
+        parts.grpc.Stop()
+        parts.tools.shellPool.close()
+    })
+
+    ts.conn = ts.dial(t, dialOpts...)
+    ts.client = pb.NewCCToolsIntegrationClient(ts.conn)
+    return ts
+}
+
+// dial opens another connection to the server, closed when the test ends;
+// dialOpts override the default plaintext credentials
+func (ts *testServer) dial(t *testing.T, dialOpts ...grpc.DialOption) *grpc.ClientConn {
+    t.Helper()
+    dialOpts = append([]grpc.DialOption{
+        grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+            return ts.lis.DialContext(ctx)
+        }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()),
+    }, dialOpts...)
+    conn, err := grpc.NewClient("passthrough:///bufconn", dialOpts...)
+    if err != nil {
+        t.Fatalf("dial bufconn: %v", err)
+    }
+    t.Cleanup(func() { conn.Close() })
+    return conn
+}
+
+// writeProject creates a temp project holding files (relative path to
+// content) and returns its root
+func writeProject(t *testing.T, files map[string]string) string {
+    t.Helper()
+    root := t.TempDir()
+    for name, content := range files {
+        path := filepath.Join(root, filepath.FromSlash(name))
+        if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+            t.Fatal(err)
+        }
+        if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+            t.Fatal(err)
+        }
+    }
+    return root
+}
+
+// makeProject is a project whose Makefile defines the given targets, each
+// running its recipe
+func makeProject(t *testing.T, targets map[string]string) string {
+    t.Helper()
+    var makefile strings.Builder
+    for target, recipe := range targets {
+        makefile.WriteString(target + ":\n\t@" + recipe + "\n")
+    }
+    return writeProject(t, map[string]string{"Makefile": makefile.String()})
+}
+
+// resultsByName indexes a response's results by validator
+func resultsByName(resp *pb.ValidationResponse) map[string]*pb.ValidationResult {
+    byName := make(map[string]*pb.ValidationResult, len(resp.Results))
+    for _, result := range resp.Results {
+        byName[result.Validator] = result
+    }
+    return byName
+}
+
+func TestValidateProjectOverBufconn(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+
+    t.Run("passing", func(t *testing.T) {
+        root := makeProject(t, map[string]string{"lint": "echo linted", "test": "echo tested"})
+        resp, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root})
+        if err != nil {
+            t.Fatalf("ValidateProject: %v", err)
+        }
+        if !resp.Success {
+            t.Fatalf("Success = false, results: %v", resp.Results)
+        }
+        if resp.Metadata.GetProjectType() != "make" {
+            t.Errorf("project type = %q, want make", resp.Metadata.GetProjectType())
+        }
+        byName := resultsByName(resp)
+        for validator, want := range map[string]string{"lint": "linted", "test": "tested"} {
+            result, ok := byName[validator]
+            if !ok {
+                t.Fatalf("no %s result in %v", validator, resp.Results)
+            }
+            if !result.Success || !strings.Contains(result.Output, want) {
+                t.Errorf("%s: success=%v output=%q, want success and %q", validator, result.Success, result.Output, want)
+            }
+        }
+    })
+
+    t.Run("failing", func(t *testing.T) {
+        root := makeProject(t, map[string]string{"lint": "true", "test": "exit 3"})
+        resp, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root})
+        if err != nil {
+            t.Fatalf("ValidateProject: %v", err)
+        }
+        if resp.Success {
+            t.Fatal("Success = true for a failing test target")
+        }
+        if result := resultsByName(resp)["test"]; result == nil || result.Success {
+            t.Errorf("test result = %v, want a failure", result)
+        }
+    })
+}
+
+func TestGetProjectMetadataOverBufconn(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    root := writeProject(t, map[string]string{
+        "package.json": `{"name": "web", "scripts": {"lint": "eslint .", "test": "jest"}}`,
+    })
+
+    md, err := ts.client.GetProjectMetadata(context.Background(), &pb.ValidationRequest{ProjectRoot: root})
+    if err != nil {
+        t.Fatalf("GetProjectMetadata: %v", err)
+    }
+    if md.ProjectType != "npm" || md.ProjectName != "web" {
+        t.Errorf("type=%q name=%q, want npm and web", md.ProjectType, md.ProjectName)
+    }
+    if md.Commands["test"] == "" {
+        t.Errorf("no test command in %v", md.Commands)
+    }
+    if md.Etag == "" {
+        t.Error("Etag is empty")
+    }
+}
+
+func TestLockLifecycleOverBufconn(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    root := t.TempDir()
+    req := &pb.LockRequest{ProjectPath: root, Owner: "tester"}
+
+    acquired, err := ts.client.AcquireLock(ctx, req)
+    if err != nil {
+        t.Fatalf("AcquireLock: %v", err)
+    }
+    if !acquired.IsLocked || acquired.LockId == "" || acquired.Owner != "tester" {
+        t.Fatalf("AcquireLock = %v, want a lock held by tester", acquired)
+    }
+
+    checked, err := ts.client.CheckLock(ctx, req)
+    if err != nil {
+        t.Fatalf("CheckLock: %v", err)
+    }
+    if !checked.IsLocked || checked.LockId != acquired.LockId {
+        t.Errorf("CheckLock = %v, want lock %s held", checked, acquired.LockId)
+    }
+
+    // A second acquirer is told who holds the lock rather than taking it
+    again, err := ts.client.AcquireLock(ctx, &pb.LockRequest{ProjectPath: root, Owner: "other"})
+    if err != nil {
+        t.Fatalf("second AcquireLock: %v", err)
+    }
+    if again.LockId != acquired.LockId || again.Owner != "tester" {
+        t.Errorf("second AcquireLock = %v, want the existing holder", again)
+    }
+
+    if _, err := ts.client.ReleaseLock(ctx, req); err != nil {
+        t.Fatalf("ReleaseLock: %v", err)
+    }
+    checked, err = ts.client.CheckLock(ctx, req)
+    if err != nil {
+        t.Fatalf("CheckLock after release: %v", err)
+    }
+    if checked.IsLocked {
+        t.Errorf("CheckLock after release = %v, want unlocked", checked)
+    }
+}
+
+func TestNewGRPCServerRegistersServices(t *testing.T) {
+    for _, reflection := range []bool{false, true} {
+        ts := newTestServer(t, serverConfig{reflection: reflection})
+        services := ts.parts.grpc.GetServiceInfo()
+        for _, name := range []string{"cc_tools_integration.CCToolsIntegration", "grpc.health.v1.Health"} {
+            if _, ok := services[name]; !ok {
+                t.Errorf("reflection=%v: %s is not registered", reflection, name)
+            }
+        }
+        if _, ok := services["grpc.reflection.v1.ServerReflection"]; ok != reflection {
+            t.Errorf("reflection=%v: reflection service registered = %v", reflection, ok)
+        }
+        if ts.parts.transport != "plaintext" {
+            t.Errorf("transport = %q, want plaintext", ts.parts.transport)
+        }
+
+        // Liveness and readiness are both SERVING once the server is built
+        health := healthpb.NewHealthClient(ts.conn)
+        for _, service := range healthServices {
+            resp, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+            if err != nil || resp.Status != healthpb.HealthCheckResponse_SERVING {
+                t.Errorf("health of %q = %v, %v; want SERVING", service, resp.GetStatus(), err)
+            }
+        }
+    }
+}
+
+// payloadSizes is a client stats handler that records the size of the
+// largest response it received, before and after decompression
+type payloadSizes struct {
+    mu               sync.Mutex
+    length           int
+    compressedLength int
+}
+
+func (p *payloadSizes) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context { return ctx }
+
+func (p *payloadSizes) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+
+func (p *payloadSizes) HandleConn(context.Context, stats.ConnStats) {}
+
+func (p *payloadSizes) HandleRPC(_ context.Context, s stats.RPCStats) {
+    if in, ok := s.(*stats.InPayload); ok {
+        p.mu.Lock()
+        defer p.mu.Unlock()
+        if in.Length > p.length {
+            p.length, p.compressedLength = in.Length, in.CompressedLength
+        }
+    }
+}
+
+func TestGzipClientDecodesLargeResponse(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("MAX_OUTPUT_BYTES", "0")
+    ts := newTestServer(t, serverConfig{reflection: true})
+    sizes := &payloadSizes{}
+    conn := ts.dial(t, grpc.WithDefaultCallOptions(grpc.UseCompressor(gzip.Name)), grpc.WithStatsHandler(sizes))
+    client := pb.NewCCToolsIntegrationClient(conn)
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+
+    // 1 MiB of highly compressible output, carried in both output and
+    // stdout, on one line so it does not trip runaway output detection
+    const size = 1 << 20
+    resp, err := client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot:      root,
+        OverrideCommands: map[string]string{"lint": "", "test": "sh -c 'head -c " + strconv.Itoa(size) + " /dev/zero | tr -c a a'"},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    // The response came back compressed and was inflated on receipt
+    sizes.mu.Lock()
+    if sizes.length < 2*size || sizes.compressedLength > size/10 {
+        t.Errorf("response was %d bytes on the wire for %d decoded, want it gzipped", sizes.compressedLength, sizes.length)
+    }
+    sizes.mu.Unlock()
+    if test := resultsByName(resp)["test"]; test.GetStdout() != strings.Repeat("a", size) {
+        t.Errorf("stdout is %d bytes (%s), want %d", len(test.GetStdout()), test.GetError(), size)
+    }
+
+    // Health and reflection answer a gzip client too
+    resp2, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+    if err != nil || resp2.Status != healthpb.HealthCheckResponse_SERVING {
+        t.Errorf("health over gzip = %v, %v; want SERVING", resp2.GetStatus(), err)
+    }
+    stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+    if err != nil {
+        t.Fatalf("ServerReflectionInfo: %v", err)
+    }
+    if err := stream.Send(&reflectionpb.ServerReflectionRequest{MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{}}); err != nil {
+        t.Fatalf("send ListServices: %v", err)
+    }
+    listed, err := stream.Recv()
+    if err != nil {
+        t.Fatalf("recv ListServices: %v", err)
+    }
+    if len(listed.GetListServicesResponse().GetService()) == 0 {
+        t.Errorf("reflection over gzip listed no services: %v", listed)
+    }
+    stream.CloseSend()
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+    "expvar"
+    "log/slog"
+    "time"
+)
+
+// heartbeatTimestamp is the unix time of the last heartbeat. Monitoring
+// should alert when it stops advancing: the port may still be open while
+// the process is wedged.
+var heartbeatTimestamp = expvar.NewInt("server_heartbeat_timestamp")
+
+// runHeartbeat logs a heartbeat line and bumps the liveness gauges every interval
+func (s *CCToolsServer) runHeartbeat(interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    s.heartbeat()
+    for range ticker.C {
+        s.heartbeat()
+    }
+}
+
+func (s *CCToolsServer) heartbeat() {
+    now := time.Now()
+    heartbeatTimestamp.Set(now.Unix())
+    heartbeatSeconds.Set(float64(now.Unix()))
+
+    s.lockManager.mutex.RLock()
+    locks := len(s.lockManager.locks)
+    s.lockManager.mutex.RUnlock()
+
+    slog.Info("heartbeat", "running_validations", s.jobs.count(), "held_locks", locks)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+    "sort"
+    "sync"
+)
+
+// historyKey identifies a validator within a project
+type historyKey struct {
+    projectRoot string
+    validator   string
+}
+
+// validationHistory keeps the most recent execution times per project and
+// validator so the server can reason about how long a validator usually takes
+type validationHistory struct {
+    mu        sync.Mutex
+    durations map[historyKey][]int64
+    samples   int // durations kept per key
+    maxKeys   int // distinct (project, validator) pairs tracked
+}
+
+func newValidationHistory(samples, maxKeys int) *validationHistory {
+    return &validationHistory{
+        durations: make(map[historyKey][]int64),
+        samples:   samples,
+        maxKeys:   maxKeys,
+    }
+}
+
+// record stores an execution time, dropping the oldest sample when full
+func (h *validationHistory) record(projectRoot, validator string, durationMs int64) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    key := historyKey{projectRoot: projectRoot, validator: validator}
+    samples, exists := h.durations[key]
+    if !exists && len(h.durations) >= h.maxKeys {
+        // Evict an arbitrary entry to keep memory bounded
+        for k := range h.durations {
+            delete(h.durations, k)
+            break
+        }
+    }
+
+    samples = append(samples, durationMs)
+    if len(samples) > h.samples {
+        samples = samples[len(samples)-h.samples:]
+    }
+    h.durations[key] = samples
+}
+
+// p50 returns the median recorded execution time, or false without history
+func (h *validationHistory) p50(projectRoot, validator string) (int64, bool) {
+    h.mu.Lock()
+    samples := append([]int64(nil), h.durations[historyKey{projectRoot: projectRoot, validator: validator}]...)
+    h.mu.Unlock()
+
+    if len(samples) == 0 {
+        return 0, false
+    }
+    sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+    return samples[len(samples)/2], true
+}
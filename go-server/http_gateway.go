@@ -0,0 +1,469 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "expvar"
+    "fmt"
+    "log/slog"
+    "net"
+    "net/http"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials"
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/peer"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/encoding/protojson"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// The HTTP gateway exposes validation as plain JSON for scripts (jq, CI
+// shells) that would rather not speak gRPC. Field names below are part of
+// the public contract: they are snake_case, always present unless marked
+// omitempty, and must not be renamed.
+//
+// The gateway runs validations, overrides included, so it gets the same
+// protection as the gRPC listener: it binds HTTP_BIND_ADDR (127.0.0.1
+// unless set), serves TLS with the gRPC listener's config when TLS is
+// configured, and under mutual TLS the client certificate's common name is
+// the caller's identity for quotas and lock namespaces, exactly as over
+// gRPC. /debug/vars requires the admin token.
+
+// defaultHTTPBindAddr keeps the gateway off the network unless the
+// operator opts in
+const defaultHTTPBindAddr = "127.0.0.1"
+
+// startHTTPGateway serves the JSON gateway on HTTP_PORT, returning nil when
+// it is not set. The caller shuts the returned server down.
+func startHTTPGateway(parts *serverParts) *http.Server {
+    port := os.Getenv("HTTP_PORT")
+    if port == "" {
+        return nil
+    }
+    server := &http.Server{
+        Addr:              net.JoinHostPort(envString("HTTP_BIND_ADDR", defaultHTTPBindAddr), port),
+        Handler:           newHTTPGateway(parts.tools, parts.limits, parts.limiter),
+        TLSConfig:         parts.tlsConfig,
+        ReadHeaderTimeout: 10 * time.Second,
+    }
+    go func() {
+        slog.Info("HTTP JSON gateway listening", "addr", server.Addr, "transport", parts.transport)
+        var err error
+        if server.TLSConfig != nil {
+            // The certificates are in TLSConfig already
+            err = server.ListenAndServeTLS("", "")
+        } else {
+            err = server.ListenAndServe()
+        }
+        if err != nil && !errors.Is(err, http.ErrServerClosed) {
+            slog.Error("HTTP gateway stopped", "error", err)
+        }
+    }()
+    return server
+}
+
+// httpCallContext carries the HTTP caller's verified client certificate and
+// authorization header the way a gRPC call does, so identityFromContext and
+// requireAdmin work unchanged
+func httpCallContext(ctx context.Context, r *http.Request) context.Context {
+    if r.TLS != nil {
+        ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: *r.TLS}})
+    }
+    if auth := r.Header.Get("Authorization"); auth != "" {
+        ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", auth))
+    }
+    return ctx
+}
+
+// parseEmptyRunPolicy maps the JSON empty_run_policy ("success", "error",
+// "warn", or empty for the server default) to the enum
+func parseEmptyRunPolicy(name string) (pb.EmptyRunPolicy, bool) {
+    if name == "" {
+        return pb.EmptyRunPolicy_EMPTY_RUN_POLICY_UNSPECIFIED, true
+    }
+    policy, ok := pb.EmptyRunPolicy_value["EMPTY_RUN_POLICY_"+strings.ToUpper(name)]
+    if !ok || policy == int32(pb.EmptyRunPolicy_EMPTY_RUN_POLICY_UNSPECIFIED) {
+        return 0, false
+    }
+    return pb.EmptyRunPolicy(policy), true
+}
+
+// validateRequestJSON is the body accepted by POST /v1/validate
+type validateRequestJSON struct {
+    ProjectRoot           string              `json:"project_root"`
+    HookType              string              `json:"hook_type"`
+    FilePaths             []string            `json:"file_paths"`
+    Context               map[string]string   `json:"context"`
+    TimeoutMs             int32               `json:"timeout_ms"`
+    LoginShell            bool                `json:"login_shell"`
+    LoginShellValidators  []string            `json:"login_shell_validators"`
+    MaxDurationHintMs     int64               `json:"max_duration_hint_ms"`
+    OverrideCommands      map[string]string   `json:"override_commands"`
+    OverrideArgv          map[string][]string `json:"override_argv"`
+    Retries               int32               `json:"retries"`
+    FailuresOnly          bool                `json:"failures_only"`
+    Env                   map[string]string   `json:"env"`
+    GitBaseRef            string              `json:"git_base_ref"`
+    GitHeadRef            string              `json:"git_head_ref"`
+    EmptyRunPolicy        string              `json:"empty_run_policy"`
+    ArtifactGlobs         []string            `json:"artifact_globs"`
+    CheckToolchain        bool                `json:"check_toolchain"`
+    Stdin                 []byte              `json:"stdin"` // base64, as encoding/json does for []byte
+    StdinFile             string              `json:"stdin_file"`
+    ChattyValidators      []string            `json:"chatty_validators"`
+    ForceColor            bool                `json:"force_color"`
+    IncludeManifest       bool                `json:"include_manifest"`
+    ContentionKeys        map[string]string   `json:"contention_keys"`
+    MaxParallelValidators int32               `json:"max_parallel_validators"`
+    UseCache              bool                `json:"use_cache"`
+    DryRun                bool                `json:"dry_run"`
+    RetryExitCodes        []int32             `json:"retry_exit_codes"`
+    WorkingDir            string              `json:"working_dir"`
+    ValidatorTimeoutsMs   map[string]int32    `json:"validator_timeouts_ms"`
+}
+
+// validationJSON is the document returned by POST /v1/validate
+type validationJSON struct {
+    Success         bool          `json:"success"`
+    ErrorMessage    string        `json:"error_message,omitempty"`
+    ExecutionTimeMs int64         `json:"execution_time_ms"`
+    Summary         summaryJSON   `json:"summary"`
+    Metadata        *metadataJSON `json:"metadata,omitempty"`
+    Results         []resultJSON  `json:"results"`
+    ChangedFiles    []string      `json:"changed_files,omitempty"`
+    Warnings        []string      `json:"warnings,omitempty"`
+    CacheHit        bool          `json:"cache_hit"`
+    RunID           string        `json:"run_id,omitempty"`
+
+    // Manifest is the RunManifest in protobuf JSON form with proto field names
+    Manifest json.RawMessage `json:"manifest,omitempty"`
+}
+
+type summaryJSON struct {
+    Total          int32 `json:"total"`
+    Passed         int32 `json:"passed"`
+    Failed         int32 `json:"failed"`
+    Skipped        int32 `json:"skipped"`
+    MaxConcurrency int32 `json:"max_concurrency"`
+    QueueWaitMs    int64 `json:"queue_wait_ms"`
+}
+
+type metadataJSON struct {
+    ProjectType    string            `json:"project_type"`
+    ProjectRoot    string            `json:"project_root"`
+    Language       string            `json:"language"`
+    ConfigFiles    []string          `json:"config_files"`
+    Commands       map[string]string `json:"commands"`
+    Warnings       []string          `json:"warnings,omitempty"`
+    ProjectName    string            `json:"project_name,omitempty"`
+    Binaries       []string          `json:"binaries,omitempty"`
+    ModulePath     string            `json:"module_path,omitempty"`
+    PackageManager string            `json:"package_manager,omitempty"`
+    Fingerprint    string            `json:"fingerprint,omitempty"`
+}
+
+type resultJSON struct {
+    Validator       string           `json:"validator"`
+    Success         bool             `json:"success"`
+    Skipped         bool             `json:"skipped"`
+    SkipReason      string           `json:"skip_reason,omitempty"`
+    Output          string           `json:"output"`
+    Stdout          string           `json:"stdout"`
+    Stderr          string           `json:"stderr"`
+    Truncated       bool             `json:"truncated"`
+    Error           string           `json:"error,omitempty"`
+    ExecutionTimeMs int64            `json:"execution_time_ms"`
+    ResolvedArgv    []string         `json:"resolved_argv"`
+    CommandForm     string           `json:"command_form,omitempty"`
+    WorkDir         string           `json:"work_dir,omitempty"`
+    DryRun          bool             `json:"dry_run,omitempty"`
+    AttemptCount    int32            `json:"attempt_count"`
+    Transient       bool             `json:"transient"`
+    StartFailed     bool             `json:"start_failed"`
+    StartFailure    string           `json:"start_failure_reason,omitempty"`
+    FailureReason   string           `json:"failure_reason,omitempty"`
+    ExitCode        int32            `json:"exit_code"`
+    Artifacts       []artifactJSON   `json:"artifacts,omitempty"`
+    Diagnostics     []diagnosticJSON `json:"diagnostics,omitempty"`
+}
+
+type diagnosticJSON struct {
+    File     string `json:"file"`
+    Line     int32  `json:"line,omitempty"`
+    Column   int32  `json:"column,omitempty"`
+    Severity string `json:"severity"`
+    Message  string `json:"message"`
+}
+
+// artifactJSON carries file content base64-encoded, as encoding/json does for []byte
+type artifactJSON struct {
+    Path           string `json:"path"`
+    SizeBytes      int64  `json:"size_bytes"`
+    Content        []byte `json:"content,omitempty"`
+    ContentOmitted bool   `json:"content_omitted"`
+}
+
+// errorJSON is returned for requests that could not be run at all
+type errorJSON struct {
+    Error string `json:"error"`
+}
+
+// newHTTPGateway builds the HTTP handler serving the JSON endpoints
+func newHTTPGateway(s *CCToolsServer, limits requestLimits, limiter *rateLimiter) http.Handler {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/debug/vars", func(w http.ResponseWriter, r *http.Request) {
+        if err := s.requireAdmin(httpCallContext(r.Context(), r)); err != nil {
+            code := http.StatusUnauthorized
+            if status.Code(err) == codes.PermissionDenied {
+                code = http.StatusForbidden
+            }
+            writeJSON(w, code, errorJSON{Error: status.Convert(err).Message()})
+            return
+        }
+        expvar.Handler().ServeHTTP(w, r)
+    })
+    mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+        writeHealth(w, r, s, "")
+    })
+    mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+        writeHealth(w, r, s, readinessService)
+    })
+    mux.HandleFunc("/v1/validate", func(w http.ResponseWriter, r *http.Request) {
+        ctx, requestID := withRequestID(httpCallContext(r.Context(), r), r.Header.Get("X-Request-ID"))
+        w.Header().Set("X-Request-ID", requestID)
+        if r.Method != http.MethodPost {
+            writeJSON(w, http.StatusMethodNotAllowed, errorJSON{Error: "use POST"})
+            return
+        }
+
+        var body validateRequestJSON
+        if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+            writeJSON(w, http.StatusBadRequest, errorJSON{Error: "invalid JSON body: " + err.Error()})
+            return
+        }
+        emptyRunPolicy, ok := parseEmptyRunPolicy(body.EmptyRunPolicy)
+        if !ok {
+            writeJSON(w, http.StatusBadRequest, errorJSON{Error: fmt.Sprintf("unknown empty_run_policy %q; use success, error or warn", body.EmptyRunPolicy)})
+            return
+        }
+
+        req := &pb.ValidationRequest{
+            ProjectRoot:           body.ProjectRoot,
+            HookType:              body.HookType,
+            FilePaths:             body.FilePaths,
+            Context:               body.Context,
+            TimeoutMs:             body.TimeoutMs,
+            LoginShell:            body.LoginShell,
+            LoginShellValidators:  body.LoginShellValidators,
+            MaxDurationHintMs:     body.MaxDurationHintMs,
+            OverrideCommands:      body.OverrideCommands,
+            Retries:               body.Retries,
+            FailuresOnly:          body.FailuresOnly,
+            Env:                   body.Env,
+            GitBaseRef:            body.GitBaseRef,
+            GitHeadRef:            body.GitHeadRef,
+            EmptyRunPolicy:        emptyRunPolicy,
+            ArtifactGlobs:         body.ArtifactGlobs,
+            CheckToolchain:        body.CheckToolchain,
+            Stdin:                 body.Stdin,
+            StdinFile:             body.StdinFile,
+            ChattyValidators:      body.ChattyValidators,
+            ForceColor:            body.ForceColor,
+            IncludeManifest:       body.IncludeManifest,
+            ContentionKeys:        body.ContentionKeys,
+            MaxParallelValidators: body.MaxParallelValidators,
+            UseCache:              body.UseCache,
+            DryRun:                body.DryRun,
+            RetryExitCodes:        body.RetryExitCodes,
+            WorkingDir:            body.WorkingDir,
+            ValidatorTimeoutsMs:   body.ValidatorTimeoutsMs,
+        }
+        if len(body.OverrideArgv) > 0 {
+            req.OverrideArgv = make(map[string]*pb.CommandArgv, len(body.OverrideArgv))
+            for name, args := range body.OverrideArgv {
+                req.OverrideArgv[name] = &pb.CommandArgv{Args: args}
+            }
+        }
+        if err := limits.check(req); err != nil {
+            writeJSON(w, http.StatusBadRequest, errorJSON{Error: status.Convert(err).Message()})
+            return
+        }
+        if err := limiter.allow(addrHost(r.RemoteAddr)); err != nil {
+            writeJSON(w, http.StatusTooManyRequests, errorJSON{Error: status.Convert(err).Message()})
+            return
+        }
+
+        resp, err := s.ValidateProject(ctx, req)
+        if err != nil {
+            code := http.StatusInternalServerError
+            switch status.Code(err) {
+            case codes.InvalidArgument, codes.FailedPrecondition:
+                code = http.StatusBadRequest
+            case codes.Unavailable:
+                code = http.StatusServiceUnavailable
+                w.Header().Set("Retry-After", strconv.Itoa(int(s.shedder.retryAfter.Seconds())))
+            case codes.ResourceExhausted:
+                code = http.StatusTooManyRequests
+            }
+            writeJSON(w, code, errorJSON{Error: status.Convert(err).Message()})
+            return
+        }
+        writeJSON(w, http.StatusOK, toValidationJSON(resp))
+    })
+    return mux
+}
+
+// healthJSON is returned by /healthz (liveness) and /readyz (readiness)
+type healthJSON struct {
+    Status    string `json:"status"`
+    Error     string `json:"error,omitempty"`      // Why the last readiness check failed (/readyz only)
+    CheckedAt int64  `json:"checked_at,omitempty"` // Unix time of the last readiness check (/readyz only)
+}
+
+// writeHealth reports the gRPC health status of service: 200 when
+// SERVING, 503 otherwise
+func writeHealth(w http.ResponseWriter, r *http.Request, s *CCToolsServer, service string) {
+    out := healthJSON{Status: healthpb.HealthCheckResponse_NOT_SERVING.String()}
+    if s.health != nil {
+        if resp, err := s.health.Check(r.Context(), &healthpb.HealthCheckRequest{Service: service}); err == nil {
+            out.Status = resp.Status.String()
+        }
+    }
+    if service == readinessService {
+        if checkedAt, err := s.readiness.result(); !checkedAt.IsZero() {
+            out.CheckedAt = checkedAt.Unix()
+            if err != nil {
+                out.Error = err.Error()
+            }
+        }
+    }
+
+    code := http.StatusOK
+    if out.Status != healthpb.HealthCheckResponse_SERVING.String() {
+        code = http.StatusServiceUnavailable
+    }
+    writeJSON(w, code, out)
+}
+
+// toValidationJSON converts a ValidationResponse into its stable JSON form
+func toValidationJSON(resp *pb.ValidationResponse) validationJSON {
+    out := validationJSON{
+        Success:         resp.Success,
+        ErrorMessage:    resp.ErrorMessage,
+        ExecutionTimeMs: resp.ExecutionTimeMs,
+        Results:         make([]resultJSON, 0, len(resp.Results)),
+        ChangedFiles:    resp.ChangedFiles,
+        Warnings:        resp.Warnings,
+        CacheHit:        resp.CacheHit,
+        RunID:           resp.RunId,
+    }
+
+    if resp.Manifest != nil {
+        if manifest, err := (protojson.MarshalOptions{UseProtoNames: true}).Marshal(resp.Manifest); err == nil {
+            out.Manifest = manifest
+        }
+    }
+
+    if summary := resp.Summary; summary != nil {
+        out.Summary = summaryJSON{
+            Total:          summary.Total,
+            Passed:         summary.Passed,
+            Failed:         summary.Failed,
+            Skipped:        summary.Skipped,
+            MaxConcurrency: summary.MaxConcurrency,
+            QueueWaitMs:    summary.QueueWaitMs,
+        }
+    }
+
+    if md := resp.Metadata; md != nil {
+        out.Metadata = &metadataJSON{
+            ProjectType:    md.ProjectType,
+            ProjectRoot:    md.ProjectRoot,
+            Language:       md.Language,
+            ConfigFiles:    nonNilStrings(md.ConfigFiles),
+            Commands:       md.Commands,
+            Warnings:       md.Warnings,
+            ProjectName:    md.ProjectName,
+            Binaries:       md.Binaries,
+            ModulePath:     md.ModulePath,
+            PackageManager: md.PackageManager,
+            Fingerprint:    md.Fingerprint,
+        }
+        if out.Metadata.Commands == nil {
+            out.Metadata.Commands = map[string]string{}
+        }
+    }
+
+    for _, result := range resp.Results {
+        r := resultJSON{
+            Validator:       result.Validator,
+            Success:         result.Success,
+            Skipped:         result.Skipped,
+            Output:          result.Output,
+            Stdout:          result.Stdout,
+            Stderr:          result.Stderr,
+            Truncated:       result.Truncated,
+            Error:           result.Error,
+            ExecutionTimeMs: result.ExecutionTimeMs,
+            ResolvedArgv:    nonNilStrings(result.ResolvedArgv),
+            CommandForm:     result.CommandForm,
+            WorkDir:         result.WorkDir,
+            DryRun:          result.DryRun,
+            AttemptCount:    result.AttemptCount,
+            Transient:       result.Transient,
+            ExitCode:        result.ExitCode,
+        }
+        if result.FailureReason != pb.FailureReason_FAILURE_REASON_UNSPECIFIED {
+            r.FailureReason = result.FailureReason.String()
+        }
+        if result.Skipped {
+            r.SkipReason = result.SkipReason.String()
+        }
+        if result.StartFailed {
+            r.StartFailure = result.StartFailureReason.String()
+        }
+        for _, artifact := range result.Artifacts {
+            r.Artifacts = append(r.Artifacts, artifactJSON{
+                Path:           artifact.Path,
+                SizeBytes:      artifact.SizeBytes,
+                Content:        artifact.Content,
+                ContentOmitted: artifact.ContentOmitted,
+            })
+        }
+        for _, d := range result.Diagnostics {
+            r.Diagnostics = append(r.Diagnostics, diagnosticJSON{
+                File:     d.File,
+                Line:     d.Line,
+                Column:   d.Column,
+                Severity: d.Severity,
+                Message:  d.Message,
+            })
+        }
+        out.Results = append(out.Results, r)
+    }
+
+    return out
+}
+
+func nonNilStrings(values []string) []string {
+    if values == nil {
+        return []string{}
+    }
+    return values
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(code)
+    if err := json.NewEncoder(w).Encode(v); err != nil {
+        slog.Warn("http gateway: failed to write response", "status", code, "error", err)
+    }
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "net"
+    "net/http"
+    "net/http/httptest"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+)
+
+// freePort returns a TCP port that was free a moment ago
+func freePort(t *testing.T) string {
+    t.Helper()
+    lis, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer lis.Close()
+    return strconv.Itoa(lis.Addr().(*net.TCPAddr).Port)
+}
+
+// waitForHTTP polls url until it answers or the deadline passes
+func waitForHTTP(t *testing.T, client *http.Client, url string) *http.Response {
+    t.Helper()
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        resp, err := client.Get(url)
+        if err == nil {
+            return resp
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("GET %s: %v", url, err)
+        }
+        time.Sleep(20 * time.Millisecond)
+    }
+}
+
+func TestHTTPGatewayRejectsUnknownEmptyRunPolicy(t *testing.T) {
+    gateway := newHTTPGateway(NewCCToolsServer(), loadRequestLimits(), loadRateLimiter())
+    root := makeProject(t, map[string]string{"test": "true"})
+
+    rec := httptest.NewRecorder()
+    body := `{"project_root": "` + root + `", "empty_run_policy": "sucess"}`
+    gateway.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/validate", strings.NewReader(body)))
+    if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "empty_run_policy") {
+        t.Errorf("status %d body %s, want 400 naming empty_run_policy", rec.Code, rec.Body)
+    }
+}
+
+func TestHTTPGatewayDebugVarsRequiresAdmin(t *testing.T) {
+    get := func(t *testing.T, auth string) int {
+        t.Helper()
+        gateway := newHTTPGateway(NewCCToolsServer(), loadRequestLimits(), loadRateLimiter())
+        req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+        if auth != "" {
+            req.Header.Set("Authorization", auth)
+        }
+        rec := httptest.NewRecorder()
+        gateway.ServeHTTP(rec, req)
+        return rec.Code
+    }
+
+    t.Run("disabled without ADMIN_TOKEN", func(t *testing.T) {
+        t.Setenv("ADMIN_TOKEN", "")
+        if code := get(t, "Bearer anything"); code != http.StatusForbidden {
+            t.Errorf("status %d, want 403", code)
+        }
+    })
+    t.Run("token required", func(t *testing.T) {
+        t.Setenv("ADMIN_TOKEN", "s3cret")
+        if code := get(t, ""); code != http.StatusUnauthorized {
+            t.Errorf("no token: status %d, want 401", code)
+        }
+        if code := get(t, "Bearer wrong"); code != http.StatusUnauthorized {
+            t.Errorf("wrong token: status %d, want 401", code)
+        }
+        if code := get(t, "Bearer s3cret"); code != http.StatusOK {
+            t.Errorf("admin token: status %d, want 200", code)
+        }
+    })
+}
+
+func TestHTTPGatewayBindsLoopbackAndShutsDown(t *testing.T) {
+    port := freePort(t)
+    t.Setenv("HTTP_PORT", port)
+    t.Setenv("HTTP_BIND_ADDR", "")
+    t.Setenv("READINESS_INTERVAL_SECONDS", "0")
+    parts, err := newGRPCServer(serverConfig{})
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    gateway := startHTTPGateway(parts)
+    if gateway == nil {
+        t.Fatal("startHTTPGateway returned nil with HTTP_PORT set")
+    }
+    if want := "127.0.0.1:" + port; gateway.Addr != want {
+        t.Errorf("Addr = %q, want %q", gateway.Addr, want)
+    }
+    resp := waitForHTTP(t, http.DefaultClient, "http://127.0.0.1:"+port+"/healthz")
+    resp.Body.Close()
+
+    parts.tools.shutdown(parts.grpc, parts.health, gateway)
+    if resp, err := http.Get("http://127.0.0.1:" + port + "/healthz"); err == nil {
+        resp.Body.Close()
+        t.Error("gateway still answering after shutdown")
+    }
+}
+
+func TestHTTPGatewayNotStartedWithoutPort(t *testing.T) {
+    t.Setenv("HTTP_PORT", "")
+    if gateway := startHTTPGateway(&serverParts{}); gateway != nil {
+        t.Errorf("startHTTPGateway = %v, want nil", gateway)
+    }
+}
+
+func TestHTTPGatewayMutualTLS(t *testing.T) {
+    pki := newTestPKI(t)
+    pki.setEnv(t, true)
+    port := freePort(t)
+    t.Setenv("HTTP_PORT", port)
+    t.Setenv("READINESS_INTERVAL_SECONDS", "0")
+    parts, err := newGRPCServer(serverConfig{tls: true})
+    if err != nil {
+        t.Fatal(err)
+    }
+    gateway := startHTTPGateway(parts)
+    t.Cleanup(func() { gateway.Close() })
+    url := "https://localhost:" + port + "/healthz"
+
+    withCert := &http.Client{Transport: &http.Transport{TLSClientConfig: pki.clientConfig(pki.clientCert(t, "team-a"))}}
+    resp := waitForHTTP(t, withCert, url)
+    resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        t.Errorf("with client cert: status %d, want 200", resp.StatusCode)
+    }
+
+    withoutCert := &http.Client{Transport: &http.Transport{TLSClientConfig: pki.clientConfig()}}
+    if resp, err := withoutCert.Get(url); err == nil {
+        resp.Body.Close()
+        t.Error("request without a client certificate succeeded")
+    }
+    plain := "http://localhost:" + port + "/healthz"
+    if resp, err := http.Get(plain); err == nil {
+        if resp.StatusCode == http.StatusOK {
+            t.Error("plaintext request succeeded against the TLS gateway")
+        }
+        resp.Body.Close()
+    }
+}
+
+func TestHTTPCallContextCarriesIdentity(t *testing.T) {
+    pki := newTestPKI(t)
+    cert := pki.clientCert(t, "team-a")
+    leaf, err := x509.ParseCertificate(cert.Certificate[0])
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    req := httptest.NewRequest(http.MethodPost, "/v1/validate", nil)
+    req.TLS = &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{leaf, pki.ca}}}
+    if got := identityFromContext(httpCallContext(req.Context(), req)); got != "team-a" {
+        t.Errorf("identity = %q, want team-a", got)
+    }
+
+    plain := httptest.NewRequest(http.MethodPost, "/v1/validate", nil)
+    if got := identityFromContext(httpCallContext(plain.Context(), plain)); got != "" {
+        t.Errorf("plaintext identity = %q, want empty", got)
+    }
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+    "context"
+
+    "google.golang.org/grpc/credentials"
+    "google.golang.org/grpc/peer"
+)
+
+// identityFromContext returns the authenticated caller's identity: the
+// common name of a verified TLS client certificate. It is empty for
+// plaintext or unauthenticated connections.
+func identityFromContext(ctx context.Context) string {
+    p, ok := peer.FromContext(ctx)
+    if !ok {
+        return ""
+    }
+    tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+    if !ok || len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
+        return ""
+    }
+    return tlsInfo.State.VerifiedChains[0][0].Subject.CommonName
+}
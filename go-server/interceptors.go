@@ -2,14 +2,21 @@ package main
 
 import (
     "context"
-    "log"
+    "fmt"
+    "log/slog"
+    "runtime/debug"
     "time"
 
     "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
     "google.golang.org/grpc/peer"
     "google.golang.org/grpc/status"
 )
 
+// loggingUnaryInterceptor logs every call. It also assigns the call its
+// request id (the caller's x-request-id, or a generated one), makes it
+// available through RequestIDFromContext and echoes it in the trailer.
 func loggingUnaryInterceptor(
     ctx context.Context,
     req interface{},
@@ -17,6 +24,8 @@ func loggingUnaryInterceptor(
     handler grpc.UnaryHandler,
 ) (interface{}, error) {
     start := time.Now()
+    ctx, requestID := withRequestID(ctx, incomingRequestID(ctx))
+    grpc.SetTrailer(ctx, metadata.Pairs(requestIDHeader, requestID))
     p, _ := peer.FromContext(ctx)
     resp, err := handler(ctx, req)
     s, _ := status.FromError(err)
@@ -25,10 +34,11 @@ func loggingUnaryInterceptor(
     if p != nil {
         peerAddr = p.Addr.String()
     }
-    log.Printf("grpc unary: method=%s code=%s dur_ms=%d peer=%s", info.FullMethod, s.Code().String(), dur.Milliseconds(), peerAddr)
+    slog.InfoContext(ctx, "grpc unary", "method", info.FullMethod, "code", s.Code().String(), "dur_ms", dur.Milliseconds(), "peer", peerAddr, "request_id", requestID)
     return resp, err
 }
 
+// loggingStreamInterceptor is loggingUnaryInterceptor for streams
 func loggingStreamInterceptor(
     srv interface{},
     ss grpc.ServerStream,
@@ -36,15 +46,103 @@ func loggingStreamInterceptor(
     handler grpc.StreamHandler,
 ) error {
     start := time.Now()
-    p, _ := peer.FromContext(ss.Context())
-    err := handler(srv, ss)
+    ctx, requestID := withRequestID(ss.Context(), incomingRequestID(ss.Context()))
+    ss.SetTrailer(metadata.Pairs(requestIDHeader, requestID))
+    p, _ := peer.FromContext(ctx)
+    err := handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
     s, _ := status.FromError(err)
     dur := time.Since(start)
     peerAddr := ""
     if p != nil {
         peerAddr = p.Addr.String()
     }
-    log.Printf("grpc stream: method=%s code=%s dur_ms=%d peer=%s", info.FullMethod, s.Code().String(), dur.Milliseconds(), peerAddr)
+    slog.InfoContext(ctx, "grpc stream", "method", info.FullMethod, "code", s.Code().String(), "dur_ms", dur.Milliseconds(), "peer", peerAddr, "request_id", requestID)
     return err
 }
 
+// contextServerStream replaces the context of a server stream
+type contextServerStream struct {
+    grpc.ServerStream
+    ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+    return s.ctx
+}
+
+// loggingRecoveryUnaryInterceptor turns a panicking handler into an Internal
+// error, logging the panic and its stack, instead of crashing the server.
+// Panics in goroutines a handler starts are not covered.
+func loggingRecoveryUnaryInterceptor(
+    ctx context.Context,
+    req interface{},
+    info *grpc.UnaryServerInfo,
+    handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+    defer func() {
+        if r := recover(); r != nil {
+            err = recoveredPanic(ctx, info.FullMethod, r)
+        }
+    }()
+    return handler(ctx, req)
+}
+
+// loggingRecoveryStreamInterceptor is loggingRecoveryUnaryInterceptor for streams
+func loggingRecoveryStreamInterceptor(
+    srv interface{},
+    ss grpc.ServerStream,
+    info *grpc.StreamServerInfo,
+    handler grpc.StreamHandler,
+) (err error) {
+    defer func() {
+        if r := recover(); r != nil {
+            err = recoveredPanic(ss.Context(), info.FullMethod, r)
+        }
+    }()
+    return handler(srv, ss)
+}
+
+func recoveredPanic(ctx context.Context, method string, r interface{}) error {
+    slog.ErrorContext(ctx, "grpc panic", "method", method, "request_id", RequestIDFromContext(ctx), "panic", fmt.Sprint(r), "stack", string(debug.Stack()))
+    return status.Errorf(codes.Internal, "internal error in %s", method)
+}
+
+// requestLimitsUnaryInterceptor rejects requests whose fields exceed the configured limits
+func requestLimitsUnaryInterceptor(limits requestLimits) grpc.UnaryServerInterceptor {
+    return func(
+        ctx context.Context,
+        req interface{},
+        info *grpc.UnaryServerInfo,
+        handler grpc.UnaryHandler,
+    ) (interface{}, error) {
+        if err := limits.check(req); err != nil {
+            return nil, err
+        }
+        return handler(ctx, req)
+    }
+}
+
+// requestLimitsStreamInterceptor applies the same limits to every message received on a stream
+func requestLimitsStreamInterceptor(limits requestLimits) grpc.StreamServerInterceptor {
+    return func(
+        srv interface{},
+        ss grpc.ServerStream,
+        info *grpc.StreamServerInfo,
+        handler grpc.StreamHandler,
+    ) error {
+        return handler(srv, &limitedServerStream{ServerStream: ss, limits: limits})
+    }
+}
+
+// limitedServerStream checks each received message against the request limits
+type limitedServerStream struct {
+    grpc.ServerStream
+    limits requestLimits
+}
+
+func (s *limitedServerStream) RecvMsg(m interface{}) error {
+    if err := s.ServerStream.RecvMsg(m); err != nil {
+        return err
+    }
+    return s.limits.check(m)
+}
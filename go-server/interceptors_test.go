@@ -0,0 +1,76 @@
+package main
+
+import (
+    "context"
+    "net"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/status"
+    "google.golang.org/grpc/test/bufconn"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// panickingServer panics in GetProjectMetadata and StreamValidation and
+// answers CheckLock normally
+type panickingServer struct {
+    pb.UnimplementedCCToolsIntegrationServer
+}
+
+func (panickingServer) GetProjectMetadata(context.Context, *pb.ValidationRequest) (*pb.ProjectMetadata, error) {
+    var metadata *pb.ProjectMetadata
+    _ = metadata.ProjectRoot // nil dereference
+    return metadata, nil
+}
+
+func (panickingServer) StreamValidation(*pb.ValidationRequest, grpc.ServerStreamingServer[pb.ValidationEvent]) error {
+    panic("stream handler failed")
+}
+
+func (panickingServer) CheckLock(context.Context, *pb.LockRequest) (*pb.LockStatus, error) {
+    return &pb.LockStatus{}, nil
+}
+
+func TestRecoveryInterceptorsReturnInternal(t *testing.T) {
+    server := grpc.NewServer(
+        grpc.ChainUnaryInterceptor(loggingUnaryInterceptor, loggingRecoveryUnaryInterceptor),
+        grpc.ChainStreamInterceptor(loggingStreamInterceptor, loggingRecoveryStreamInterceptor),
+    )
+    pb.RegisterCCToolsIntegrationServer(server, panickingServer{})
+    lis := bufconn.Listen(1 << 20)
+    go server.Serve(lis)
+    defer server.Stop()
+
+    conn, err := grpc.NewClient("passthrough:///bufconn",
+        grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+            return lis.DialContext(ctx)
+        }),
+        grpc.WithTransportCredentials(insecure.NewCredentials()),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer conn.Close()
+    client := pb.NewCCToolsIntegrationClient(conn)
+    ctx := context.Background()
+
+    if _, err := client.GetProjectMetadata(ctx, &pb.ValidationRequest{}); status.Code(err) != codes.Internal {
+        t.Errorf("panicking unary handler = %v, want Internal", err)
+    }
+
+    stream, err := client.StreamValidation(ctx, &pb.ValidationRequest{})
+    if err != nil {
+        t.Fatalf("StreamValidation: %v", err)
+    }
+    if _, err := stream.Recv(); status.Code(err) != codes.Internal {
+        t.Errorf("panicking stream handler = %v, want Internal", err)
+    }
+
+    // The server survived and the connection is still usable
+    if _, err := client.CheckLock(ctx, &pb.LockRequest{}); err != nil {
+        t.Errorf("CheckLock after the panics: %v", err)
+    }
+}
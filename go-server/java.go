@@ -0,0 +1,76 @@
+package main
+
+import (
+    "encoding/xml"
+    "os"
+    "path"
+    "path/filepath"
+    "regexp"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// rootOnlyProjectTypes only match a marker at the project root unless
+// MARKER_SEARCH_DEPTHS says otherwise. In a multi-module build every module
+// has its own pom.xml or build.gradle, and a nested match would validate
+// one module instead of the whole build. Nested Bazel workspaces are
+// usually test fixtures or vendored repositories.
+var rootOnlyProjectTypes = map[string]bool{"maven": true, "gradle": true, "bazel": true}
+
+// gradleBuildFiles are the Gradle markers, Groovy DSL before Kotlin DSL
+var gradleBuildFiles = []string{"build.gradle", "build.gradle.kts"}
+
+// gradleRootProjectPattern reads rootProject.name from settings.gradle(.kts)
+var gradleRootProjectPattern = regexp.MustCompile(`rootProject\.name\s*=\s*["']([^"']+)["']`)
+
+// detectGradle refines a Gradle project found in dir. The project's
+// wrapper pins the Gradle version, so it is preferred over a gradle binary
+// on PATH.
+func (s *CCToolsServer) detectGradle(metadata *pb.ProjectMetadata, dir, buildFile string) {
+    root := filepath.Join(metadata.ProjectRoot, dir)
+    for _, settings := range []string{"settings.gradle", "settings.gradle.kts"} {
+        if s.fileExists(filepath.Join(root, settings)) {
+            metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(dir, settings))
+        }
+    }
+
+    gradle := "gradle"
+    if s.fileExists(filepath.Join(root, "gradlew")) {
+        gradle = "./gradlew"
+    }
+    metadata.Commands["lint"] = gradle + " check"
+    metadata.Commands["test"] = gradle + " test"
+}
+
+// mavenProjectName reads the top-level artifactId of pom.xml
+func mavenProjectName(dir string) (string, []string, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "pom.xml"))
+    if err != nil {
+        return "", nil, err
+    }
+    var pom struct {
+        ArtifactID string `xml:"artifactId"`
+    }
+    if err := xml.Unmarshal(data, &pom); err != nil {
+        return "", nil, err
+    }
+    return pom.ArtifactID, nil, nil
+}
+
+// gradleProjectName reads rootProject.name from the settings script; Gradle
+// otherwise names the project after its directory
+func gradleProjectName(dir string) (string, []string, error) {
+    for _, settings := range []string{"settings.gradle", "settings.gradle.kts"} {
+        data, err := os.ReadFile(filepath.Join(dir, settings))
+        if os.IsNotExist(err) {
+            continue
+        }
+        if err != nil {
+            return "", nil, err
+        }
+        if m := gradleRootProjectPattern.FindSubmatch(data); m != nil {
+            return string(m[1]), nil, nil
+        }
+    }
+    return filepath.Base(dir), nil, nil
+}
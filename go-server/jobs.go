@@ -0,0 +1,140 @@
+package main
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "errors"
+    "path/filepath"
+    "sort"
+    "sync"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/metadata"
+)
+
+// runIDHeader carries a validation's run id in the response headers
+const runIDHeader = "x-run-id"
+
+// errAborted is the cancellation cause recorded when an operator aborts runs
+var errAborted = errors.New("aborted by operator")
+
+// errShutdown is the cancellation cause recorded when shutdown kills runs
+var errShutdown = errors.New("aborted by server shutdown")
+
+// errRunCancelled is the cancellation cause recorded by CancelValidation
+var errRunCancelled = errors.New("cancelled by CancelValidation")
+
+// validationJob is a validation run that is currently executing
+type validationJob struct {
+    id          string
+    projectRoot string
+    identity    string // caller's TLS identity, "" for unauthenticated callers
+    startedAt   time.Time
+    cancel      context.CancelCauseFunc
+}
+
+// jobRegistry tracks in-flight validation runs so they can be cancelled
+type jobRegistry struct {
+    mu   sync.Mutex
+    jobs map[string]*validationJob
+}
+
+func newJobRegistry() *jobRegistry {
+    return &jobRegistry{jobs: make(map[string]*validationJob)}
+}
+
+// start registers a run and returns its cancellable context. The returned
+// finish func must be called when the run completes.
+func (r *jobRegistry) start(parent context.Context, projectRoot string) (context.Context, *validationJob, func()) {
+    ctx, cancel := context.WithCancelCause(parent)
+    job := &validationJob{
+        id:          newJobID(),
+        projectRoot: normalizeRoot(projectRoot),
+        identity:    identityFromContext(parent),
+        startedAt:   time.Now(),
+        cancel:      cancel,
+    }
+
+    r.mu.Lock()
+    r.jobs[job.id] = job
+    r.mu.Unlock()
+
+    finish := func() {
+        r.mu.Lock()
+        delete(r.jobs, job.id)
+        r.mu.Unlock()
+        cancel(nil)
+    }
+    return ctx, job, finish
+}
+
+// cancelAll cancels every registered run with the given cause and returns how many were cancelled
+func (r *jobRegistry) cancelAll(cause error) int {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    for _, job := range r.jobs {
+        job.cancel(cause)
+    }
+    return len(r.jobs)
+}
+
+// cancelProject cancels the runs of one project with the given cause and
+// returns their ids; with dryRun it only reports which runs match
+func (r *jobRegistry) cancelProject(projectRoot string, cause error, dryRun bool) []string {
+    root := normalizeRoot(projectRoot)
+
+    r.mu.Lock()
+    defer r.mu.Unlock()
+
+    ids := make([]string, 0)
+    for _, job := range r.jobs {
+        if job.projectRoot != root {
+            continue
+        }
+        if !dryRun {
+            job.cancel(cause)
+        }
+        ids = append(ids, job.id)
+    }
+    sort.Strings(ids)
+    return ids
+}
+
+// lookup returns the running job with the given id
+func (r *jobRegistry) lookup(id string) (*validationJob, bool) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    job, ok := r.jobs[id]
+    return job, ok
+}
+
+// sendRunID sends the run id in the response headers right away so the
+// caller can CancelValidation the run while it executes. It does nothing
+// outside a gRPC call and for all but the first run of a batch.
+func sendRunID(ctx context.Context, id string) {
+    _ = grpc.SendHeader(ctx, metadata.Pairs(runIDHeader, id))
+}
+
+// normalizeRoot makes project roots comparable: absolute and cleaned
+func normalizeRoot(projectRoot string) string {
+    if abs, err := filepath.Abs(projectRoot); err == nil {
+        return abs
+    }
+    return filepath.Clean(projectRoot)
+}
+
+// count returns the number of runs currently executing
+func (r *jobRegistry) count() int {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return len(r.jobs)
+}
+
+func newJobID() string {
+    b := make([]byte, 8)
+    _, _ = rand.Read(b)
+    return hex.EncodeToString(b)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+    "time"
+
+    "google.golang.org/grpc/keepalive"
+)
+
+// Keepalive
+//
+// The server pings a connection that has been quiet for
+// KEEPALIVE_TIME_SECONDS (60) and drops it when no answer arrives within
+// KEEPALIVE_TIMEOUT_SECONDS (20), so half-open connections from vanished
+// clients are reaped. Connections with no RPC for
+// KEEPALIVE_MAX_IDLE_SECONDS (300) are closed with a GOAWAY, and every
+// connection is recycled after KEEPALIVE_MAX_AGE_SECONDS (1800) so clients
+// rebalance across replicas. Validations can outlive a connection's age,
+// so RPCs in flight when it expires get KEEPALIVE_MAX_AGE_GRACE_SECONDS to
+// finish (0, the default, waits for them however long they take).
+//
+// Clients may ping at most every KEEPALIVE_MIN_PING_SECONDS (10), and
+// without an active RPC only when KEEPALIVE_PERMIT_WITHOUT_STREAM is true
+// (the default); a client pinging more often is disconnected. Setting any
+// of these durations to 0 leaves grpc's default in place: no limit for the
+// idle time, age and grace, a 2h ping interval, a 20s ping timeout and a
+// 5m minimum between client pings.
+
+// keepaliveParams are the server-side keepalive settings
+func keepaliveParams() keepalive.ServerParameters {
+    return keepalive.ServerParameters{
+        MaxConnectionIdle:     envSeconds("KEEPALIVE_MAX_IDLE_SECONDS", 300),
+        MaxConnectionAge:      envSeconds("KEEPALIVE_MAX_AGE_SECONDS", 1800),
+        MaxConnectionAgeGrace: envSeconds("KEEPALIVE_MAX_AGE_GRACE_SECONDS", 0),
+        Time:                  envSeconds("KEEPALIVE_TIME_SECONDS", 60),
+        Timeout:               envSeconds("KEEPALIVE_TIMEOUT_SECONDS", 20),
+    }
+}
+
+// keepalivePolicy is how often clients may ping the server
+func keepalivePolicy() keepalive.EnforcementPolicy {
+    return keepalive.EnforcementPolicy{
+        MinTime:             envSeconds("KEEPALIVE_MIN_PING_SECONDS", 10),
+        PermitWithoutStream: envBool("KEEPALIVE_PERMIT_WITHOUT_STREAM", true),
+    }
+}
+
+// envSeconds reads a duration in whole seconds; zero and negative values
+// become 0, which grpc reads as unset
+func envSeconds(key string, def int) time.Duration {
+    n := envInt(key, def)
+    if n <= 0 {
+        return 0
+    }
+    return time.Duration(n) * time.Second
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+    "testing"
+    "time"
+
+    "google.golang.org/grpc/keepalive"
+)
+
+func TestKeepaliveDefaults(t *testing.T) {
+    for _, key := range []string{
+        "KEEPALIVE_MAX_IDLE_SECONDS", "KEEPALIVE_MAX_AGE_SECONDS", "KEEPALIVE_MAX_AGE_GRACE_SECONDS",
+        "KEEPALIVE_TIME_SECONDS", "KEEPALIVE_TIMEOUT_SECONDS", "KEEPALIVE_MIN_PING_SECONDS", "KEEPALIVE_PERMIT_WITHOUT_STREAM",
+    } {
+        t.Setenv(key, "")
+    }
+
+    want := keepalive.ServerParameters{
+        MaxConnectionIdle: 5 * time.Minute,
+        MaxConnectionAge:  30 * time.Minute,
+        Time:              time.Minute,
+        Timeout:           20 * time.Second,
+    }
+    if got := keepaliveParams(); got != want {
+        t.Errorf("keepaliveParams() = %+v, want %+v", got, want)
+    }
+    if got, want := keepalivePolicy(), (keepalive.EnforcementPolicy{MinTime: 10 * time.Second, PermitWithoutStream: true}); got != want {
+        t.Errorf("keepalivePolicy() = %+v, want %+v", got, want)
+    }
+}
+
+func TestKeepaliveFromEnv(t *testing.T) {
+    t.Setenv("KEEPALIVE_MAX_IDLE_SECONDS", "60")
+    t.Setenv("KEEPALIVE_MAX_AGE_SECONDS", "0") // grpc's default: no limit
+    t.Setenv("KEEPALIVE_MAX_AGE_GRACE_SECONDS", "30")
+    t.Setenv("KEEPALIVE_TIME_SECONDS", "-5")
+    t.Setenv("KEEPALIVE_TIMEOUT_SECONDS", "5")
+    t.Setenv("KEEPALIVE_MIN_PING_SECONDS", "30")
+    t.Setenv("KEEPALIVE_PERMIT_WITHOUT_STREAM", "false")
+
+    want := keepalive.ServerParameters{
+        MaxConnectionIdle:     time.Minute,
+        MaxConnectionAgeGrace: 30 * time.Second,
+        Timeout:               5 * time.Second,
+    }
+    if got := keepaliveParams(); got != want {
+        t.Errorf("keepaliveParams() = %+v, want %+v", got, want)
+    }
+    if got, want := keepalivePolicy(), (keepalive.EnforcementPolicy{MinTime: 30 * time.Second}); got != want {
+        t.Errorf("keepalivePolicy() = %+v, want %+v", got, want)
+    }
+}
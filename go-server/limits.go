@@ -0,0 +1,311 @@
+package main
+
+import (
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// requestLimits bounds individual request fields so pathological input is
+// rejected before it reaches a handler
+type requestLimits struct {
+    maxPathLength     int // project_root, project_path and each file path
+    maxFieldLength    int // any other string field, map key or value
+    maxFilePaths      int // entries in file_paths (the changed-files list)
+    maxContextEntries int // entries in the context and env maps
+    maxListEntries    int // entries in validator name lists
+    maxOverrides      int // entries in override_commands and override_argv
+    maxArgs           int // arguments in a single override argv
+    maxBatchEntries   int // requests in a single batch call
+    maxRetries        int // retries per validator
+    maxStdinBytes     int // bytes in stdin
+
+    allowedRoots []string // project roots must lie under one of these (empty = anywhere)
+}
+
+// loadRequestLimits reads the limits from the environment with sane defaults
+func loadRequestLimits() requestLimits {
+    return requestLimits{
+        maxPathLength:     envInt("MAX_PATH_LENGTH", 4096),
+        maxFieldLength:    envInt("MAX_FIELD_LENGTH", 1024),
+        maxFilePaths:      envInt("MAX_FILE_PATHS", 10000),
+        maxContextEntries: envInt("MAX_CONTEXT_ENTRIES", 256),
+        maxListEntries:    envInt("MAX_LIST_ENTRIES", 64),
+        maxOverrides:      envInt("MAX_OVERRIDE_ENTRIES", 64),
+        maxArgs:           envInt("MAX_ARGV_ENTRIES", 256),
+        maxBatchEntries:   envInt("MAX_BATCH_ENTRIES", 256),
+        maxRetries:        envInt("MAX_RETRIES", 5),
+        maxStdinBytes:     envInt("MAX_STDIN_BYTES", 4<<20),
+        allowedRoots:      loadAllowedRoots(),
+    }
+}
+
+// check validates a request message, returning an InvalidArgument status on violation
+func (l requestLimits) check(req interface{}) error {
+    switch r := req.(type) {
+    case *pb.ValidationRequest:
+        return l.checkValidationRequest(r)
+    case *pb.BatchValidationRequest:
+        if len(r.Requests) > l.maxBatchEntries {
+            return status.Errorf(codes.InvalidArgument, "requests has %d entries, limit is %d", len(r.Requests), l.maxBatchEntries)
+        }
+        // Each entry's project_root is checked by the batch handler, so a
+        // bad root fails that entry rather than the whole batch
+        for _, entry := range r.Requests {
+            if err := l.checkValidationFields(entry); err != nil {
+                return err
+            }
+        }
+    case *pb.ValidatorDefinitionRequest:
+        if err := l.checkField("validator", r.Validator); err != nil {
+            return err
+        }
+        return l.checkValidationRequest(r.Request)
+    case *pb.ValidationLogRequest:
+        if err := l.checkField("run_id", r.RunId); err != nil {
+            return err
+        }
+        return l.checkField("validator", r.Validator)
+    case *pb.CancelValidationRequest:
+        if err := l.checkField("run_id", r.RunId); err != nil {
+            return err
+        }
+        return l.checkField("reason", r.Reason)
+    case *pb.CancelValidationsRequest:
+        if err := l.checkField("reason", r.Reason); err != nil {
+            return err
+        }
+        return l.checkRoot("project_root", r.ProjectRoot)
+    case *pb.LockAndValidateRequest:
+        if r.Lock != nil {
+            // An empty lock project_path defaults to the validated root
+            if err := l.checkLockRequest(r.Lock, r.Lock.ProjectPath == ""); err != nil {
+                return err
+            }
+        }
+        return l.checkValidationRequest(r.Validation)
+    case *pb.DiscoverSubProjectsRequest:
+        return l.checkRoot("project_root", r.ProjectRoot)
+    case *pb.ListLocksRequest:
+        return l.checkField("namespace", r.Namespace)
+    case *pb.LockRequest:
+        return l.checkLockRequest(r, false)
+    }
+    return nil
+}
+
+func (l requestLimits) checkLockRequest(r *pb.LockRequest, skipPath bool) error {
+    if r.TtlSeconds < 0 {
+        return status.Error(codes.InvalidArgument, "ttl_seconds must not be negative")
+    }
+    if err := l.checkField("namespace", r.Namespace); err != nil {
+        return err
+    }
+    if err := l.checkField("owner", r.Owner); err != nil {
+        return err
+    }
+    if err := l.checkField("hostname", r.Hostname); err != nil {
+        return err
+    }
+    if skipPath {
+        return nil
+    }
+    return l.checkRoot("project_path", r.ProjectPath)
+}
+
+func (l requestLimits) checkValidationRequest(r *pb.ValidationRequest) error {
+    if r == nil {
+        return nil
+    }
+    if err := l.checkRoot("project_root", r.ProjectRoot); err != nil {
+        return err
+    }
+    return l.checkValidationFields(r)
+}
+
+// checkValidationFields checks everything in a validation request but its
+// project_root
+func (l requestLimits) checkValidationFields(r *pb.ValidationRequest) error {
+    if r == nil {
+        return nil
+    }
+    if err := l.checkField("hook_type", r.HookType); err != nil {
+        return err
+    }
+    if err := l.checkField("output_prefix_format", r.OutputPrefixFormat); err != nil {
+        return err
+    }
+    if err := l.checkField("if_none_match", r.IfNoneMatch); err != nil {
+        return err
+    }
+    if err := l.checkField("git_base_ref", r.GitBaseRef); err != nil {
+        return err
+    }
+    if err := l.checkField("git_head_ref", r.GitHeadRef); err != nil {
+        return err
+    }
+    if r.DryRun && (r.GitBaseRef != "" || r.GitHeadRef != "") {
+        return status.Error(codes.InvalidArgument, "dry_run cannot be combined with git_base_ref or git_head_ref, which run git")
+    }
+    if len(r.Stdin) > l.maxStdinBytes {
+        return status.Errorf(codes.InvalidArgument, "stdin exceeds %d bytes", l.maxStdinBytes)
+    }
+    if r.StdinFile != "" {
+        if r.Stdin != nil {
+            return status.Error(codes.InvalidArgument, "stdin and stdin_file are mutually exclusive")
+        }
+        if err := l.checkPath("stdin_file", r.StdinFile); err != nil {
+            return err
+        }
+        if !validRelativePath(r.StdinFile) {
+            return status.Errorf(codes.InvalidArgument, "stdin_file %q must be relative to the project root", r.StdinFile)
+        }
+    }
+    if r.WorkingDir != "" {
+        if err := l.checkPath("working_dir", r.WorkingDir); err != nil {
+            return err
+        }
+        if !validRelativePath(r.WorkingDir) {
+            return status.Errorf(codes.InvalidArgument, "working_dir %q must be relative to the project root", r.WorkingDir)
+        }
+    }
+    if r.MaxParallelValidators < 0 {
+        return status.Error(codes.InvalidArgument, "max_parallel_validators must not be negative")
+    }
+    if r.Retries < 0 || int(r.Retries) > l.maxRetries {
+        return status.Errorf(codes.InvalidArgument, "retries must be between 0 and %d", l.maxRetries)
+    }
+
+    if len(r.FilePaths) > l.maxFilePaths {
+        return status.Errorf(codes.InvalidArgument, "file_paths has %d entries, limit is %d", len(r.FilePaths), l.maxFilePaths)
+    }
+    for _, p := range r.FilePaths {
+        if err := l.checkPath("file_paths", p); err != nil {
+            return err
+        }
+    }
+
+    if len(r.Context) > l.maxContextEntries {
+        return status.Errorf(codes.InvalidArgument, "context has %d entries, limit is %d", len(r.Context), l.maxContextEntries)
+    }
+    for k, v := range r.Context {
+        if err := l.checkField("context key", k); err != nil {
+            return err
+        }
+        if err := l.checkField("context value", v); err != nil {
+            return err
+        }
+    }
+
+    if len(r.Env) > l.maxContextEntries {
+        return status.Errorf(codes.InvalidArgument, "env has %d entries, limit is %d", len(r.Env), l.maxContextEntries)
+    }
+    for k, v := range r.Env {
+        if err := l.checkField("env key", k); err != nil {
+            return err
+        }
+        if err := l.checkField("env value", v); err != nil {
+            return err
+        }
+    }
+
+    if len(r.ContentionKeys) > l.maxOverrides {
+        return status.Errorf(codes.InvalidArgument, "contention_keys has %d entries, limit is %d", len(r.ContentionKeys), l.maxOverrides)
+    }
+    for name, key := range r.ContentionKeys {
+        if err := l.checkField("contention_keys key", name); err != nil {
+            return err
+        }
+        if err := l.checkField("contention_keys value", key); err != nil {
+            return err
+        }
+    }
+
+    if len(r.ValidatorTimeoutsMs) > l.maxOverrides {
+        return status.Errorf(codes.InvalidArgument, "validator_timeouts_ms has %d entries, limit is %d", len(r.ValidatorTimeoutsMs), l.maxOverrides)
+    }
+    for name, ms := range r.ValidatorTimeoutsMs {
+        if err := l.checkField("validator_timeouts_ms key", name); err != nil {
+            return err
+        }
+        if ms < 0 {
+            return status.Errorf(codes.InvalidArgument, "validator_timeouts_ms[%s] must not be negative", name)
+        }
+    }
+
+    if len(r.ArtifactGlobs) > l.maxListEntries {
+        return status.Errorf(codes.InvalidArgument, "artifact_globs has %d entries, limit is %d", len(r.ArtifactGlobs), l.maxListEntries)
+    }
+    for _, glob := range r.ArtifactGlobs {
+        if err := l.checkField("artifact_globs", glob); err != nil {
+            return err
+        }
+        if !validArtifactGlob(glob) {
+            return status.Errorf(codes.InvalidArgument, "artifact_globs entry %q must be relative to the project root", glob)
+        }
+    }
+
+    if len(r.RetryExitCodes) > l.maxListEntries {
+        return status.Errorf(codes.InvalidArgument, "retry_exit_codes has %d entries, limit is %d", len(r.RetryExitCodes), l.maxListEntries)
+    }
+    if len(r.ChattyValidators) > l.maxListEntries {
+        return status.Errorf(codes.InvalidArgument, "chatty_validators has %d entries, limit is %d", len(r.ChattyValidators), l.maxListEntries)
+    }
+    for _, name := range r.ChattyValidators {
+        if err := l.checkField("chatty_validators", name); err != nil {
+            return err
+        }
+    }
+
+    if len(r.LoginShellValidators) > l.maxListEntries {
+        return status.Errorf(codes.InvalidArgument, "login_shell_validators has %d entries, limit is %d", len(r.LoginShellValidators), l.maxListEntries)
+    }
+    for _, name := range r.LoginShellValidators {
+        if err := l.checkField("login_shell_validators", name); err != nil {
+            return err
+        }
+    }
+
+    if len(r.OverrideCommands)+len(r.OverrideArgv) > l.maxOverrides {
+        return status.Errorf(codes.InvalidArgument, "override_commands and override_argv have %d entries, limit is %d",
+            len(r.OverrideCommands)+len(r.OverrideArgv), l.maxOverrides)
+    }
+    for name, command := range r.OverrideCommands {
+        if err := l.checkField("override_commands key", name); err != nil {
+            return err
+        }
+        if err := l.checkField("override_commands value", command); err != nil {
+            return err
+        }
+    }
+    for name, argv := range r.OverrideArgv {
+        if err := l.checkField("override_argv key", name); err != nil {
+            return err
+        }
+        if len(argv.GetArgs()) > l.maxArgs {
+            return status.Errorf(codes.InvalidArgument, "override_argv[%s] has %d arguments, limit is %d", name, len(argv.GetArgs()), l.maxArgs)
+        }
+        for _, arg := range argv.GetArgs() {
+            if err := l.checkField("override_argv argument", arg); err != nil {
+                return err
+            }
+        }
+    }
+
+    return nil
+}
+
+func (l requestLimits) checkPath(field, value string) error {
+    if len(value) > l.maxPathLength {
+        return status.Errorf(codes.InvalidArgument, "%s exceeds %d bytes", field, l.maxPathLength)
+    }
+    return nil
+}
+
+func (l requestLimits) checkField(field, value string) error {
+    if len(value) > l.maxFieldLength {
+        return status.Errorf(codes.InvalidArgument, "%s exceeds %d bytes", field, l.maxFieldLength)
+    }
+    return nil
+}
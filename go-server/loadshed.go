@@ -0,0 +1,56 @@
+package main
+
+import (
+    "context"
+    "strconv"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+)
+
+// Backoff contract for clients
+//
+// The server rejects work for two different reasons and uses a distinct
+// code for each so retry policies can tell them apart:
+//
+//   - Unavailable, with a "retry-after" trailer (seconds): the server is
+//     saturated and is shedding load. This is transient and not the
+//     caller's fault; retry after the hinted delay, with jitter.
+//   - ResourceExhausted: the caller is over its rate limit or quota.
+//     Retrying soon will keep failing; back off exponentially or surface
+//     the error.
+//
+// Over HTTP the same split maps to 503 with a Retry-After header and 429.
+
+// retryAfterTrailer carries the load-shedding backoff hint in seconds
+const retryAfterTrailer = "retry-after"
+
+// loadShedder rejects new validations while too many are already running
+type loadShedder struct {
+    maxInflight int           // 0 disables shedding
+    retryAfter  time.Duration // hint returned to shed callers
+}
+
+func loadLoadShedder() loadShedder {
+    return loadShedder{
+        maxInflight: envInt("MAX_INFLIGHT_VALIDATIONS", 0),
+        retryAfter:  time.Duration(envInt("LOAD_SHED_RETRY_AFTER_SECONDS", 5)) * time.Second,
+    }
+}
+
+// shedLoad returns Unavailable, and sets the retry-after trailer, when
+// inflight validations are at the configured maximum
+func (l loadShedder) shedLoad(ctx context.Context, inflight int) error {
+    if l.maxInflight <= 0 || inflight < l.maxInflight {
+        return nil
+    }
+
+    seconds := strconv.Itoa(int(l.retryAfter.Seconds()))
+    // Fails outside a gRPC call (HTTP gateway, batch entries); the gateway
+    // sets its own Retry-After header instead
+    _ = grpc.SetTrailer(ctx, metadata.Pairs(retryAfterTrailer, seconds))
+    return status.Errorf(codes.Unavailable, "server overloaded: %d validations running, retry after %ss", inflight, seconds)
+}
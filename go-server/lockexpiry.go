@@ -0,0 +1,60 @@
+package main
+
+import (
+    "log/slog"
+    "time"
+)
+
+// defaultLockSweepInterval is how often expired locks are removed unless
+// LOCK_SWEEP_INTERVAL_SECONDS says otherwise
+const defaultLockSweepInterval = 30 * time.Second
+
+// expiresAt returns the Unix time the lock expires, or 0 when it never does
+func (l *LockInfo) expiresAt() int64 {
+    if l.TTLSeconds <= 0 {
+        return 0
+    }
+    return l.AcquiredAt + l.TTLSeconds
+}
+
+// expired reports whether the lock's TTL has passed; such a lock is free
+// to take even if its holder is still alive
+func (l *LockInfo) expired(now time.Time) bool {
+    expiresAt := l.expiresAt()
+    return expiresAt > 0 && now.Unix() >= expiresAt
+}
+
+// sweepExpired removes expired locks every interval, so a wedged holder
+// does not keep a project locked until someone next asks for it. It runs
+// for the life of the process; interval <= 0 disables it.
+func (lm *LockManager) sweepExpired(interval time.Duration) {
+    if interval <= 0 {
+        return
+    }
+    go func() {
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+        for range ticker.C {
+            lm.removeExpired(time.Now())
+        }
+    }()
+}
+
+// removeExpired drops every lock whose TTL has passed at now
+func (lm *LockManager) removeExpired(now time.Time) {
+    lm.mutex.Lock()
+    defer lm.mutex.Unlock()
+
+    for lockID, info := range lm.locks {
+        // With lock files the file is authoritative and may have changed hands
+        current, err := lm.lookup(lockID, info.ProjectPath, info.Namespace)
+        if err != nil || current == nil || !current.expired(now) {
+            continue
+        }
+        if err := lm.remove(lockID, info.ProjectPath, info.Namespace); err != nil {
+            slog.Error("lock sweeper: failed to remove expired lock", "project_path", info.ProjectPath, "namespace", info.Namespace, "error", err)
+            continue
+        }
+        slog.Info("lock sweeper: released expired lock", "project_path", info.ProjectPath, "namespace", info.Namespace, "owner", current.Owner)
+    }
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+    "encoding/json"
+    "errors"
+    "io/fs"
+    "os"
+    "path/filepath"
+)
+
+// lockFileName is the on-disk lock shared with external DevFlow tools
+const lockFileName = ".devflow.lock"
+
+// lockFileContent is the JSON document stored in a project's .devflow.lock
+type lockFileContent struct {
+    PID        int32  `json:"pid"`
+    AcquiredAt int64  `json:"acquired_at"`
+    Owner      string `json:"owner,omitempty"`
+    TTLSeconds int64  `json:"ttl_seconds,omitempty"`
+}
+
+// lookup returns the current holder of a lock, or nil when it is free.
+// With lock files enabled the file in the project root is authoritative.
+func (lm *LockManager) lookup(lockID, projectPath, namespace string) (*LockInfo, error) {
+    if !lm.useLockFiles {
+        return lm.locks[lockID], nil
+    }
+    return readLockFile(projectPath, namespace)
+}
+
+// store records a new holder. With lock files enabled the file is created
+// with O_EXCL, so it fails with fs.ErrExist if another process won the race.
+func (lm *LockManager) store(lockID string, info *LockInfo) error {
+    if lm.useLockFiles {
+        if err := createLockFile(info); err != nil {
+            return err
+        }
+    }
+    if _, held := lm.locks[lockID]; !held {
+        locksHeld.Inc()
+    }
+    lm.locks[lockID] = info
+    return nil
+}
+
+// remove drops the holder of a lock and wakes waiting acquirers
+func (lm *LockManager) remove(lockID, projectPath, namespace string) error {
+    if _, held := lm.locks[lockID]; held {
+        delete(lm.locks, lockID)
+        locksHeld.Dec()
+    }
+    if lm.released != nil {
+        close(lm.released)
+        lm.released = nil
+    }
+    if !lm.useLockFiles {
+        return nil
+    }
+    if err := os.Remove(filepath.Join(projectPath, lockFileBase(namespace))); err != nil && !errors.Is(err, fs.ErrNotExist) {
+        return err
+    }
+    return nil
+}
+
+// nextRelease returns a channel that is closed the next time any lock is
+// removed, so waiters retry at once instead of at their next poll. Locks
+// freed outside the server (lock files, dead holders) are still only
+// noticed by polling.
+func (lm *LockManager) nextRelease() <-chan struct{} {
+    lm.mutex.Lock()
+    defer lm.mutex.Unlock()
+    if lm.released == nil {
+        lm.released = make(chan struct{})
+    }
+    return lm.released
+}
+
+// readLockFile parses a project's lock file for namespace, returning nil if there is none
+func readLockFile(projectPath, namespace string) (*LockInfo, error) {
+    data, err := os.ReadFile(filepath.Join(projectPath, lockFileBase(namespace)))
+    if errors.Is(err, fs.ErrNotExist) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+
+    var content lockFileContent
+    if err := json.Unmarshal(data, &content); err != nil {
+        return nil, err
+    }
+    return &LockInfo{
+        ProcessID:   content.PID,
+        AcquiredAt:  content.AcquiredAt,
+        ProjectPath: projectPath,
+        Owner:       content.Owner,
+        Namespace:   namespace,
+        TTLSeconds:  content.TTLSeconds,
+    }, nil
+}
+
+// createLockFile writes .devflow.lock using exclusive creation
+func createLockFile(info *LockInfo) error {
+    data, err := json.Marshal(lockFileContent{
+        PID:        info.ProcessID,
+        AcquiredAt: info.AcquiredAt,
+        Owner:      info.Owner,
+        TTLSeconds: info.TTLSeconds,
+    })
+    if err != nil {
+        return err
+    }
+
+    path := filepath.Join(info.ProjectPath, lockFileBase(info.Namespace))
+    f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+    if err != nil {
+        return err
+    }
+    if _, err := f.Write(data); err != nil {
+        f.Close()
+        os.Remove(path)
+        return err
+    }
+    return f.Close()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+    "context"
+    "sort"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// ListLocks reports the locks held through this server in the caller's
+// namespace, or in every namespace for admins with all_namespaces. Locks
+// whose holder has died or whose TTL has passed are listed with is_locked
+// false rather than left out, so operators can decide to force-release
+// them. With LOCK_FILES, lock files created by external tools are not
+// listed since the server never saw them taken.
+func (s *CCToolsServer) ListLocks(ctx context.Context, req *pb.ListLocksRequest) (*pb.ListLocksResponse, error) {
+    if req.AllNamespaces {
+        if err := s.requireAdmin(ctx); err != nil {
+            return nil, err
+        }
+    }
+    namespace := s.lockNamespace(ctx, &pb.LockRequest{Namespace: req.Namespace})
+
+    s.lockManager.mutex.RLock()
+    defer s.lockManager.mutex.RUnlock()
+
+    now := time.Now()
+    locks := make([]*pb.LockStatus, 0, len(s.lockManager.locks))
+    for id, info := range s.lockManager.locks {
+        if !req.AllNamespaces && info.Namespace != namespace {
+            continue
+        }
+        // With lock files the file is authoritative and may have been removed
+        current, err := s.lockManager.lookup(id, info.ProjectPath, info.Namespace)
+        if err != nil || current == nil {
+            continue
+        }
+        alive := s.isProcessAlive(current.ProcessID)
+        locks = append(locks, &pb.LockStatus{
+            LockId:      id,
+            ProjectPath: current.ProjectPath,
+            Namespace:   current.Namespace,
+            ProcessId:   current.ProcessID,
+            AcquiredAt:  current.AcquiredAt,
+            IsLocked:    alive && !current.expired(now),
+            Owner:       current.Owner,
+            ExpiresAt:   current.expiresAt(),
+            HolderAlive: alive,
+        })
+    }
+    sort.Slice(locks, func(i, j int) bool { return locks[i].LockId < locks[j].LockId })
+
+    return &pb.ListLocksResponse{Locks: locks}, nil
+}
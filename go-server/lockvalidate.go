@@ -0,0 +1,70 @@
+package main
+
+import (
+    "context"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/proto"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// LockAndValidate takes the project lock, validates and releases the lock in
+// one call, closing the gap between separate AcquireLock and ValidateProject
+// calls. When the lock is held elsewhere no validator runs and the response
+// describes the holder.
+func (s *CCToolsServer) LockAndValidate(ctx context.Context, req *pb.LockAndValidateRequest) (*pb.LockAndValidateResponse, error) {
+    if req.Validation == nil {
+        return nil, status.Error(codes.InvalidArgument, "validation is required")
+    }
+    lockReq := &pb.LockRequest{ProjectPath: req.Validation.ProjectRoot}
+    if req.Lock != nil {
+        lockReq = proto.Clone(req.Lock).(*pb.LockRequest)
+        if lockReq.ProjectPath == "" {
+            lockReq.ProjectPath = req.Validation.ProjectRoot
+        }
+    }
+
+    lock, acquired, err := s.acquireLock(ctx, lockReq)
+    if err != nil {
+        return nil, err
+    }
+    if !acquired {
+        return &pb.LockAndValidateResponse{Lock: lock}, nil
+    }
+    // Release on every exit path, including validation errors and a
+    // cancelled or expired ctx
+    defer func() {
+        released, err := s.releaseIfHolder(lock)
+        if err != nil {
+            logCtx(ctx, "LockAndValidate: failed to release lock", "project_path", lock.ProjectPath, "error", err)
+        } else if !released {
+            logCtx(ctx, "LockAndValidate: lock expired during validation; leaving it to its new holder", "project_path", lock.ProjectPath)
+        }
+    }()
+
+    validation, err := s.ValidateProject(ctx, req.Validation)
+    if err != nil {
+        return nil, err
+    }
+    return &pb.LockAndValidateResponse{Lock: lock, Acquired: true, Validation: validation}, nil
+}
+
+// releaseIfHolder releases the lock held describes unless it has changed
+// hands: a TTL can run out during a long validation, after which the sweeper
+// or another acquirer takes the lock, and that holder's lock (and lock file)
+// must survive. The holder is identified by owner, PID and acquisition time.
+func (s *CCToolsServer) releaseIfHolder(held *pb.LockStatus) (bool, error) {
+    s.lockManager.mutex.Lock()
+    defer s.lockManager.mutex.Unlock()
+
+    current, err := s.lockManager.lookup(held.LockId, held.ProjectPath, held.Namespace)
+    if err != nil {
+        return false, err
+    }
+    if current == nil || current.Owner != held.Owner || current.ProcessID != held.ProcessId || current.AcquiredAt != held.AcquiredAt {
+        return false, nil
+    }
+    return true, s.lockManager.remove(held.LockId, held.ProjectPath, held.Namespace)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestLockAndValidateLeavesReacquiredLock(t *testing.T) {
+    for _, lockFiles := range []string{"false", "true"} {
+        t.Run("LOCK_FILES="+lockFiles, func(t *testing.T) {
+            t.Setenv("LOCK_FILES", lockFiles)
+            ts := newTestServer(t, serverConfig{})
+            ctx := context.Background()
+            root := makeProject(t, map[string]string{
+                "lint": "echo x > running; while [ ! -f release ]; do sleep 0.05; done",
+                "test": "true",
+            })
+            if err := os.WriteFile(filepath.Join(root, "release"), nil, 0o644); err != nil {
+                t.Fatal(err)
+            }
+
+            // Without expiry the lock is released once validation ends
+            resp, err := ts.client.LockAndValidate(ctx, &pb.LockAndValidateRequest{
+                Lock:       &pb.LockRequest{Owner: "validator"},
+                Validation: &pb.ValidationRequest{ProjectRoot: root},
+            })
+            if err != nil || !resp.Acquired || !resp.Validation.GetSuccess() {
+                t.Fatalf("LockAndValidate = %v, %v", resp, err)
+            }
+            if lock, err := ts.client.CheckLock(ctx, &pb.LockRequest{ProjectPath: root}); err != nil || lock.IsLocked {
+                t.Fatalf("after LockAndValidate: %v, %v; want unlocked", lock, err)
+            }
+
+            // This time the 1s TTL runs out mid-validation and another
+            // client takes the lock
+            if err := os.Remove(filepath.Join(root, "release")); err != nil {
+                t.Fatal(err)
+            }
+            done := make(chan *pb.LockAndValidateResponse, 1)
+            go func() {
+                resp, err := ts.client.LockAndValidate(ctx, &pb.LockAndValidateRequest{
+                    Lock:       &pb.LockRequest{Owner: "validator", TtlSeconds: 1},
+                    Validation: &pb.ValidationRequest{ProjectRoot: root},
+                })
+                if err != nil {
+                    t.Errorf("LockAndValidate: %v", err)
+                }
+                done <- resp
+            }()
+            waitForFile(t, filepath.Join(root, "running"))
+            other, err := ts.client.AcquireLock(ctx, &pb.LockRequest{ProjectPath: root, Owner: "other", TimeoutMs: 5000})
+            if err != nil || other.Owner != "other" {
+                t.Fatalf("AcquireLock after expiry = %v, %v", other, err)
+            }
+            if err := os.WriteFile(filepath.Join(root, "release"), nil, 0o644); err != nil {
+                t.Fatal(err)
+            }
+            if resp := <-done; !resp.GetAcquired() || !resp.GetValidation().GetSuccess() {
+                t.Errorf("LockAndValidate = %v", resp)
+            }
+
+            lock, err := ts.client.CheckLock(ctx, &pb.LockRequest{ProjectPath: root})
+            if err != nil || !lock.IsLocked || lock.Owner != "other" {
+                t.Errorf("after the expired LockAndValidate: %v, %v; want still held by other", lock, err)
+            }
+            if lockFiles == "true" {
+                if _, err := os.Stat(filepath.Join(root, lockFileBase(lock.GetNamespace()))); err != nil {
+                    t.Errorf("the new holder's lock file is gone: %v", err)
+                }
+            }
+        })
+    }
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+    "io"
+    "log/slog"
+    "os"
+    "strings"
+)
+
+// setupLogging installs the process-wide structured logger, writing to
+// stderr. Calls to the standard log package go through the same handler as
+// INFO messages.
+func setupLogging() {
+    slog.SetDefault(slog.New(newLogHandler(os.Stderr)))
+}
+
+// newLogHandler writes JSON lines to w, or human-readable text with
+// LOG_FORMAT=text, at LOG_LEVEL (debug, info, warn or error; default info)
+func newLogHandler(w io.Writer) slog.Handler {
+    level := slog.LevelInfo
+    if err := level.UnmarshalText([]byte(envString("LOG_LEVEL", "info"))); err != nil {
+        level = slog.LevelInfo
+    }
+    opts := &slog.HandlerOptions{Level: level}
+
+    if strings.EqualFold(os.Getenv("LOG_FORMAT"), "text") {
+        return slog.NewTextHandler(w, opts)
+    }
+    return slog.NewJSONHandler(w, opts)
+}
+
+// fatal logs msg at error level and exits
+func fatal(msg string, args ...interface{}) {
+    slog.Error(msg, args...)
+    os.Exit(1)
+}
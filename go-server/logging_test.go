@@ -0,0 +1,94 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "log/slog"
+    "strings"
+    "sync"
+    "testing"
+
+    "google.golang.org/grpc/metadata"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// syncBuffer is a bytes.Buffer safe for the server's logging goroutines
+type syncBuffer struct {
+    mu  sync.Mutex
+    buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.buf.String()
+}
+
+// captureLogs sends the default logger to a buffer for the rest of the test
+func captureLogs(t *testing.T) *syncBuffer {
+    t.Helper()
+    logs := &syncBuffer{}
+    previous := slog.Default()
+    slog.SetDefault(slog.New(newLogHandler(logs)))
+    t.Cleanup(func() { slog.SetDefault(previous) })
+    return logs
+}
+
+func TestUnaryRPCLogsJSONFields(t *testing.T) {
+    t.Setenv("LOG_FORMAT", "")
+    t.Setenv("LOG_LEVEL", "info")
+    logs := captureLogs(t)
+    ts := newTestServer(t, serverConfig{})
+
+    ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDHeader, "log-789")
+    if _, err := ts.client.CheckLock(ctx, &pb.LockRequest{ProjectPath: t.TempDir()}); err != nil {
+        t.Fatalf("CheckLock: %v", err)
+    }
+
+    var entry map[string]interface{}
+    for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+        var e map[string]interface{}
+        if err := json.Unmarshal([]byte(line), &e); err != nil {
+            t.Fatalf("log line is not JSON: %q", line)
+        }
+        if e["msg"] == "grpc unary" && e["method"] == pb.CCToolsIntegration_CheckLock_FullMethodName {
+            entry = e
+        }
+    }
+    if entry == nil {
+        t.Fatalf("no grpc unary line for CheckLock in:\n%s", logs)
+    }
+    for _, key := range []string{"time", "level", "method", "code", "dur_ms", "peer", "request_id"} {
+        if _, ok := entry[key]; !ok {
+            t.Errorf("log entry %v has no %q", entry, key)
+        }
+    }
+    if entry["code"] != "OK" || entry["request_id"] != "log-789" || entry["level"] != "INFO" {
+        t.Errorf("log entry %v, want code OK, request_id log-789 at INFO", entry)
+    }
+}
+
+func TestLogHandlerHonorsFormatAndLevel(t *testing.T) {
+    t.Setenv("LOG_FORMAT", "text")
+    t.Setenv("LOG_LEVEL", "warn")
+    var buf bytes.Buffer
+    logger := slog.New(newLogHandler(&buf))
+    logger.Info("hidden")
+    logger.Warn("shown", "request_id", "r1")
+
+    out := buf.String()
+    if strings.Contains(out, "hidden") {
+        t.Errorf("INFO line logged at LOG_LEVEL=warn: %q", out)
+    }
+    if !strings.Contains(out, "level=WARN msg=shown request_id=r1") {
+        t.Errorf("text output = %q, want a key=value WARN line", out)
+    }
+}
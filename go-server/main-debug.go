@@ -4,68 +4,78 @@
 package main
 
 import (
+    "context"
     "fmt"
-    "log"
+    "log/slog"
     "net"
     "os"
     "os/signal"
     "syscall"
-
-    "google.golang.org/grpc"
-    "google.golang.org/grpc/reflection"
-    health "google.golang.org/grpc/health"
-    healthpb "google.golang.org/grpc/health/grpc_health_v1"
-    pb "github.com/devflow/cc-tools-server/proto"
+    "time"
 )
 
 func main() {
+    setupLogging()
+    shutdownTracing := setupTracing()
+
     port := os.Getenv("GRPC_PORT")
     if port == "" {
         port = "50051"
     }
 
-    log.Printf("Starting CC-Tools gRPC server (debug mode)...")
-    log.Printf("Binding to 0.0.0.0:%s", port)
+    slog.Info("Starting CC-Tools gRPC server (debug mode)")
+    slog.Info("Binding", "addr", "0.0.0.0:"+port)
 
     lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%s", port))
     if err != nil {
-        log.Fatalf("Failed to listen: %v", err)
+        fatal("Failed to listen", "error", err)
     }
 
-    log.Printf("Successfully bound to %s", lis.Addr().String())
+    slog.Info("Successfully bound", "addr", lis.Addr().String())
 
-    grpcServer := grpc.NewServer(
-        grpc.MaxRecvMsgSize(4*1024*1024),
-        grpc.MaxSendMsgSize(4*1024*1024),
-        grpc.ChainUnaryInterceptor(loggingUnaryInterceptor),
-        grpc.ChainStreamInterceptor(loggingStreamInterceptor),
-    )
+    // Plaintext with reflection enabled for debugging
+    server, err := newGRPCServer(serverConfig{reflection: true})
+    if err != nil {
+        fatal("Failed to build server", "error", err)
+    }
+    grpcServer, ccToolsServer, hs := server.grpc, server.tools, server.health
+    slog.Info("Services registered successfully; reflection enabled")
 
-    ccToolsServer := NewCCToolsServer()
-    pb.RegisterCCToolsIntegrationServer(grpcServer, ccToolsServer)
+    // Periodic heartbeat for detecting a wedged server (0 disables)
+    if interval := envInt("HEARTBEAT_INTERVAL_SECONDS", 30); interval > 0 {
+        go ccToolsServer.runHeartbeat(time.Duration(interval) * time.Second)
+    }
 
-    // Health service registration
-    hs := health.NewServer()
-    healthpb.RegisterHealthServer(grpcServer, hs)
-    hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-    hs.SetServingStatus("cc_tools_integration.CCToolsIntegration", healthpb.HealthCheckResponse_SERVING)
+    // Optional Prometheus endpoint, on its own port so it can stay internal
+    if metricsPort := os.Getenv("METRICS_PORT"); metricsPort != "" {
+        serveMetrics(metricsPort)
+    }
 
-    // Enable reflection for debugging
-    reflection.Register(grpcServer)
-    log.Printf("Services registered successfully; reflection enabled")
+    // Optional JSON gateway for scripts that prefer plain HTTP
+    gateway := startHTTPGateway(server)
 
     // Graceful shutdown on SIGINT/SIGTERM
+    shutdownDone := make(chan struct{})
     go func() {
         sigCh := make(chan os.Signal, 1)
         signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
         <-sigCh
-        log.Printf("Shutting down gracefully...")
-        grpcServer.GracefulStop()
+        ccToolsServer.shutdown(grpcServer, hs, gateway)
+        close(shutdownDone)
     }()
 
-    log.Printf("CC-Tools gRPC server (debug) ready on port %s", port)
+    slog.Info("CC-Tools gRPC server (debug) ready", "port", port)
 
     if err := grpcServer.Serve(lis); err != nil {
-        log.Fatalf("Failed to serve: %v", err)
+        fatal("Failed to serve", "error", err)
+    }
+    // Serve returns as soon as shutdown begins; wait for the drain
+    <-shutdownDone
+
+    // Flush the spans of the last RPCs
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    if err := shutdownTracing(ctx); err != nil {
+        slog.Error("Failed to flush traces", "error", err)
     }
 }
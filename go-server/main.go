@@ -4,17 +4,19 @@
 package main
 
 import (
-    "log"
+    "context"
+    "log/slog"
     "net"
     "os"
-
-    "google.golang.org/grpc"
-    health "google.golang.org/grpc/health"
-    healthpb "google.golang.org/grpc/health/grpc_health_v1"
-    pb "github.com/devflow/cc-tools-server/proto"
+    "os/signal"
+    "syscall"
+    "time"
 )
 
 func main() {
+    setupLogging()
+    shutdownTracing := setupTracing()
+
     port := os.Getenv("GRPC_PORT")
     if port == "" {
         port = "50051"
@@ -22,29 +24,50 @@ func main() {
 
     lis, err := net.Listen("tcp", ":"+port)
     if err != nil {
-        log.Fatalf("Failed to listen: %v", err)
+        fatal("Failed to listen", "error", err)
+    }
+
+    server, err := newGRPCServer(serverConfig{tls: true})
+    if err != nil {
+        fatal("Failed to build server", "error", err)
     }
+    grpcServer, ccToolsServer, hs := server.grpc, server.tools, server.health
 
-    grpcServer := grpc.NewServer(
-        grpc.MaxRecvMsgSize(4*1024*1024),
-        grpc.MaxSendMsgSize(4*1024*1024),
-        grpc.ChainUnaryInterceptor(loggingUnaryInterceptor),
-        grpc.ChainStreamInterceptor(loggingStreamInterceptor),
-    )
-    ccToolsServer := NewCCToolsServer()
+    // Periodic heartbeat for detecting a wedged server (0 disables)
+    if interval := envInt("HEARTBEAT_INTERVAL_SECONDS", 30); interval > 0 {
+        go ccToolsServer.runHeartbeat(time.Duration(interval) * time.Second)
+    }
+
+    // Optional Prometheus endpoint, on its own port so it can stay internal
+    if metricsPort := os.Getenv("METRICS_PORT"); metricsPort != "" {
+        serveMetrics(metricsPort)
+    }
 
-    pb.RegisterCCToolsIntegrationServer(grpcServer, ccToolsServer)
+    // Optional JSON gateway for scripts that prefer plain HTTP
+    gateway := startHTTPGateway(server)
 
-    // Health service registration
-    hs := health.NewServer()
-    healthpb.RegisterHealthServer(grpcServer, hs)
-    // Set overall and service-specific statuses to SERVING
-    hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
-    hs.SetServingStatus("cc_tools_integration.CCToolsIntegration", healthpb.HealthCheckResponse_SERVING)
+    // Graceful shutdown on SIGINT/SIGTERM: drain or kill per SHUTDOWN_POLICY
+    shutdownDone := make(chan struct{})
+    go func() {
+        sigCh := make(chan os.Signal, 1)
+        signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+        <-sigCh
+        ccToolsServer.shutdown(grpcServer, hs, gateway)
+        close(shutdownDone)
+    }()
 
-    log.Printf("CC-Tools gRPC server listening on port %s", port)
+    slog.Info("CC-Tools gRPC server listening", "port", port, "transport", server.transport)
 
     if err := grpcServer.Serve(lis); err != nil {
-        log.Fatalf("Failed to serve: %v", err)
+        fatal("Failed to serve", "error", err)
+    }
+    // Serve returns as soon as shutdown begins; wait for the drain
+    <-shutdownDone
+
+    // Flush the spans of the last RPCs
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    if err := shutdownTracing(ctx); err != nil {
+        slog.Error("Failed to flush traces", "error", err)
     }
 }
@@ -0,0 +1,100 @@
+package main
+
+import (
+    "bufio"
+    "bytes"
+    "strings"
+)
+
+// tomlString returns the string value of key within [section] of a TOML
+// document. It understands only what manifests need: bare keys with
+// single- or double-quoted string values on one line. An empty section
+// means the top-level table.
+func tomlString(data []byte, section, key string) (string, bool) {
+    values := tomlStrings(data, section, key)
+    if len(values) == 0 {
+        return "", false
+    }
+    return values[0], true
+}
+
+// tomlStrings is tomlString returning every match, in document order. An
+// array of tables such as [[bin]] counts as section "bin", so this yields
+// the key's value from each entry.
+func tomlStrings(data []byte, section, key string) []string {
+    var values []string
+    current := ""
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        if strings.HasPrefix(line, "[") {
+            current = strings.TrimSpace(strings.Trim(line, "[]"))
+            continue
+        }
+        if current != section {
+            continue
+        }
+
+        k, v, ok := strings.Cut(line, "=")
+        if !ok || strings.TrimSpace(k) != key {
+            continue
+        }
+        if value, ok := unquoteTOML(strings.TrimSpace(v)); ok {
+            values = append(values, value)
+        }
+    }
+    return values
+}
+
+// tomlKeys returns the bare keys of [section], in document order
+func tomlKeys(data []byte, section string) []string {
+    var keys []string
+    current := ""
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if line == "" || strings.HasPrefix(line, "#") {
+            continue
+        }
+        if strings.HasPrefix(line, "[") {
+            current = strings.TrimSpace(strings.Trim(line, "[]"))
+            continue
+        }
+        if k, _, ok := strings.Cut(line, "="); ok && current == section {
+            keys = append(keys, strings.Trim(strings.TrimSpace(k), `"'`))
+        }
+    }
+    return keys
+}
+
+// tomlHasSection reports whether a TOML (or INI) document has [section]
+// or one of its subtables
+func tomlHasSection(data []byte, section string) bool {
+    scanner := bufio.NewScanner(bytes.NewReader(data))
+    for scanner.Scan() {
+        line := strings.TrimSpace(scanner.Text())
+        if !strings.HasPrefix(line, "[") {
+            continue
+        }
+        name := strings.TrimSpace(strings.Trim(line, "[]"))
+        if name == section || strings.HasPrefix(name, section+".") {
+            return true
+        }
+    }
+    return false
+}
+
+// unquoteTOML strips the quotes of a one-line TOML string, ignoring a trailing comment
+func unquoteTOML(v string) (string, bool) {
+    if len(v) < 2 || (v[0] != '"' && v[0] != '\'') {
+        return "", false
+    }
+    end := strings.IndexByte(v[1:], v[0])
+    if end < 0 {
+        return "", false
+    }
+    return v[1 : end+1], true
+}
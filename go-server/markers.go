@@ -0,0 +1,118 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "path"
+    "path/filepath"
+    "strconv"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// markerSkipDirs are never descended into while searching for marker files
+var markerSkipDirs = map[string]bool{
+    ".git":         true,
+    ".gradle":      true,
+    ".zig-cache":   true,
+    "__pycache__":  true,
+    ".tox":         true,
+    ".venv":        true,
+    "_build":       true,
+    "deps":         true,
+    "node_modules": true,
+    "target":       true,
+    "vendor":       true,
+    "venv":         true,
+    "zig-cache":    true,
+    "zig-out":      true,
+}
+
+// loadMarkerDepths reads the per-project-type search depth overrides
+// from MARKER_SEARCH_DEPTHS (e.g. "cargo=2,npm=1")
+func loadMarkerDepths() map[string]int {
+    depths := make(map[string]int)
+    for projectType, v := range envMap("MARKER_SEARCH_DEPTHS") {
+        if n, err := strconv.Atoi(v); err == nil && n > 0 {
+            depths[projectType] = n
+        }
+    }
+    return depths
+}
+
+// markerSearchDepth returns how many directory levels are searched for a
+// project type's marker file; 1 means the project root only
+func (s *CCToolsServer) markerSearchDepth(projectType string) int {
+    if depth, ok := s.markerDepths[projectType]; ok {
+        return depth
+    }
+    if rootOnlyProjectTypes[projectType] {
+        return 1
+    }
+    return s.markerDepth
+}
+
+// locateMarker finds the marker file for a project type, returning the
+// directory that holds it relative to the project root ("." for the root).
+// Disabled detectors never match, so detection falls through to the next.
+//
+// Filesystem errors do not fail detection. They are added to the metadata
+// warnings and the search carries on: unreadable directories are skipped,
+// and a marker that exists but cannot be read does not match, so a later
+// detector gets its chance instead of the project being misclassified.
+func (s *CCToolsServer) locateMarker(metadata *pb.ProjectMetadata, projectType, marker string) (string, bool) {
+    if s.disabledDetectors[projectType] {
+        return "", false
+    }
+    dir, ok, errs := findMarker(metadata.ProjectRoot, marker, s.markerSearchDepth(projectType))
+    for _, err := range errs {
+        metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("%s detection: %v", projectType, err))
+    }
+    if !ok {
+        return "", false
+    }
+
+    f, err := os.Open(filepath.Join(metadata.ProjectRoot, dir, marker))
+    if err != nil {
+        metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("%s detection: ignoring unreadable marker: %v", projectType, err))
+        return "", false
+    }
+    f.Close()
+    return dir, true
+}
+
+// findMarker searches projectRoot breadth-first, level by level, for a file
+// named marker and returns the nearest directory containing it. Ties at the
+// same level resolve to the lexically first directory. Errors other than a
+// missing file are returned alongside the result.
+func findMarker(projectRoot, marker string, depth int) (string, bool, []error) {
+    var errs []error
+    level := []string{"."}
+    for i := 0; i < depth && len(level) > 0; i++ {
+        var next []string
+        for _, dir := range level {
+            info, err := os.Stat(filepath.Join(projectRoot, dir, marker))
+            if err == nil && !info.IsDir() {
+                return dir, true, errs
+            }
+            if err != nil && !os.IsNotExist(err) {
+                errs = append(errs, err)
+            }
+            if i+1 == depth {
+                continue
+            }
+            entries, err := os.ReadDir(filepath.Join(projectRoot, dir))
+            if err != nil {
+                errs = append(errs, err)
+                continue
+            }
+            for _, entry := range entries {
+                if entry.IsDir() && !markerSkipDirs[entry.Name()] {
+                    next = append(next, path.Join(dir, entry.Name()))
+                }
+            }
+        }
+        level = next
+    }
+    return "", false, errs
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+    "log/slog"
+    "net/http"
+    "time"
+
+    "github.com/prometheus/client_golang/prometheus"
+    "github.com/prometheus/client_golang/prometheus/collectors"
+    "github.com/prometheus/client_golang/prometheus/promhttp"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Prometheus metrics, served on METRICS_PORT at /metrics. They live in
+// their own registry so only what is listed here (plus the Go runtime and
+// process collectors) is exported.
+var (
+    metricsRegistry = prometheus.NewRegistry()
+
+    validationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Name: "cctools_validations_total",
+        Help: "Validators executed, by project type, validator and result (passed or failed).",
+    }, []string{"project_type", "validator", "result"})
+
+    validatorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+        Name:    "cctools_validator_duration_seconds",
+        Help:    "Wall-clock time of a validator including retries.",
+        Buckets: prometheus.ExponentialBuckets(0.1, 2, 12), // 100ms to ~3.4min
+    }, []string{"validator"})
+
+    locksHeld = prometheus.NewGauge(prometheus.GaugeOpts{
+        Name: "cctools_locks_held",
+        Help: "Locks currently held through this server.",
+    })
+
+    lockContentionTotal = prometheus.NewCounter(prometheus.CounterOpts{
+        Name: "cctools_lock_contention_total",
+        Help: "Lock acquisition attempts that found the lock already held.",
+    })
+
+    // The lock wait families share the project keys of lockContention, so
+    // their cardinality is bounded by LOCK_STATS_MAX_PROJECTS
+    lockWaitsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Name: "cctools_lock_waits_total",
+        Help: "Contended lock acquisitions, by project path (_other past the tracked limit).",
+    }, []string{"project"})
+
+    lockWaitSecondsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+        Name: "cctools_lock_wait_seconds_total",
+        Help: "Time spent waiting for held locks, by project path (_other past the tracked limit).",
+    }, []string{"project"})
+
+    heartbeatSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+        Name: "cctools_server_heartbeat_timestamp_seconds",
+        Help: "Unix time of the last heartbeat; alert when it stops advancing.",
+    })
+)
+
+func init() {
+    metricsRegistry.MustRegister(
+        validationsTotal,
+        validatorDuration,
+        locksHeld,
+        lockContentionTotal,
+        lockWaitsTotal,
+        lockWaitSecondsTotal,
+        heartbeatSeconds,
+        collectors.NewGoCollector(),
+        collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+    )
+}
+
+// recordValidations counts the validators of a finished run; skipped ones
+// did not execute and are left out
+func recordValidations(projectType string, results []*pb.ValidationResult) {
+    for _, result := range results {
+        if result.Skipped {
+            continue
+        }
+        outcome := "passed"
+        if !result.Success {
+            outcome = "failed"
+        }
+        validationsTotal.WithLabelValues(projectType, result.Validator, outcome).Inc()
+    }
+}
+
+// observeValidatorDuration records how long a validator took
+func observeValidatorDuration(validator string, d time.Duration) {
+    validatorDuration.WithLabelValues(validator).Observe(d.Seconds())
+}
+
+// serveMetrics exposes /metrics on port in the background
+func serveMetrics(port string) {
+    mux := http.NewServeMux()
+    mux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+    go func() {
+        slog.Info("Prometheus metrics listening", "port", port)
+        if err := http.ListenAndServe(":"+port, mux); err != nil {
+            slog.Error("metrics server stopped", "error", err)
+        }
+    }()
+}
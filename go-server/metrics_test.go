@@ -0,0 +1,67 @@
+package main
+
+import (
+    "context"
+    "io"
+    "net/http"
+    "strings"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestMetricsEndpointExportsFamilies(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+
+    // Labeled families only appear once they have a sample
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+    if _, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root}); err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    lock := &pb.LockRequest{ProjectPath: t.TempDir(), Owner: "holder"}
+    if _, err := ts.client.AcquireLock(ctx, lock); err != nil {
+        t.Fatalf("AcquireLock: %v", err)
+    }
+    if _, err := ts.client.AcquireLock(ctx, &pb.LockRequest{ProjectPath: lock.ProjectPath, Owner: "contender"}); err != nil {
+        t.Fatalf("contended AcquireLock: %v", err)
+    }
+    defer ts.client.ReleaseLock(ctx, lock)
+    ts.parts.tools.heartbeat()
+
+    port := freePort(t)
+    serveMetrics(port)
+    resp := waitForHTTP(t, http.DefaultClient, "http://127.0.0.1:"+port+"/metrics")
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        t.Fatalf("GET /metrics: %s", resp.Status)
+    }
+    body, err := io.ReadAll(resp.Body)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    for _, family := range []string{
+        "# TYPE cctools_validations_total counter",
+        "# TYPE cctools_validator_duration_seconds histogram",
+        "# TYPE cctools_locks_held gauge",
+        "# TYPE cctools_lock_contention_total counter",
+        "# TYPE cctools_lock_waits_total counter",
+        "# TYPE cctools_lock_wait_seconds_total counter",
+        "# TYPE cctools_server_heartbeat_timestamp_seconds gauge",
+        "# TYPE go_goroutines gauge",
+    } {
+        if !strings.Contains(string(body), family) {
+            t.Errorf("/metrics has no %q", family)
+        }
+    }
+    if want := `cctools_validations_total{project_type="make",result="passed",validator="lint"}`; !strings.Contains(string(body), want) {
+        t.Errorf("/metrics has no %s sample", want)
+    }
+    if want := `cctools_lock_waits_total{project="` + lock.ProjectPath + `"}`; !strings.Contains(string(body), want) {
+        t.Errorf("/metrics has no %s sample", want)
+    }
+    if strings.Contains(string(body), "cctools_server_heartbeat_timestamp_seconds 0\n") {
+        t.Error("heartbeat gauge was not set")
+    }
+}
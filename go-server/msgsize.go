@@ -0,0 +1,55 @@
+package main
+
+import (
+    "fmt"
+    "log/slog"
+    "os"
+    "strconv"
+)
+
+// defaultMessageMB is the gRPC message size limit in each direction,
+// matching grpc-go's own receive default
+const defaultMessageMB = 4
+
+// messageLimits caps the size of gRPC messages the server accepts and
+// sends (MAX_RECV_MSG_MB, MAX_SEND_MSG_MB). Large validation output or big
+// batch requests need more than the default.
+type messageLimits struct {
+    recvMB int
+    sendMB int
+}
+
+// loadMessageLimits reads the message size limits, rejecting values that
+// are not positive whole megabytes
+func loadMessageLimits() (messageLimits, error) {
+    recv, err := envMegabytes("MAX_RECV_MSG_MB")
+    if err != nil {
+        return messageLimits{}, err
+    }
+    send, err := envMegabytes("MAX_SEND_MSG_MB")
+    if err != nil {
+        return messageLimits{}, err
+    }
+    slog.Info("gRPC message size limits", "recv_mb", recv, "send_mb", send)
+    return messageLimits{recvMB: recv, sendMB: send}, nil
+}
+
+func envMegabytes(key string) (int, error) {
+    v := os.Getenv(key)
+    if v == "" {
+        return defaultMessageMB, nil
+    }
+    n, err := strconv.Atoi(v)
+    if err != nil || n <= 0 {
+        return 0, fmt.Errorf("%s must be a positive number of megabytes, got %q", key, v)
+    }
+    // Sizes are passed to grpc as int; stay far from overflow on 32-bit
+    if n > 2047 {
+        return 0, fmt.Errorf("%s must be at most 2047, got %d", key, n)
+    }
+    return n, nil
+}
+
+func (m messageLimits) recvBytes() int { return m.recvMB << 20 }
+
+func (m messageLimits) sendBytes() int { return m.sendMB << 20 }
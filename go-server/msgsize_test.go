@@ -0,0 +1,61 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestLoadMessageLimits(t *testing.T) {
+    tests := []struct {
+        recv, send           string
+        recvBytes, sendBytes int
+        wantErr              bool
+    }{
+        {recv: "", send: "", recvBytes: 4 << 20, sendBytes: 4 << 20},
+        {recv: "16", send: "1", recvBytes: 16 << 20, sendBytes: 1 << 20},
+        {recv: "2047", send: "64", recvBytes: 2047 << 20, sendBytes: 64 << 20},
+        {recv: "0", wantErr: true},
+        {recv: "-4", wantErr: true},
+        {send: "lots", wantErr: true},
+        {send: "2048", wantErr: true},
+    }
+    for _, tt := range tests {
+        t.Setenv("MAX_RECV_MSG_MB", tt.recv)
+        t.Setenv("MAX_SEND_MSG_MB", tt.send)
+        limits, err := loadMessageLimits()
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("recv=%q send=%q: got %+v, want an error", tt.recv, tt.send, limits)
+            }
+            continue
+        }
+        if err != nil {
+            t.Errorf("recv=%q send=%q: %v", tt.recv, tt.send, err)
+            continue
+        }
+        if limits.recvBytes() != tt.recvBytes || limits.sendBytes() != tt.sendBytes {
+            t.Errorf("recv=%q send=%q: %d and %d bytes, want %d and %d", tt.recv, tt.send, limits.recvBytes(), limits.sendBytes(), tt.recvBytes, tt.sendBytes)
+        }
+    }
+}
+
+func TestMessageLimitAppliesToServer(t *testing.T) {
+    t.Setenv("MAX_RECV_MSG_MB", "1")
+    ts := newTestServer(t, serverConfig{})
+    req := &pb.ValidationRequest{ProjectRoot: t.TempDir(), HookType: strings.Repeat("x", 3<<19)}
+
+    if _, err := ts.client.GetProjectMetadata(context.Background(), req); status.Code(err) != codes.ResourceExhausted {
+        t.Errorf("1.5 MiB request = %v, want ResourceExhausted", err)
+    }
+
+    t.Setenv("MAX_RECV_MSG_MB", "0")
+    if _, err := newGRPCServer(serverConfig{}); err == nil {
+        t.Error("newGRPCServer accepted MAX_RECV_MSG_MB=0")
+    }
+}
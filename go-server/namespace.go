@@ -0,0 +1,50 @@
+package main
+
+import (
+    "context"
+    "strings"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// lockNamespace resolves the namespace a lock lives in: the request's
+// namespace, else the caller's authenticated identity, else LOCK_NAMESPACE.
+// An empty namespace keeps the original, un-namespaced lock ids.
+func (s *CCToolsServer) lockNamespace(ctx context.Context, req *pb.LockRequest) string {
+    if req.Namespace != "" {
+        return req.Namespace
+    }
+    if identity := identityFromContext(ctx); identity != "" {
+        return identity
+    }
+    return s.defaultNamespace
+}
+
+// lockName identifies a lock within the server: the project path,
+// qualified by the namespace when there is one
+func lockName(namespace, projectPath string) string {
+    if namespace == "" {
+        return projectPath
+    }
+    return namespace + ":" + projectPath
+}
+
+// lockID is the identifier reported to clients for a lock
+func lockID(namespace, projectPath string) string {
+    return "devflow_" + lockName(namespace, projectPath)
+}
+
+// lockFileBase is the lock file name for a namespace: .devflow.lock for the
+// default namespace (shared with external tools), .devflow.<ns>.lock otherwise
+func lockFileBase(namespace string) string {
+    if namespace == "" {
+        return lockFileName
+    }
+    safe := strings.Map(func(r rune) rune {
+        if r == '/' || r == '\\' || r == 0 {
+            return '_'
+        }
+        return r
+    }, namespace)
+    return ".devflow." + safe + ".lock"
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+    "path"
+    "path/filepath"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// nodePackageManagers pair each Node package manager with its lockfile,
+// most specific first. Repos that switched managers often keep a stale
+// package-lock.json, while a pnpm or yarn lockfile is only there on purpose.
+var nodePackageManagers = []struct{ name, lockfile string }{
+    {"pnpm", "pnpm-lock.yaml"},
+    {"yarn", "yarn.lock"},
+    {"npm", "package-lock.json"},
+}
+
+// detectNode picks the package manager of a package.json project. Scripts run
+// through the package manager whose lockfile is present, npm when there is
+// none. The lockfile is looked for next to package.json and then at the
+// project root, where workspaces keep a single one for every package.
+func (s *CCToolsServer) detectNode(metadata *pb.ProjectMetadata, dir, marker string) {
+    metadata.PackageManager = "npm"
+
+    dirs := []string{dir}
+    if dir != "." {
+        dirs = append(dirs, ".")
+    }
+search:
+    for _, d := range dirs {
+        for _, pm := range nodePackageManagers {
+            if s.fileExists(filepath.Join(metadata.ProjectRoot, d, pm.lockfile)) {
+                metadata.PackageManager = pm.name
+                metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(d, pm.lockfile))
+                break search
+            }
+        }
+    }
+
+    metadata.Commands["lint"] = metadata.PackageManager + " run lint"
+    metadata.Commands["test"] = metadata.PackageManager + " test"
+}
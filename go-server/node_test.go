@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestDetectNodePackageManager(t *testing.T) {
+    const manifest = `{"scripts": {"lint": "eslint .", "test": "jest"}}`
+    tests := []struct {
+        name      string
+        lockfiles []string
+        want      string
+        lockfile  string
+    }{
+        {"no lockfile", nil, "npm", ""},
+        {"npm", []string{"package-lock.json"}, "npm", "package-lock.json"},
+        {"yarn", []string{"yarn.lock"}, "yarn", "yarn.lock"},
+        {"pnpm", []string{"pnpm-lock.yaml"}, "pnpm", "pnpm-lock.yaml"},
+        {"yarn over npm", []string{"package-lock.json", "yarn.lock"}, "yarn", "yarn.lock"},
+        {"pnpm over npm", []string{"package-lock.json", "pnpm-lock.yaml"}, "pnpm", "pnpm-lock.yaml"},
+        {"pnpm over yarn and npm", []string{"package-lock.json", "yarn.lock", "pnpm-lock.yaml"}, "pnpm", "pnpm-lock.yaml"},
+    }
+    s := NewCCToolsServer()
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            files := map[string]string{"package.json": manifest}
+            for _, lockfile := range tt.lockfiles {
+                files[lockfile] = ""
+            }
+            metadata, err := s.detectProjectMetadata(writeProject(t, files))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if metadata.ProjectType != "npm" || metadata.PackageManager != tt.want {
+                t.Fatalf("type=%q package manager=%q, want npm and %s", metadata.ProjectType, metadata.PackageManager, tt.want)
+            }
+            if lint, test := metadata.Commands["lint"], metadata.Commands["test"]; lint != tt.want+" run lint" || test != tt.want+" test" {
+                t.Errorf("lint=%q test=%q, want them run through %s", lint, test, tt.want)
+            }
+
+            found := false
+            for _, name := range metadata.ConfigFiles {
+                for _, lockfile := range tt.lockfiles {
+                    if name == lockfile && name != tt.lockfile {
+                        t.Errorf("config files %v include the unused %s", metadata.ConfigFiles, name)
+                    }
+                }
+                found = found || name == tt.lockfile
+            }
+            if tt.lockfile != "" && !found {
+                t.Errorf("config files %v do not include %s", metadata.ConfigFiles, tt.lockfile)
+            }
+        })
+    }
+}
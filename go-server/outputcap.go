@@ -0,0 +1,81 @@
+package main
+
+import (
+    "fmt"
+    "unicode/utf8"
+)
+
+// defaultMaxOutputBytes caps each captured output of a validator
+const defaultMaxOutputBytes = 1 << 20
+
+// loadMaxOutputBytes reads MAX_OUTPUT_BYTES; 0 disables the cap
+func loadMaxOutputBytes() int {
+    return envInt("MAX_OUTPUT_BYTES", defaultMaxOutputBytes)
+}
+
+// tailBuffer captures a validator's output, keeping only the last max bytes
+// so a chatty failure can neither exhaust the server's memory nor push the
+// response past the gRPC message limit. The tail is kept because that is
+// where errors usually are. The cap applies as the output is written: the
+// buffer never holds more than twice max, and the bytes dropped from the
+// head are counted for the truncation marker. max <= 0 keeps everything.
+//
+// tailBuffer is not safe for concurrent use; its writers hold their own
+// locks.
+type tailBuffer struct {
+    max     int
+    buf     []byte
+    written int64
+}
+
+func (b *tailBuffer) Write(p []byte) (int, error) {
+    b.written += int64(len(p))
+    switch {
+    case b.max <= 0:
+        b.buf = append(b.buf, p...)
+    case len(p) >= b.max:
+        b.buf = append(b.buf[:0], p[len(p)-b.max:]...)
+    default:
+        // Slide the tail to the front once the slack is used up, so the
+        // copying is amortized over max bytes of output
+        if len(b.buf)+len(p) > 2*b.max {
+            n := copy(b.buf, b.buf[len(b.buf)-(b.max-len(p)):])
+            b.buf = b.buf[:n]
+        }
+        b.buf = append(b.buf, p...)
+    }
+    return len(p), nil
+}
+
+// tail returns the kept output, starting on a rune boundary, and how many
+// bytes were dropped before it
+func (b *tailBuffer) tail() ([]byte, int64) {
+    kept := b.buf
+    if b.max > 0 && len(kept) > b.max {
+        kept = kept[len(kept)-b.max:]
+    }
+    dropped := b.written - int64(len(kept))
+    if dropped > 0 {
+        for len(kept) > 0 && !utf8.RuneStart(kept[0]) {
+            kept = kept[1:]
+            dropped++
+        }
+    }
+    return kept, dropped
+}
+
+// truncated reports whether any output was dropped
+func (b *tailBuffer) truncated() bool {
+    _, dropped := b.tail()
+    return dropped > 0
+}
+
+// String returns the kept output, preceded by a marker counting the
+// dropped bytes when it was truncated
+func (b *tailBuffer) String() string {
+    kept, dropped := b.tail()
+    if dropped > 0 {
+        return fmt.Sprintf("...[truncated %d bytes]\n", dropped) + string(kept)
+    }
+    return string(kept)
+}
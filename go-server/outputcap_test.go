@@ -0,0 +1,89 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestTailBuffer(t *testing.T) {
+    tests := []struct {
+        name   string
+        max    int
+        writes []string
+        want   string
+    }{
+        {"under the cap", 10, []string{"abc", "def"}, "abcdef"},
+        {"exactly the cap", 6, []string{"abc", "def"}, "abcdef"},
+        {"small writes over the cap", 4, []string{"ab", "cd", "ef", "gh", "ij"}, "...[truncated 6 bytes]\nghij"},
+        {"one write over the cap", 4, []string{"abcdefghij"}, "...[truncated 6 bytes]\nghij"},
+        {"large write after small ones", 4, []string{"ab", "cdefghij", "k"}, "...[truncated 7 bytes]\nhijk"},
+        {"cut inside a rune", 4, []string{"aé€"}, "...[truncated 3 bytes]\n€"},
+        {"no cap", 0, []string{"abc", "def"}, "abcdef"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            b := &tailBuffer{max: tt.max}
+            for _, w := range tt.writes {
+                if n, err := b.Write([]byte(w)); n != len(w) || err != nil {
+                    t.Fatalf("Write(%q) = %d, %v", w, n, err)
+                }
+            }
+            if got := b.String(); got != tt.want {
+                t.Errorf("String() = %q, want %q", got, tt.want)
+            }
+            if got, want := b.truncated(), strings.HasPrefix(tt.want, "...[truncated"); got != want {
+                t.Errorf("truncated() = %v, want %v", got, want)
+            }
+        })
+    }
+}
+
+func TestTailBufferBoundsMemory(t *testing.T) {
+    b := &tailBuffer{max: 1024}
+    line := []byte(strings.Repeat("x", 99) + "\n")
+    for i := 0; i < 100000; i++ {
+        b.Write(line)
+        if cap(b.buf) > 4*b.max {
+            t.Fatalf("buffer grew to %d bytes after %d writes, cap is %d", cap(b.buf), i+1, b.max)
+        }
+    }
+    kept, dropped := b.tail()
+    if len(kept) != b.max || dropped != int64(100000*len(line)-b.max) {
+        t.Errorf("kept %d bytes, dropped %d", len(kept), dropped)
+    }
+}
+
+func TestRunCommandCapsOutputWhileCapturing(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("MAX_OUTPUT_BYTES", "1024")
+    s := NewCCToolsServer()
+    // 2 MiB on stdout and 1 MiB on stderr
+    spec := shellSpec(t, "test", `head -c 2097152 /dev/zero | tr '\0' o; head -c 1048576 /dev/zero | tr '\0' e >&2; echo; echo tail`)
+    spec.timeout = 30 * time.Second
+
+    result := s.executeValidator(t.Context(), spec)
+    if !result.Success || !result.Truncated {
+        t.Fatalf("success=%v truncated=%v error=%q, want a truncated success", result.Success, result.Truncated, result.Error)
+    }
+    total := 2097152 + 1048576 + len("\ntail\n")
+    for name, got := range map[string]struct {
+        output  string
+        written int
+    }{
+        "output": {result.Output, total},
+        "stdout": {result.Stdout, total - 1048576},
+        "stderr": {result.Stderr, 1048576},
+    } {
+        marker := fmt.Sprintf("...[truncated %d bytes]\n", got.written-1024)
+        if !strings.HasPrefix(got.output, marker) || len(got.output) != len(marker)+1024 {
+            t.Errorf("%s starts %q (%d bytes), want %q and 1024 bytes of tail", name, got.output[:min(40, len(got.output))], len(got.output), marker)
+        }
+    }
+    // Only stdout keeps a fixed order; the combined output interleaves the
+    // two pipes as they are read
+    if !strings.HasSuffix(result.Stdout, "\ntail\n") {
+        t.Errorf("stdout does not end with the last line: %q", result.Stdout[len(result.Stdout)-20:])
+    }
+}
@@ -0,0 +1,47 @@
+package main
+
+import "log/slog"
+
+// Degraded readiness under load
+//
+// Load shedding (MAX_INFLIGHT_VALIDATIONS) turns callers away once the
+// server is full; DEGRADED_INFLIGHT_THRESHOLD (0 disables) warns them off
+// before that. While more validations than the threshold are running, the
+// readiness health entry reports NOT_SERVING so health-aware load balancers
+// send new work elsewhere, and it returns to SERVING once load drops back
+// to the threshold. Liveness is left alone: a busy server is not a broken
+// one and must not be restarted.
+
+// trackInflight counts a validation as running until the returned func is called
+func (s *CCToolsServer) trackInflight() func() {
+    s.inflight.Add(1)
+    s.noteLoad()
+    return func() {
+        s.inflight.Add(-1)
+        s.noteLoad()
+    }
+}
+
+// noteLoad flips readiness when the inflight count has crossed the
+// threshold. The count is read under the readiness lock, so whichever
+// caller locks last publishes the current state.
+func (s *CCToolsServer) noteLoad() {
+    if s.degradeAbove <= 0 {
+        return
+    }
+    s.readiness.mu.Lock()
+    defer s.readiness.mu.Unlock()
+
+    inflight := s.inflight.Load()
+    overloaded := inflight > int64(s.degradeAbove)
+    if overloaded == s.readiness.overloaded {
+        return
+    }
+    s.readiness.overloaded = overloaded
+    s.readiness.publish(s.health)
+    if overloaded {
+        slog.Warn("readiness: overloaded", "inflight", inflight, "threshold", s.degradeAbove)
+    } else {
+        slog.Info("readiness: load back under threshold", "inflight", inflight, "threshold", s.degradeAbove)
+    }
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestReadinessDegradesPastInflightThreshold(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("DEGRADED_INFLIGHT_THRESHOLD", "1")
+    ts := newTestServer(t, serverConfig{})
+    health := healthpb.NewHealthClient(ts.conn)
+    waitForHealth(t, health, readinessService, healthpb.HealthCheckResponse_SERVING)
+
+    // Each validation signals it is running, then holds until release exists
+    dir := t.TempDir()
+    release := filepath.Join(dir, "release")
+    done := make(chan error, 2)
+    start := func(name string) string {
+        running := filepath.Join(dir, name)
+        hold := "sh -c 'echo x > " + running + "; while [ ! -f " + release + " ]; do sleep 0.05; done'"
+        root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+        go func() {
+            _, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+                ProjectRoot:      root,
+                OverrideCommands: map[string]string{"lint": "", "test": hold},
+            })
+            done <- err
+        }()
+        return running
+    }
+
+    // At the threshold the server is still ready
+    waitForFile(t, start("first"))
+    waitForHealth(t, health, readinessService, healthpb.HealthCheckResponse_SERVING)
+
+    waitForFile(t, start("second"))
+    waitForHealth(t, health, readinessService, healthpb.HealthCheckResponse_NOT_SERVING)
+    if _, err := ts.parts.tools.readiness.result(); err != errOverloaded {
+        t.Errorf("readiness result = %v, want %v", err, errOverloaded)
+    }
+    // A busy server is still alive
+    waitForHealth(t, health, "", healthpb.HealthCheckResponse_SERVING)
+
+    if err := os.WriteFile(release, nil, 0o644); err != nil {
+        t.Fatal(err)
+    }
+    for range 2 {
+        if err := <-done; err != nil {
+            t.Errorf("ValidateProject: %v", err)
+        }
+    }
+    waitForHealth(t, health, readinessService, healthpb.HealthCheckResponse_SERVING)
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+    "context"
+    "os"
+    "strings"
+
+    "google.golang.org/grpc/peer"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Lock owner fallback chain
+//
+// When AcquireLock is called without an owner, the server derives one so
+// CheckLock can say who holds a lock. Sources are tried in the order given
+// by LOCK_OWNER_SOURCES (default "peer,identity,hostname"):
+//
+//   - peer: the caller's network address
+//   - identity: the CN of the caller's verified TLS client certificate
+//   - hostname: the hostname field of the LockRequest
+//
+// The first non-empty value wins; if all are empty the owner stays empty.
+
+var defaultLockOwnerSources = []string{"peer", "identity", "hostname"}
+
+// loadLockOwnerSources reads LOCK_OWNER_SOURCES, ignoring unknown names
+func loadLockOwnerSources() []string {
+    v := os.Getenv("LOCK_OWNER_SOURCES")
+    if v == "" {
+        return defaultLockOwnerSources
+    }
+    sources := make([]string, 0)
+    for _, source := range strings.Split(v, ",") {
+        switch source = strings.TrimSpace(source); source {
+        case "peer", "identity", "hostname":
+            sources = append(sources, source)
+        }
+    }
+    return sources
+}
+
+// lockOwner returns the request's owner, or derives one from the fallback chain
+func (s *CCToolsServer) lockOwner(ctx context.Context, req *pb.LockRequest) string {
+    if req.Owner != "" {
+        return req.Owner
+    }
+    for _, source := range s.lockOwnerSources {
+        var owner string
+        switch source {
+        case "peer":
+            if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+                owner = p.Addr.String()
+            }
+        case "identity":
+            owner = identityFromContext(ctx)
+        case "hostname":
+            owner = req.Hostname
+        }
+        if owner != "" {
+            return owner
+        }
+    }
+    return ""
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path"
+    "path/filepath"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// composerManifest is the part of composer.json that detection reads
+type composerManifest struct {
+    Name       string                     `json:"name"`
+    Bin        []string                   `json:"bin"`
+    Scripts    map[string]json.RawMessage `json:"scripts"`
+    Require    map[string]string          `json:"require"`
+    RequireDev map[string]string          `json:"require-dev"`
+}
+
+func readComposerManifest(dir string) (*composerManifest, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "composer.json"))
+    if err != nil {
+        return nil, err
+    }
+    var manifest composerManifest
+    if err := json.Unmarshal(data, &manifest); err != nil {
+        return nil, fmt.Errorf("composer.json: %w", err)
+    }
+    return &manifest, nil
+}
+
+// phpLintAll is the lint fallback for Composer projects without a "lint"
+// script. `php -l` checks a single file (several only from PHP 8.3), so
+// PHP itself walks the project, skipping dependencies, and runs `php -l`
+// on every .php file, failing if any of them does.
+const phpLintAll = `php -r '$failed = 0; ` +
+    `$tree = new RecursiveCallbackFilterIterator(new RecursiveDirectoryIterator(".", FilesystemIterator::SKIP_DOTS), ` +
+    `fn ($f) => !$f->isDir() || !in_array($f->getFilename(), ["vendor", "node_modules", ".git"])); ` +
+    `foreach (new RecursiveIteratorIterator($tree) as $f) { if ($f->getExtension() === "php") { ` +
+    `passthru(escapeshellarg(PHP_BINARY) . " -l " . escapeshellarg($f->getPathname()), $rc); $failed |= $rc; } } ` +
+    `exit($failed ? 1 : 0);'`
+
+// detectComposer keeps only the commands a Composer project provides. Lint
+// runs the "lint" script, or else phpLintAll. Tests run the "test" script,
+// or else PHPUnit from vendor/bin when the project requires it.
+func (s *CCToolsServer) detectComposer(metadata *pb.ProjectMetadata, dir, marker string) {
+    root := filepath.Join(metadata.ProjectRoot, dir)
+    if s.fileExists(filepath.Join(root, "composer.lock")) {
+        metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(dir, "composer.lock"))
+    }
+
+    manifest, err := readComposerManifest(root)
+    if err != nil {
+        metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("composer detection: %v", err))
+        return
+    }
+    if _, ok := manifest.Scripts["lint"]; !ok {
+        metadata.Commands["lint"] = phpLintAll
+    }
+    if _, ok := manifest.Scripts["test"]; !ok {
+        delete(metadata.Commands, "test")
+        if manifest.requires("phpunit/phpunit") || s.fileExists(filepath.Join(root, "vendor", "bin", "phpunit")) {
+            metadata.Commands["test"] = "./vendor/bin/phpunit"
+        }
+    }
+}
+
+// requires reports whether the project depends on pkg, at runtime or for development
+func (m *composerManifest) requires(pkg string) bool {
+    _, ok := m.Require[pkg]
+    if !ok {
+        _, ok = m.RequireDev[pkg]
+    }
+    return ok
+}
+
+// composerProjectName reads "name" (vendor/package) and the "bin" scripts
+func composerProjectName(dir string) (string, []string, error) {
+    manifest, err := readComposerManifest(dir)
+    if err != nil {
+        return "", nil, err
+    }
+    var binaries []string
+    for _, bin := range manifest.Bin {
+        binaries = append(binaries, path.Base(bin))
+    }
+    return manifest.Name, binaries, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestDetectComposer(t *testing.T) {
+    tests := []struct {
+        name     string
+        files    map[string]string
+        wantLint string
+        wantTest string
+    }{
+        {
+            name:     "lint and test scripts",
+            files:    map[string]string{"composer.json": `{"name": "acme/app", "scripts": {"lint": "phpcs", "test": "phpunit"}}`},
+            wantLint: "composer run lint",
+            wantTest: "composer test",
+        },
+        {
+            name:     "no test script, phpunit required",
+            files:    map[string]string{"composer.json": `{"scripts": {"lint": "phpcs"}, "require-dev": {"phpunit/phpunit": "^10"}}`},
+            wantLint: "composer run lint",
+            wantTest: "./vendor/bin/phpunit",
+        },
+        {
+            name:     "no scripts",
+            files:    map[string]string{"composer.json": `{"name": "acme/lib"}`},
+            wantLint: phpLintAll,
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            root := writeProject(t, tt.files)
+            metadata, err := NewCCToolsServer().detectProjectMetadata(root)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if metadata.ProjectType != "composer" || metadata.Language != "php" {
+                t.Errorf("type=%q language=%q, want composer and php", metadata.ProjectType, metadata.Language)
+            }
+            if got := metadata.Commands["lint"]; got != tt.wantLint {
+                t.Errorf("lint = %q, want %q", got, tt.wantLint)
+            }
+            if got, ok := metadata.Commands["test"]; got != tt.wantTest || ok != (tt.wantTest != "") {
+                t.Errorf("test = %q (present %v), want %q", got, ok, tt.wantTest)
+            }
+        })
+    }
+}
+
+func TestPHPLintAllIsOnePHPInvocation(t *testing.T) {
+    argv, err := shellSplit(phpLintAll)
+    if err != nil {
+        t.Fatalf("shellSplit: %v", err)
+    }
+    if len(argv) != 3 || argv[0] != "php" || argv[1] != "-r" {
+        t.Fatalf("argv = %q, want php -r <script>", argv)
+    }
+    t.Setenv("ALLOWED_COMMANDS", defaultAllowedCommands)
+    if err := loadCommandAllowlist().check(&validatorSpec{command: phpLintAll}); err != nil {
+        t.Errorf("the default allowlist refuses the fallback: %v", err)
+    }
+}
@@ -0,0 +1,18 @@
+//go:build !unix
+// +build !unix
+
+package main
+
+import "os/exec"
+
+// setProcessGroup is a no-op where process groups are unavailable; only
+// the direct child is killed on cancellation
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// startProcessGroup is a no-op where process groups are unavailable
+func startProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills a started command; children may survive
+func killProcessGroup(cmd *exec.Cmd) {
+    cmd.Process.Kill()
+}
@@ -0,0 +1,29 @@
+//go:build unix
+// +build unix
+
+package main
+
+import (
+    "os/exec"
+    "syscall"
+)
+
+// setProcessGroup runs the command in its own process group so cancelling
+// its context kills every process it spawned, not just the direct child
+func setProcessGroup(cmd *exec.Cmd) {
+    startProcessGroup(cmd)
+    cmd.Cancel = func() error {
+        return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+    }
+}
+
+// startProcessGroup makes the command lead a new process group without
+// tying it to a context; use killProcessGroup to stop it
+func startProcessGroup(cmd *exec.Cmd) {
+    cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills a started command and everything it spawned
+func killProcessGroup(cmd *exec.Cmd) {
+    syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
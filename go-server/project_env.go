@@ -0,0 +1,71 @@
+package main
+
+import (
+    "os"
+    "sort"
+    "strings"
+)
+
+// Validator environment precedence, highest first:
+//
+//   1. env on the ValidationRequest
+//   2. forceColorEnv, when force_color is set or the project type is listed
+//      in FORCE_COLOR_PROJECT_TYPES
+//   3. defaults for the detected project type (PROJECT_ENV_<TYPE>)
+//   4. the server's own environment (os.Environ)
+
+// forceColorEnv makes common tools emit ANSI color without a TTY:
+// FORCE_COLOR for Node-based tools, CLICOLOR_FORCE for BSD-style CLIs and
+// CARGO_TERM_COLOR for cargo, whose project default turns color off
+var forceColorEnv = map[string]string{
+    "FORCE_COLOR":      "1",
+    "CLICOLOR_FORCE":   "1",
+    "CARGO_TERM_COLOR": "always",
+}
+
+// defaultProjectEnv holds the built-in per-type defaults. Setting
+// PROJECT_ENV_<TYPE> replaces the defaults for that type entirely.
+var defaultProjectEnv = map[string]map[string]string{
+    "npm":   {"CI": "true"},
+    "cargo": {"CARGO_TERM_COLOR": "never"},
+}
+
+// loadProjectEnv resolves the per-type defaults, reading overrides such as
+// PROJECT_ENV_NPM="CI=true,NODE_OPTIONS=--max-old-space-size=4096"
+func loadProjectEnv() map[string]map[string]string {
+    result := make(map[string]map[string]string, len(defaultProjectEnv))
+    for projectType, env := range defaultProjectEnv {
+        result[projectType] = env
+    }
+
+    for _, kv := range os.Environ() {
+        key, _, _ := strings.Cut(kv, "=")
+        projectType, ok := strings.CutPrefix(key, "PROJECT_ENV_")
+        if !ok || projectType == "" {
+            continue
+        }
+        result[strings.ToLower(projectType)] = envMap(key)
+    }
+    return result
+}
+
+// environ returns the full environment for a validator process, or nil to
+// inherit the server's environment unchanged
+func (v *validatorSpec) environ() []string {
+    if len(v.env) == 0 {
+        return nil
+    }
+
+    keys := make([]string, 0, len(v.env))
+    for k := range v.env {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+
+    // exec keeps the last value of a duplicated key, so these override os.Environ
+    env := os.Environ()
+    for _, k := range keys {
+        env = append(env, k+"="+v.env[k])
+    }
+    return env
+}
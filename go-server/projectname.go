@@ -0,0 +1,106 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path"
+    "path/filepath"
+    "regexp"
+    "sort"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// projectNameReaders read a project type's declared name and binaries from
+// the directory holding its marker file
+var projectNameReaders = map[string]func(dir string) (name string, binaries []string, err error){
+    "npm":      npmProjectName,
+    "cargo":    cargoProjectName,
+    "mix":      mixProjectName,
+    "zig":      zigProjectName,
+    "gomod":    goModProjectName,
+    "python":   pythonProjectName,
+    "maven":    mavenProjectName,
+    "gradle":   gradleProjectName,
+    "composer": composerProjectName,
+    "deno":     denoProjectName,
+}
+
+// annotateProjectName fills in ProjectName and Binaries. A manifest that
+// cannot be parsed leaves them empty and adds a warning.
+func annotateProjectName(metadata *pb.ProjectMetadata) {
+    read, ok := projectNameReaders[metadata.ProjectType]
+    if !ok {
+        return
+    }
+    name, binaries, err := read(filepath.Join(metadata.ProjectRoot, metadata.MarkerDir))
+    if err != nil {
+        metadata.Warnings = append(metadata.Warnings, fmt.Sprintf("%s project name: %v", metadata.ProjectType, err))
+        return
+    }
+    metadata.ProjectName = name
+    metadata.Binaries = binaries
+}
+
+// npmProjectName reads "name" and "bin", which is either a single command
+// named after the package or a map of command names
+func npmProjectName(dir string) (string, []string, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+    if err != nil {
+        return "", nil, err
+    }
+    var manifest struct {
+        Name string          `json:"name"`
+        Bin  json.RawMessage `json:"bin"`
+    }
+    if err := json.Unmarshal(data, &manifest); err != nil {
+        return "", nil, fmt.Errorf("package.json: %w", err)
+    }
+
+    var binaries []string
+    var single string
+    var named map[string]string
+    switch {
+    case json.Unmarshal(manifest.Bin, &single) == nil && single != "":
+        // A scoped package's command drops the scope
+        binaries = []string{path.Base(manifest.Name)}
+    case json.Unmarshal(manifest.Bin, &named) == nil:
+        for command := range named {
+            binaries = append(binaries, command)
+        }
+        sort.Strings(binaries)
+    }
+    return manifest.Name, binaries, nil
+}
+
+// cargoProjectName reads [package] name and the [[bin]] targets; without
+// explicit targets src/main.rs builds a binary named after the package
+func cargoProjectName(dir string) (string, []string, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+    if err != nil {
+        return "", nil, err
+    }
+    name, _ := tomlString(data, "package", "name")
+    binaries := tomlStrings(data, "bin", "name")
+    if len(binaries) == 0 && name != "" {
+        if _, err := os.Stat(filepath.Join(dir, "src", "main.rs")); err == nil {
+            binaries = []string{name}
+        }
+    }
+    return name, binaries, nil
+}
+
+var mixAppPattern = regexp.MustCompile(`\bapp:\s*:([a-z_][a-zA-Z0-9_]*)`)
+
+// mixProjectName reads the OTP application name from mix.exs
+func mixProjectName(dir string) (string, []string, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "mix.exs"))
+    if err != nil {
+        return "", nil, err
+    }
+    if m := mixAppPattern.FindSubmatch(data); m != nil {
+        return string(m[1]), nil, nil
+    }
+    return "", nil, nil
+}
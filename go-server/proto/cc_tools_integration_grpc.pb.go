@@ -19,11 +19,24 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CCToolsIntegration_ValidateProject_FullMethodName    = "/cc_tools_integration.CCToolsIntegration/ValidateProject"
-	CCToolsIntegration_GetProjectMetadata_FullMethodName = "/cc_tools_integration.CCToolsIntegration/GetProjectMetadata"
-	CCToolsIntegration_AcquireLock_FullMethodName        = "/cc_tools_integration.CCToolsIntegration/AcquireLock"
-	CCToolsIntegration_ReleaseLock_FullMethodName        = "/cc_tools_integration.CCToolsIntegration/ReleaseLock"
-	CCToolsIntegration_CheckLock_FullMethodName          = "/cc_tools_integration.CCToolsIntegration/CheckLock"
+	CCToolsIntegration_ValidateProject_FullMethodName            = "/cc_tools_integration.CCToolsIntegration/ValidateProject"
+	CCToolsIntegration_GetProjectMetadata_FullMethodName         = "/cc_tools_integration.CCToolsIntegration/GetProjectMetadata"
+	CCToolsIntegration_AcquireLock_FullMethodName                = "/cc_tools_integration.CCToolsIntegration/AcquireLock"
+	CCToolsIntegration_ReleaseLock_FullMethodName                = "/cc_tools_integration.CCToolsIntegration/ReleaseLock"
+	CCToolsIntegration_CheckLock_FullMethodName                  = "/cc_tools_integration.CCToolsIntegration/CheckLock"
+	CCToolsIntegration_ListLocks_FullMethodName                  = "/cc_tools_integration.CCToolsIntegration/ListLocks"
+	CCToolsIntegration_LockAndValidate_FullMethodName            = "/cc_tools_integration.CCToolsIntegration/LockAndValidate"
+	CCToolsIntegration_StreamValidation_FullMethodName           = "/cc_tools_integration.CCToolsIntegration/StreamValidation"
+	CCToolsIntegration_GetValidationLog_FullMethodName           = "/cc_tools_integration.CCToolsIntegration/GetValidationLog"
+	CCToolsIntegration_ValidateProjects_FullMethodName           = "/cc_tools_integration.CCToolsIntegration/ValidateProjects"
+	CCToolsIntegration_GetProjectMetadataBatch_FullMethodName    = "/cc_tools_integration.CCToolsIntegration/GetProjectMetadataBatch"
+	CCToolsIntegration_DiscoverSubProjects_FullMethodName        = "/cc_tools_integration.CCToolsIntegration/DiscoverSubProjects"
+	CCToolsIntegration_AbortAll_FullMethodName                   = "/cc_tools_integration.CCToolsIntegration/AbortAll"
+	CCToolsIntegration_CancelValidationsByProject_FullMethodName = "/cc_tools_integration.CCToolsIntegration/CancelValidationsByProject"
+	CCToolsIntegration_CancelValidation_FullMethodName           = "/cc_tools_integration.CCToolsIntegration/CancelValidation"
+	CCToolsIntegration_GetValidatorDefinition_FullMethodName     = "/cc_tools_integration.CCToolsIntegration/GetValidatorDefinition"
+	CCToolsIntegration_GetSupportedProjectTypes_FullMethodName   = "/cc_tools_integration.CCToolsIntegration/GetSupportedProjectTypes"
+	CCToolsIntegration_GetStats_FullMethodName                   = "/cc_tools_integration.CCToolsIntegration/GetStats"
 )
 
 // CCToolsIntegrationClient is the client API for CCToolsIntegration service.
@@ -42,6 +55,33 @@ type CCToolsIntegrationClient interface {
 	ReleaseLock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockStatus, error)
 	// Check lock status
 	CheckLock(ctx context.Context, in *LockRequest, opts ...grpc.CallOption) (*LockStatus, error)
+	// List the locks held through this server, including stale ones
+	ListLocks(ctx context.Context, in *ListLocksRequest, opts ...grpc.CallOption) (*ListLocksResponse, error)
+	// Acquire the project lock, validate and release the lock in one call
+	LockAndValidate(ctx context.Context, in *LockAndValidateRequest, opts ...grpc.CallOption) (*LockAndValidateResponse, error)
+	// Validate a project, streaming validator output as it is produced; cancelling the stream kills running validators
+	StreamValidation(ctx context.Context, in *ValidationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ValidationEvent], error)
+	// Fetch the persisted output of a streamed run's validator
+	GetValidationLog(ctx context.Context, in *ValidationLogRequest, opts ...grpc.CallOption) (*ValidationLog, error)
+	// Validate several projects in one call
+	ValidateProjects(ctx context.Context, in *BatchValidationRequest, opts ...grpc.CallOption) (*BatchValidationResponse, error)
+	// Get project metadata for several projects in one call
+	GetProjectMetadataBatch(ctx context.Context, in *BatchValidationRequest, opts ...grpc.CallOption) (*ProjectMetadataBatchResponse, error)
+	// Find the projects nested in a monorepo
+	DiscoverSubProjects(ctx context.Context, in *DiscoverSubProjectsRequest, opts ...grpc.CallOption) (*DiscoverSubProjectsResponse, error)
+	// Abort every running validation (admin only, requires ADMIN_TOKEN)
+	AbortAll(ctx context.Context, in *AbortAllRequest, opts ...grpc.CallOption) (*AbortAllResponse, error)
+	// Abort the running validations of one project (admin only, requires ADMIN_TOKEN)
+	CancelValidationsByProject(ctx context.Context, in *CancelValidationsRequest, opts ...grpc.CallOption) (*CancelValidationsResponse, error)
+	// Cancel one running validation by run id; its validators fail with
+	// FAILURE_REASON_CANCELLED. NotFound once the run has finished.
+	CancelValidation(ctx context.Context, in *CancelValidationRequest, opts ...grpc.CallOption) (*CancelValidationResponse, error)
+	// Get the resolved definition of a single validator without running it
+	GetValidatorDefinition(ctx context.Context, in *ValidatorDefinitionRequest, opts ...grpc.CallOption) (*ValidatorDefinition, error)
+	// List the project types this server detects
+	GetSupportedProjectTypes(ctx context.Context, in *SupportedProjectTypesRequest, opts ...grpc.CallOption) (*SupportedProjectTypes, error)
+	// Get server statistics (lock contention per project)
+	GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*ServerStats, error)
 }
 
 type cCToolsIntegrationClient struct {
@@ -102,6 +142,145 @@ func (c *cCToolsIntegrationClient) CheckLock(ctx context.Context, in *LockReques
 	return out, nil
 }
 
+func (c *cCToolsIntegrationClient) ListLocks(ctx context.Context, in *ListLocksRequest, opts ...grpc.CallOption) (*ListLocksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListLocksResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_ListLocks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) LockAndValidate(ctx context.Context, in *LockAndValidateRequest, opts ...grpc.CallOption) (*LockAndValidateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LockAndValidateResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_LockAndValidate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) StreamValidation(ctx context.Context, in *ValidationRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ValidationEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CCToolsIntegration_ServiceDesc.Streams[0], CCToolsIntegration_StreamValidation_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ValidationRequest, ValidationEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CCToolsIntegration_StreamValidationClient = grpc.ServerStreamingClient[ValidationEvent]
+
+func (c *cCToolsIntegrationClient) GetValidationLog(ctx context.Context, in *ValidationLogRequest, opts ...grpc.CallOption) (*ValidationLog, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidationLog)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_GetValidationLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) ValidateProjects(ctx context.Context, in *BatchValidationRequest, opts ...grpc.CallOption) (*BatchValidationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchValidationResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_ValidateProjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) GetProjectMetadataBatch(ctx context.Context, in *BatchValidationRequest, opts ...grpc.CallOption) (*ProjectMetadataBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProjectMetadataBatchResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_GetProjectMetadataBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) DiscoverSubProjects(ctx context.Context, in *DiscoverSubProjectsRequest, opts ...grpc.CallOption) (*DiscoverSubProjectsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscoverSubProjectsResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_DiscoverSubProjects_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) AbortAll(ctx context.Context, in *AbortAllRequest, opts ...grpc.CallOption) (*AbortAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AbortAllResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_AbortAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) CancelValidationsByProject(ctx context.Context, in *CancelValidationsRequest, opts ...grpc.CallOption) (*CancelValidationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelValidationsResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_CancelValidationsByProject_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) CancelValidation(ctx context.Context, in *CancelValidationRequest, opts ...grpc.CallOption) (*CancelValidationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelValidationResponse)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_CancelValidation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) GetValidatorDefinition(ctx context.Context, in *ValidatorDefinitionRequest, opts ...grpc.CallOption) (*ValidatorDefinition, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidatorDefinition)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_GetValidatorDefinition_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) GetSupportedProjectTypes(ctx context.Context, in *SupportedProjectTypesRequest, opts ...grpc.CallOption) (*SupportedProjectTypes, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SupportedProjectTypes)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_GetSupportedProjectTypes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cCToolsIntegrationClient) GetStats(ctx context.Context, in *StatsRequest, opts ...grpc.CallOption) (*ServerStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerStats)
+	err := c.cc.Invoke(ctx, CCToolsIntegration_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CCToolsIntegrationServer is the server API for CCToolsIntegration service.
 // All implementations must embed UnimplementedCCToolsIntegrationServer
 // for forward compatibility.
@@ -118,6 +297,33 @@ type CCToolsIntegrationServer interface {
 	ReleaseLock(context.Context, *LockRequest) (*LockStatus, error)
 	// Check lock status
 	CheckLock(context.Context, *LockRequest) (*LockStatus, error)
+	// List the locks held through this server, including stale ones
+	ListLocks(context.Context, *ListLocksRequest) (*ListLocksResponse, error)
+	// Acquire the project lock, validate and release the lock in one call
+	LockAndValidate(context.Context, *LockAndValidateRequest) (*LockAndValidateResponse, error)
+	// Validate a project, streaming validator output as it is produced; cancelling the stream kills running validators
+	StreamValidation(*ValidationRequest, grpc.ServerStreamingServer[ValidationEvent]) error
+	// Fetch the persisted output of a streamed run's validator
+	GetValidationLog(context.Context, *ValidationLogRequest) (*ValidationLog, error)
+	// Validate several projects in one call
+	ValidateProjects(context.Context, *BatchValidationRequest) (*BatchValidationResponse, error)
+	// Get project metadata for several projects in one call
+	GetProjectMetadataBatch(context.Context, *BatchValidationRequest) (*ProjectMetadataBatchResponse, error)
+	// Find the projects nested in a monorepo
+	DiscoverSubProjects(context.Context, *DiscoverSubProjectsRequest) (*DiscoverSubProjectsResponse, error)
+	// Abort every running validation (admin only, requires ADMIN_TOKEN)
+	AbortAll(context.Context, *AbortAllRequest) (*AbortAllResponse, error)
+	// Abort the running validations of one project (admin only, requires ADMIN_TOKEN)
+	CancelValidationsByProject(context.Context, *CancelValidationsRequest) (*CancelValidationsResponse, error)
+	// Cancel one running validation by run id; its validators fail with
+	// FAILURE_REASON_CANCELLED. NotFound once the run has finished.
+	CancelValidation(context.Context, *CancelValidationRequest) (*CancelValidationResponse, error)
+	// Get the resolved definition of a single validator without running it
+	GetValidatorDefinition(context.Context, *ValidatorDefinitionRequest) (*ValidatorDefinition, error)
+	// List the project types this server detects
+	GetSupportedProjectTypes(context.Context, *SupportedProjectTypesRequest) (*SupportedProjectTypes, error)
+	// Get server statistics (lock contention per project)
+	GetStats(context.Context, *StatsRequest) (*ServerStats, error)
 	mustEmbedUnimplementedCCToolsIntegrationServer()
 }
 
@@ -143,6 +349,45 @@ func (UnimplementedCCToolsIntegrationServer) ReleaseLock(context.Context, *LockR
 func (UnimplementedCCToolsIntegrationServer) CheckLock(context.Context, *LockRequest) (*LockStatus, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CheckLock not implemented")
 }
+func (UnimplementedCCToolsIntegrationServer) ListLocks(context.Context, *ListLocksRequest) (*ListLocksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListLocks not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) LockAndValidate(context.Context, *LockAndValidateRequest) (*LockAndValidateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LockAndValidate not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) StreamValidation(*ValidationRequest, grpc.ServerStreamingServer[ValidationEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamValidation not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) GetValidationLog(context.Context, *ValidationLogRequest) (*ValidationLog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetValidationLog not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) ValidateProjects(context.Context, *BatchValidationRequest) (*BatchValidationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateProjects not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) GetProjectMetadataBatch(context.Context, *BatchValidationRequest) (*ProjectMetadataBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProjectMetadataBatch not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) DiscoverSubProjects(context.Context, *DiscoverSubProjectsRequest) (*DiscoverSubProjectsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscoverSubProjects not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) AbortAll(context.Context, *AbortAllRequest) (*AbortAllResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AbortAll not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) CancelValidationsByProject(context.Context, *CancelValidationsRequest) (*CancelValidationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelValidationsByProject not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) CancelValidation(context.Context, *CancelValidationRequest) (*CancelValidationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelValidation not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) GetValidatorDefinition(context.Context, *ValidatorDefinitionRequest) (*ValidatorDefinition, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetValidatorDefinition not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) GetSupportedProjectTypes(context.Context, *SupportedProjectTypesRequest) (*SupportedProjectTypes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSupportedProjectTypes not implemented")
+}
+func (UnimplementedCCToolsIntegrationServer) GetStats(context.Context, *StatsRequest) (*ServerStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
 func (UnimplementedCCToolsIntegrationServer) mustEmbedUnimplementedCCToolsIntegrationServer() {}
 func (UnimplementedCCToolsIntegrationServer) testEmbeddedByValue()                            {}
 
@@ -254,6 +499,233 @@ func _CCToolsIntegration_CheckLock_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _CCToolsIntegration_ListLocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListLocksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).ListLocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_ListLocks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).ListLocks(ctx, req.(*ListLocksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_LockAndValidate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LockAndValidateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).LockAndValidate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_LockAndValidate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).LockAndValidate(ctx, req.(*LockAndValidateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_StreamValidation_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ValidationRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CCToolsIntegrationServer).StreamValidation(m, &grpc.GenericServerStream[ValidationRequest, ValidationEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CCToolsIntegration_StreamValidationServer = grpc.ServerStreamingServer[ValidationEvent]
+
+func _CCToolsIntegration_GetValidationLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidationLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).GetValidationLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_GetValidationLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).GetValidationLog(ctx, req.(*ValidationLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_ValidateProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchValidationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).ValidateProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_ValidateProjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).ValidateProjects(ctx, req.(*BatchValidationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_GetProjectMetadataBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchValidationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).GetProjectMetadataBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_GetProjectMetadataBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).GetProjectMetadataBatch(ctx, req.(*BatchValidationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_DiscoverSubProjects_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverSubProjectsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).DiscoverSubProjects(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_DiscoverSubProjects_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).DiscoverSubProjects(ctx, req.(*DiscoverSubProjectsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_AbortAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AbortAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).AbortAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_AbortAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).AbortAll(ctx, req.(*AbortAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_CancelValidationsByProject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelValidationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).CancelValidationsByProject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_CancelValidationsByProject_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).CancelValidationsByProject(ctx, req.(*CancelValidationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_CancelValidation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelValidationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).CancelValidation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_CancelValidation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).CancelValidation(ctx, req.(*CancelValidationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_GetValidatorDefinition_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidatorDefinitionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).GetValidatorDefinition(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_GetValidatorDefinition_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).GetValidatorDefinition(ctx, req.(*ValidatorDefinitionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_GetSupportedProjectTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SupportedProjectTypesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).GetSupportedProjectTypes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_GetSupportedProjectTypes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).GetSupportedProjectTypes(ctx, req.(*SupportedProjectTypesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CCToolsIntegration_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CCToolsIntegrationServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CCToolsIntegration_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CCToolsIntegrationServer).GetStats(ctx, req.(*StatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // CCToolsIntegration_ServiceDesc is the grpc.ServiceDesc for CCToolsIntegration service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -281,7 +753,61 @@ var CCToolsIntegration_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CheckLock",
 			Handler:    _CCToolsIntegration_CheckLock_Handler,
 		},
+		{
+			MethodName: "ListLocks",
+			Handler:    _CCToolsIntegration_ListLocks_Handler,
+		},
+		{
+			MethodName: "LockAndValidate",
+			Handler:    _CCToolsIntegration_LockAndValidate_Handler,
+		},
+		{
+			MethodName: "GetValidationLog",
+			Handler:    _CCToolsIntegration_GetValidationLog_Handler,
+		},
+		{
+			MethodName: "ValidateProjects",
+			Handler:    _CCToolsIntegration_ValidateProjects_Handler,
+		},
+		{
+			MethodName: "GetProjectMetadataBatch",
+			Handler:    _CCToolsIntegration_GetProjectMetadataBatch_Handler,
+		},
+		{
+			MethodName: "DiscoverSubProjects",
+			Handler:    _CCToolsIntegration_DiscoverSubProjects_Handler,
+		},
+		{
+			MethodName: "AbortAll",
+			Handler:    _CCToolsIntegration_AbortAll_Handler,
+		},
+		{
+			MethodName: "CancelValidationsByProject",
+			Handler:    _CCToolsIntegration_CancelValidationsByProject_Handler,
+		},
+		{
+			MethodName: "CancelValidation",
+			Handler:    _CCToolsIntegration_CancelValidation_Handler,
+		},
+		{
+			MethodName: "GetValidatorDefinition",
+			Handler:    _CCToolsIntegration_GetValidatorDefinition_Handler,
+		},
+		{
+			MethodName: "GetSupportedProjectTypes",
+			Handler:    _CCToolsIntegration_GetSupportedProjectTypes_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _CCToolsIntegration_GetStats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamValidation",
+			Handler:       _CCToolsIntegration_StreamValidation_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/cc_tools_integration.proto",
 }
@@ -0,0 +1,83 @@
+package main
+
+import (
+    "os"
+    "path"
+    "path/filepath"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// pythonMarkers are the files that identify a Python project, in the order
+// they are tried; all of them present in the marker directory are reported
+// as config files
+var pythonMarkers = []string{"pyproject.toml", "setup.py", "requirements.txt"}
+
+// flake8ConfigFiles hold flake8 settings; ruff configures itself in
+// pyproject.toml [tool.ruff], ruff.toml or .ruff.toml
+var flake8ConfigFiles = []string{".flake8", "setup.cfg", "tox.ini"}
+
+// detectPython refines a Python project found in dir. Lint defaults to
+// ruff and falls back to flake8 only for projects configured for flake8
+// and not ruff. Poetry projects, those with [tool.poetry] in
+// pyproject.toml, run tests inside the poetry environment.
+func (s *CCToolsServer) detectPython(metadata *pb.ProjectMetadata, dir, marker string) {
+    // The matched marker is the highest-priority one present; report the others too
+    root := filepath.Join(metadata.ProjectRoot, dir)
+    for _, other := range pythonMarkers {
+        if other != marker && s.fileExists(filepath.Join(root, other)) {
+            metadata.ConfigFiles = append(metadata.ConfigFiles, path.Join(dir, other))
+        }
+    }
+
+    pyproject, err := os.ReadFile(filepath.Join(root, "pyproject.toml"))
+    if err != nil && !os.IsNotExist(err) {
+        metadata.Warnings = append(metadata.Warnings, "python detection: "+err.Error())
+    }
+
+    if !s.usesRuff(root, pyproject) && s.usesFlake8(root) {
+        metadata.Commands["lint"] = "flake8 ."
+    }
+    if tomlHasSection(pyproject, "tool.poetry") {
+        metadata.Commands["test"] = "poetry run pytest"
+    }
+}
+
+// usesRuff reports whether the project configures ruff
+func (s *CCToolsServer) usesRuff(root string, pyproject []byte) bool {
+    return tomlHasSection(pyproject, "tool.ruff") ||
+        s.fileExists(filepath.Join(root, "ruff.toml")) ||
+        s.fileExists(filepath.Join(root, ".ruff.toml"))
+}
+
+// usesFlake8 reports whether a flake8 config file has a [flake8] section
+func (s *CCToolsServer) usesFlake8(root string) bool {
+    for _, name := range flake8ConfigFiles {
+        data, err := os.ReadFile(filepath.Join(root, name))
+        if err == nil && (name == ".flake8" || tomlHasSection(data, "flake8")) {
+            return true
+        }
+    }
+    return false
+}
+
+// pythonProjectName reads the name from pyproject.toml, [project] (PEP 621)
+// before [tool.poetry]; console scripts are the binaries. Projects without
+// pyproject.toml declare their name in code (setup.py) or not at all.
+func pythonProjectName(dir string) (string, []string, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "pyproject.toml"))
+    if os.IsNotExist(err) {
+        return "", nil, nil
+    }
+    if err != nil {
+        return "", nil, err
+    }
+
+    name, ok := tomlString(data, "project", "name")
+    if !ok {
+        name, _ = tomlString(data, "tool.poetry", "name")
+    }
+    binaries := tomlKeys(data, "project.scripts")
+    binaries = append(binaries, tomlKeys(data, "tool.poetry.scripts")...)
+    return name, binaries, nil
+}
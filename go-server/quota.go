@@ -0,0 +1,60 @@
+package main
+
+import (
+    "strconv"
+    "sync"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+// identityQuotas caps the validations each caller identity may run at once,
+// so one tenant cannot take every slot while global capacity remains.
+// Limits come from IDENTITY_QUOTAS (e.g. "team-a=4,team-b=2"); any other
+// identity, including unauthenticated callers (who share the "" identity),
+// gets IDENTITY_DEFAULT_QUOTA. A limit of 0 means unlimited.
+type identityQuotas struct {
+    limits       map[string]int
+    defaultLimit int
+
+    mu      sync.Mutex
+    running map[string]int
+}
+
+func loadIdentityQuotas() *identityQuotas {
+    q := &identityQuotas{
+        limits:       make(map[string]int),
+        defaultLimit: envInt("IDENTITY_DEFAULT_QUOTA", 0),
+        running:      make(map[string]int),
+    }
+    for identity, v := range envMap("IDENTITY_QUOTAS") {
+        if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+            q.limits[identity] = n
+        }
+    }
+    return q
+}
+
+// acquire reserves a validation for identity, returning ResourceExhausted
+// when it is at its quota. The returned func releases the reservation.
+func (q *identityQuotas) acquire(identity string) (func(), error) {
+    limit, ok := q.limits[identity]
+    if !ok {
+        limit = q.defaultLimit
+    }
+
+    q.mu.Lock()
+    defer q.mu.Unlock()
+    if limit > 0 && q.running[identity] >= limit {
+        return nil, status.Errorf(codes.ResourceExhausted, "identity %q is at its quota of %d concurrent validations", identity, limit)
+    }
+    q.running[identity]++
+
+    return func() {
+        q.mu.Lock()
+        defer q.mu.Unlock()
+        if q.running[identity]--; q.running[identity] == 0 {
+            delete(q.running, identity)
+        }
+    }, nil
+}
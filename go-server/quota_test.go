@@ -0,0 +1,105 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestIdentityQuotas(t *testing.T) {
+    t.Setenv("IDENTITY_QUOTAS", "team-a=2,team-b=0")
+    t.Setenv("IDENTITY_DEFAULT_QUOTA", "1")
+    q := loadIdentityQuotas()
+
+    // team-a has its own limit of 2
+    releaseA1, err := q.acquire("team-a")
+    if err != nil {
+        t.Fatalf("first team-a acquire: %v", err)
+    }
+    if _, err := q.acquire("team-a"); err != nil {
+        t.Fatalf("second team-a acquire: %v", err)
+    }
+    if _, err := q.acquire("team-a"); status.Code(err) != codes.ResourceExhausted {
+        t.Fatalf("third team-a acquire = %v, want ResourceExhausted", err)
+    }
+    releaseA1()
+    if _, err := q.acquire("team-a"); err != nil {
+        t.Errorf("team-a acquire after a release: %v", err)
+    }
+
+    // team-b is unlimited
+    for i := 0; i < 5; i++ {
+        if _, err := q.acquire("team-b"); err != nil {
+            t.Fatalf("team-b acquire %d: %v", i, err)
+        }
+    }
+
+    // Unmatched and unauthenticated identities get the default quota,
+    // each counted on its own
+    for _, identity := range []string{"", "team-c"} {
+        if _, err := q.acquire(identity); err != nil {
+            t.Fatalf("first %q acquire: %v", identity, err)
+        }
+        if _, err := q.acquire(identity); status.Code(err) != codes.ResourceExhausted {
+            t.Errorf("second %q acquire = %v, want ResourceExhausted", identity, err)
+        }
+    }
+}
+
+func TestIdentityQuotaRejectsTenantOverQuota(t *testing.T) {
+    pki := newTestPKI(t)
+    pki.setEnv(t, true)
+    t.Setenv("IDENTITY_QUOTAS", "team-a=1")
+    t.Setenv("IDENTITY_DEFAULT_QUOTA", "0")
+    ts := newTestServer(t, serverConfig{tls: true},
+        grpc.WithTransportCredentials(credentials.NewTLS(pki.clientConfig(pki.clientCert(t, "team-a")))))
+    teamB := pb.NewCCToolsIntegrationClient(ts.dial(t,
+        grpc.WithTransportCredentials(credentials.NewTLS(pki.clientConfig(pki.clientCert(t, "team-b"))))))
+    ctx := context.Background()
+
+    // team-a's first validation blocks until the test releases it
+    signals := t.TempDir()
+    started, release := filepath.Join(signals, "started"), filepath.Join(signals, "release")
+    blocking := makeProject(t, map[string]string{
+        "lint": "echo running >" + started + "; while [ ! -f " + release + " ]; do sleep 0.05; done",
+        "test": "true",
+    })
+    first := make(chan error, 1)
+    go func() {
+        _, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: blocking})
+        first <- err
+    }()
+    waitForFile(t, started)
+
+    quick := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+    if _, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: quick}); status.Code(err) != codes.ResourceExhausted {
+        t.Errorf("second team-a validation = %v, want ResourceExhausted", err)
+    }
+    if _, err := teamB.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: quick}); err != nil {
+        t.Errorf("team-b validation while team-a is at its quota: %v", err)
+    }
+
+    if err := os.WriteFile(release, nil, 0o644); err != nil {
+        t.Fatal(err)
+    }
+    select {
+    case err := <-first:
+        if err != nil {
+            t.Fatalf("first team-a validation: %v", err)
+        }
+    case <-time.After(30 * time.Second):
+        t.Fatal("first team-a validation did not finish")
+    }
+    if _, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: quick}); err != nil {
+        t.Errorf("team-a validation after its quota freed up: %v", err)
+    }
+}
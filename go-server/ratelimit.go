@@ -0,0 +1,200 @@
+package main
+
+import (
+    "context"
+    "net"
+    "sync"
+    "time"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/peer"
+    "google.golang.org/grpc/status"
+)
+
+// Rate limiting
+//
+// Validation RPCs spawn subprocesses, so a client calling them in a tight
+// loop can exhaust the host long before MAX_INFLIGHT_VALIDATIONS notices.
+// Two token buckets guard the methods in rateLimitedMethods: a global one
+// (RATE_LIMIT_RPS, RATE_LIMIT_BURST) and one per caller
+// (RATE_LIMIT_PEER_RPS, RATE_LIMIT_PEER_BURST), where the caller is its
+// TLS identity or else its IP address. A rate of 0 disables a bucket, and
+// a burst defaults to the rate. Calls over a limit fail with
+// ResourceExhausted (429 on the HTTP gateway); health, lock and metadata
+// RPCs are never limited.
+
+// rateLimitedMethods are the RPCs that start validations
+var rateLimitedMethods = map[string]bool{
+    "/cc_tools_integration.CCToolsIntegration/ValidateProject":  true,
+    "/cc_tools_integration.CCToolsIntegration/StreamValidation": true,
+    "/cc_tools_integration.CCToolsIntegration/ValidateProjects": true,
+    "/cc_tools_integration.CCToolsIntegration/LockAndValidate":  true,
+}
+
+// maxRateLimitPeers bounds the per-peer buckets kept; full buckets are
+// dropped first since they are indistinguishable from new ones
+const maxRateLimitPeers = 4096
+
+// tokenBucket allows rate events per second with bursts of up to burst
+type tokenBucket struct {
+    rate   float64
+    burst  float64
+    tokens float64
+    last   time.Time
+}
+
+func newTokenBucket(rate, burst int, now time.Time) *tokenBucket {
+    return &tokenBucket{rate: float64(rate), burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+// take spends a token if one is available
+func (b *tokenBucket) take(now time.Time) bool {
+    b.refill(now)
+    if b.tokens < 1 {
+        return false
+    }
+    b.tokens--
+    return true
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+    b.tokens += now.Sub(b.last).Seconds() * b.rate
+    if b.tokens > b.burst {
+        b.tokens = b.burst
+    }
+    b.last = now
+}
+
+// rateLimiter applies the global and per-peer buckets
+type rateLimiter struct {
+    peerRate  int
+    peerBurst int
+
+    mu     sync.Mutex
+    global *tokenBucket // nil when the global limit is disabled
+    peers  map[string]*tokenBucket
+}
+
+// loadRateLimiter reads the RATE_LIMIT_* settings; it returns nil when
+// both limits are disabled
+func loadRateLimiter() *rateLimiter {
+    rate, peerRate := envInt("RATE_LIMIT_RPS", 0), envInt("RATE_LIMIT_PEER_RPS", 0)
+    if rate <= 0 && peerRate <= 0 {
+        return nil
+    }
+    l := &rateLimiter{
+        peerRate:  peerRate,
+        peerBurst: envInt("RATE_LIMIT_PEER_BURST", peerRate),
+        peers:     make(map[string]*tokenBucket),
+    }
+    if rate > 0 {
+        l.global = newTokenBucket(rate, envInt("RATE_LIMIT_BURST", rate), time.Now())
+    }
+    return l
+}
+
+// allow reports whether a call from peerKey may proceed, returning a
+// ResourceExhausted status when it may not. A nil limiter allows everything.
+func (l *rateLimiter) allow(peerKey string) error {
+    if l == nil {
+        return nil
+    }
+    now := time.Now()
+
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    var bucket *tokenBucket
+    if l.peerRate > 0 {
+        bucket = l.peers[peerKey]
+        if bucket == nil {
+            l.prunePeers(now)
+            bucket = newTokenBucket(l.peerRate, l.peerBurst, now)
+            l.peers[peerKey] = bucket
+        }
+        // Check without spending so a rejected peer call leaves the global bucket alone
+        if bucket.refill(now); bucket.tokens < 1 {
+            return status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s: %d validations per second", peerKey, l.peerRate)
+        }
+    }
+    if l.global != nil && !l.global.take(now) {
+        return status.Errorf(codes.ResourceExhausted, "server rate limit exceeded: %d validations per second", int(l.global.rate))
+    }
+    if bucket != nil {
+        bucket.take(now)
+    }
+    return nil
+}
+
+// prunePeers makes room for a new peer bucket
+func (l *rateLimiter) prunePeers(now time.Time) {
+    if len(l.peers) < maxRateLimitPeers {
+        return
+    }
+    for key, bucket := range l.peers {
+        if bucket.refill(now); bucket.tokens >= bucket.burst {
+            delete(l.peers, key)
+        }
+    }
+    // Every peer is active: evict an arbitrary one rather than grow
+    for key := range l.peers {
+        if len(l.peers) < maxRateLimitPeers {
+            break
+        }
+        delete(l.peers, key)
+    }
+}
+
+// rateLimitPeerKey identifies the caller: its TLS identity, or its IP address
+func rateLimitPeerKey(ctx context.Context) string {
+    if identity := identityFromContext(ctx); identity != "" {
+        return identity
+    }
+    if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+        return addrHost(p.Addr.String())
+    }
+    return ""
+}
+
+// addrHost strips the port from a host:port address
+func addrHost(addr string) string {
+    if host, _, err := net.SplitHostPort(addr); err == nil {
+        return host
+    }
+    return addr
+}
+
+// rateLimitUnaryInterceptor rejects validation calls over the rate limits
+func rateLimitUnaryInterceptor(limiter *rateLimiter) grpc.UnaryServerInterceptor {
+    return func(
+        ctx context.Context,
+        req interface{},
+        info *grpc.UnaryServerInfo,
+        handler grpc.UnaryHandler,
+    ) (interface{}, error) {
+        if rateLimitedMethods[info.FullMethod] {
+            if err := limiter.allow(rateLimitPeerKey(ctx)); err != nil {
+                return nil, err
+            }
+        }
+        return handler(ctx, req)
+    }
+}
+
+// rateLimitStreamInterceptor is rateLimitUnaryInterceptor for streams
+func rateLimitStreamInterceptor(limiter *rateLimiter) grpc.StreamServerInterceptor {
+    return func(
+        srv interface{},
+        ss grpc.ServerStream,
+        info *grpc.StreamServerInfo,
+        handler grpc.StreamHandler,
+    ) error {
+        if rateLimitedMethods[info.FullMethod] {
+            if err := limiter.allow(rateLimitPeerKey(ss.Context())); err != nil {
+                return err
+            }
+        }
+        return handler(srv, ss)
+    }
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestTokenBucket(t *testing.T) {
+    start := time.Now()
+    b := newTokenBucket(2, 3, start)
+    for i := 0; i < 3; i++ {
+        if !b.take(start) {
+            t.Fatalf("take %d within the burst failed", i)
+        }
+    }
+    if b.take(start) {
+        t.Fatal("take beyond the burst succeeded")
+    }
+    // Two tokens per second: one is back after half a second
+    if !b.take(start.Add(500 * time.Millisecond)) {
+        t.Error("no token after refilling for 500ms")
+    }
+    if b.take(start.Add(500 * time.Millisecond)) {
+        t.Error("a second token after refilling for 500ms")
+    }
+    // Refills never exceed the burst
+    later := start.Add(time.Hour)
+    for i := 0; i < 3; i++ {
+        b.take(later)
+    }
+    if b.take(later) {
+        t.Error("bucket refilled past its burst")
+    }
+}
+
+func TestRateLimiterPerPeer(t *testing.T) {
+    t.Setenv("RATE_LIMIT_RPS", "0")
+    t.Setenv("RATE_LIMIT_PEER_RPS", "1")
+    t.Setenv("RATE_LIMIT_PEER_BURST", "2")
+    l := loadRateLimiter()
+
+    for i := 0; i < 2; i++ {
+        if err := l.allow("team-a"); err != nil {
+            t.Fatalf("team-a call %d: %v", i, err)
+        }
+    }
+    if err := l.allow("team-a"); status.Code(err) != codes.ResourceExhausted {
+        t.Errorf("team-a over its burst = %v, want ResourceExhausted", err)
+    }
+    // Another peer has its own bucket
+    if err := l.allow("team-b"); err != nil {
+        t.Errorf("team-b while team-a is limited: %v", err)
+    }
+
+    var disabled *rateLimiter
+    if err := disabled.allow("team-a"); err != nil {
+        t.Errorf("nil limiter rejected a call: %v", err)
+    }
+}
+
+func TestRateLimitRejectsValidationStorm(t *testing.T) {
+    t.Setenv("RATE_LIMIT_RPS", "1")
+    t.Setenv("RATE_LIMIT_BURST", "2")
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+
+    passed, rejected := 0, 0
+    for i := 0; i < 6; i++ {
+        _, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root})
+        switch status.Code(err) {
+        case codes.OK:
+            passed++
+        case codes.ResourceExhausted:
+            rejected++
+        default:
+            t.Fatalf("ValidateProject: %v", err)
+        }
+    }
+    if rejected == 0 || passed < 2 {
+        t.Errorf("%d passed and %d rejected, want the burst of 2 through and the rest rejected", passed, rejected)
+    }
+
+    // Lock and health-style calls are not limited
+    for i := 0; i < 10; i++ {
+        if _, err := ts.client.CheckLock(ctx, &pb.LockRequest{ProjectPath: root}); err != nil {
+            t.Fatalf("CheckLock %d: %v", i, err)
+        }
+    }
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "log/slog"
+    "os"
+    "os/exec"
+    "sync"
+    "time"
+
+    health "google.golang.org/grpc/health"
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Liveness and readiness
+//
+// The overall health entry ("") is liveness: it turns SERVING once the
+// server has started (after the optional warm-up) and only goes back at
+// shutdown. The cc_tools_integration.CCToolsIntegration entry is
+// readiness: every READINESS_INTERVAL_SECONDS (10, 0 disables) the server
+// runs a trivial command and writes a scratch file to the temp directory
+// and the validation log directory, and the entry follows the result. A
+// server that is alive but cannot spawn processes or write files is
+// therefore taken out of rotation without being restarted. Kubernetes can
+// probe either entry with a gRPC probe; the HTTP gateway mirrors them as
+// /healthz and /readyz. Readiness also drops while the server is
+// overloaded (see overload.go).
+
+// readinessService is the health entry that reports readiness
+const readinessService = "cc_tools_integration.CCToolsIntegration"
+
+// readinessTimeout bounds one readiness check
+const readinessTimeout = 5 * time.Second
+
+// readinessState holds the outcome of the latest readiness check
+type readinessState struct {
+    mu        sync.Mutex
+    err       error
+    checkedAt time.Time
+
+    // started is set once the server is serving; until then readiness is
+    // left to the warm-up
+    started bool
+
+    // overloaded is set while more validations run than DEGRADED_INFLIGHT_THRESHOLD
+    overloaded bool
+}
+
+// errOverloaded is reported by /readyz while the server is overloaded
+var errOverloaded = errors.New("overloaded: too many validations running")
+
+// result returns when the last check ran (zero before the first) and its
+// error, or errOverloaded when the check passed but the server is overloaded
+func (r *readinessState) result() (time.Time, error) {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    if r.err == nil && r.overloaded {
+        return r.checkedAt, errOverloaded
+    }
+    return r.checkedAt, r.err
+}
+
+// publish sets the readiness entry from the latest check and the load; r.mu
+// must be held
+func (r *readinessState) publish(hs *health.Server) {
+    if hs == nil || !r.started {
+        return
+    }
+    if r.err != nil || r.overloaded {
+        hs.SetServingStatus(readinessService, healthpb.HealthCheckResponse_NOT_SERVING)
+        return
+    }
+    hs.SetServingStatus(readinessService, healthpb.HealthCheckResponse_SERVING)
+}
+
+// startReadiness begins the periodic readiness check unless disabled
+func (s *CCToolsServer) startReadiness(hs *health.Server) {
+    s.readiness.mu.Lock()
+    s.readiness.started = true
+    s.readiness.publish(hs)
+    s.readiness.mu.Unlock()
+
+    if interval := envInt("READINESS_INTERVAL_SECONDS", 10); interval > 0 {
+        go s.watchReadiness(hs, time.Duration(interval)*time.Second)
+    }
+}
+
+// watchReadiness re-checks readiness every interval and flips the
+// readiness health entry to match
+func (s *CCToolsServer) watchReadiness(hs *health.Server, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    s.updateReadiness(hs)
+    for range ticker.C {
+        s.updateReadiness(hs)
+    }
+}
+
+func (s *CCToolsServer) updateReadiness(hs *health.Server) {
+    ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+    defer cancel()
+    err := s.checkReadiness(ctx)
+
+    s.readiness.mu.Lock()
+    defer s.readiness.mu.Unlock()
+    wasReady := s.readiness.err == nil
+    s.readiness.err = err
+    s.readiness.checkedAt = time.Now()
+    s.readiness.publish(hs)
+
+    switch {
+    case err != nil && wasReady:
+        slog.Warn("readiness: not ready", "error", err)
+    case err == nil && !wasReady:
+        slog.Info("readiness: ready again")
+    }
+}
+
+// checkReadiness verifies the server can do what validations and locks
+// need: start a process and write files. With LOCK_FILES the lock files live
+// in the project roots, so the lock directories probed are the allowed roots
+// (ALLOWED_ROOTS) every project must sit under; they are never created.
+func (s *CCToolsServer) checkReadiness(ctx context.Context) error {
+    if err := exec.CommandContext(ctx, "sh", "-c", "exit 0").Run(); err != nil {
+        return fmt.Errorf("cannot run commands: %w", err)
+    }
+
+    dirs := []string{os.TempDir()}
+    if s.validationLogs != nil {
+        if err := os.MkdirAll(s.validationLogs.dir, 0o700); err != nil {
+            return fmt.Errorf("cannot write to %s: %w", s.validationLogs.dir, err)
+        }
+        dirs = append(dirs, s.validationLogs.dir)
+    }
+    if s.lockManager.useLockFiles {
+        dirs = append(dirs, s.limits.allowedRoots...)
+    }
+    for _, dir := range dirs {
+        if err := probeWritable(dir); err != nil {
+            return fmt.Errorf("cannot write to %s: %w", dir, err)
+        }
+    }
+    return nil
+}
+
+// probeWritable creates, writes and removes a scratch file in dir
+func probeWritable(dir string) error {
+    f, err := os.CreateTemp(dir, ".cc-tools-ready-*")
+    if err != nil {
+        return err
+    }
+    defer os.Remove(f.Name())
+    if _, err := f.WriteString("ok"); err != nil {
+        f.Close()
+        return err
+    }
+    return f.Close()
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// waitForHealth polls service until it reports want
+func waitForHealth(t *testing.T, client healthpb.HealthClient, service string, want healthpb.HealthCheckResponse_ServingStatus) {
+    t.Helper()
+    deadline := time.Now().Add(10 * time.Second)
+    for {
+        resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+        if err == nil && resp.Status == want {
+            return
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("health of %q = %v, %v; want %v", service, resp.GetStatus(), err, want)
+        }
+        time.Sleep(50 * time.Millisecond)
+    }
+}
+
+func TestReadinessFollowsWritableDirs(t *testing.T) {
+    tests := []struct {
+        name string
+        env  func(t *testing.T, dir string)
+    }{
+        {"log dir", func(t *testing.T, dir string) { t.Setenv("VALIDATION_LOG_DIR", dir) }},
+        {"lock dir", func(t *testing.T, dir string) {
+            t.Setenv("LOCK_FILES", "true")
+            t.Setenv("ALLOWED_ROOTS", dir)
+        }},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            dir := filepath.Join(t.TempDir(), "dir")
+            if err := os.Mkdir(dir, 0o755); err != nil {
+                t.Fatal(err)
+            }
+            tt.env(t, dir)
+            t.Setenv("READINESS_INTERVAL_SECONDS", "1")
+            ts := newTestServer(t, serverConfig{})
+            health := healthpb.NewHealthClient(ts.conn)
+            waitForHealth(t, health, readinessService, healthpb.HealthCheckResponse_SERVING)
+
+            // A regular file where the directory should be cannot be
+            // written into, even by root. The server recreates a missing
+            // log dir, so retry until the file wins the race.
+            for {
+                if err := os.RemoveAll(dir); err != nil {
+                    t.Fatal(err)
+                }
+                if err := os.WriteFile(dir, nil, 0o644); err == nil {
+                    break
+                }
+            }
+            waitForHealth(t, health, readinessService, healthpb.HealthCheckResponse_NOT_SERVING)
+            if _, err := ts.parts.tools.readiness.result(); err == nil {
+                t.Errorf("readiness result has no error while the %s is unwritable", tt.name)
+            }
+            // Liveness is unaffected
+            waitForHealth(t, health, "", healthpb.HealthCheckResponse_SERVING)
+
+            if err := os.Remove(dir); err != nil {
+                t.Fatal(err)
+            }
+            if err := os.Mkdir(dir, 0o755); err != nil {
+                t.Fatal(err)
+            }
+            waitForHealth(t, health, readinessService, healthpb.HealthCheckResponse_SERVING)
+        })
+    }
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+    "context"
+    "log/slog"
+
+    "google.golang.org/grpc/metadata"
+)
+
+// requestIDHeader carries the request id in gRPC metadata (and as
+// X-Request-ID on the HTTP gateway)
+const requestIDHeader = "x-request-id"
+
+// maxRequestIDLength bounds a client-supplied id; longer ones are replaced
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// RequestIDFromContext returns the id of the RPC ctx belongs to, or "" outside one
+func RequestIDFromContext(ctx context.Context) string {
+    id, _ := ctx.Value(requestIDKey{}).(string)
+    return id
+}
+
+// withRequestID attaches the caller's id to ctx, generating one when the
+// caller sent none or an unusable one
+func withRequestID(ctx context.Context, id string) (context.Context, string) {
+    if !validRequestID(id) {
+        id = newJobID()
+    }
+    return context.WithValue(ctx, requestIDKey{}, id), id
+}
+
+// incomingRequestID reads the request id from the incoming gRPC metadata
+func incomingRequestID(ctx context.Context) string {
+    md, _ := metadata.FromIncomingContext(ctx)
+    if values := md.Get(requestIDHeader); len(values) > 0 {
+        return values[0]
+    }
+    return ""
+}
+
+// validRequestID accepts short ids of printable ASCII so they are safe to log
+func validRequestID(id string) bool {
+    if id == "" || len(id) > maxRequestIDLength {
+        return false
+    }
+    for i := 0; i < len(id); i++ {
+        if id[i] < 0x21 || id[i] > 0x7e {
+            return false
+        }
+    }
+    return true
+}
+
+// logCtx logs msg with slog key-value args at info level, adding ctx's request id
+func logCtx(ctx context.Context, msg string, args ...interface{}) {
+    if id := RequestIDFromContext(ctx); id != "" {
+        args = append(args, "request_id", id)
+    }
+    slog.InfoContext(ctx, msg, args...)
+}
+
+// debugCtx is logCtx at debug level
+func debugCtx(ctx context.Context, msg string, args ...interface{}) {
+    if id := RequestIDFromContext(ctx); id != "" {
+        args = append(args, "request_id", id)
+    }
+    slog.DebugContext(ctx, msg, args...)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+    "context"
+    "io"
+    "strings"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/metadata"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestRequestIDEchoedInTrailer(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    req := &pb.LockRequest{ProjectPath: t.TempDir()}
+
+    tests := []struct {
+        name     string
+        sent     string
+        generate bool
+    }{
+        {name: "client supplied", sent: "req-123"},
+        {name: "missing", generate: true},
+        {name: "too long", sent: strings.Repeat("x", maxRequestIDLength+1), generate: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            ctx := context.Background()
+            if tt.sent != "" {
+                ctx = metadata.AppendToOutgoingContext(ctx, requestIDHeader, tt.sent)
+            }
+            var trailer metadata.MD
+            if _, err := ts.client.CheckLock(ctx, req, grpc.Trailer(&trailer)); err != nil {
+                t.Fatalf("CheckLock: %v", err)
+            }
+            got := trailer.Get(requestIDHeader)
+            if len(got) != 1 {
+                t.Fatalf("trailer %s = %q, want one id", requestIDHeader, got)
+            }
+            if tt.generate && (got[0] == "" || got[0] == tt.sent) {
+                t.Errorf("request id = %q, want a generated one", got[0])
+            }
+            if !tt.generate && got[0] != tt.sent {
+                t.Errorf("request id = %q, want %q", got[0], tt.sent)
+            }
+        })
+    }
+}
+
+func TestRequestIDEchoedInStreamTrailer(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+    ctx := metadata.AppendToOutgoingContext(context.Background(), requestIDHeader, "stream-456")
+
+    stream, err := ts.client.StreamValidation(ctx, &pb.ValidationRequest{ProjectRoot: root})
+    if err != nil {
+        t.Fatalf("StreamValidation: %v", err)
+    }
+    for {
+        if _, err := stream.Recv(); err == io.EOF {
+            break
+        } else if err != nil {
+            t.Fatalf("Recv: %v", err)
+        }
+    }
+    if got := stream.Trailer().Get(requestIDHeader); len(got) != 1 || got[0] != "stream-456" {
+        t.Errorf("trailer %s = %q, want stream-456", requestIDHeader, got)
+    }
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+    "container/list"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "fmt"
+    "io"
+    "io/fs"
+    "path/filepath"
+    "sync"
+    "time"
+
+    "google.golang.org/protobuf/proto"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Result cache
+//
+// With use_cache, ValidateProject answers a repeated request from memory
+// when the project has not changed since an earlier run. The key hashes the
+// request itself (minus use_cache) and a snapshot of the project tree: the
+// path, size and mtime of every file outside fingerprintSkipDirs, so
+// touching any source or config file busts the cache. Dependency
+// directories are skipped; lockfiles stand in for them. Entries expire
+// after RESULT_CACHE_TTL_SECONDS (600) and only the RESULT_CACHE_MAX_ENTRIES
+// (256, 0 disables) most recently used are kept. Projects with more than
+// RESULT_CACHE_MAX_FILES (50000) files are never cached, and neither are
+// runs that were cut short (aborted, cancelled, timed out) or that
+// validate a git range, since refs can move without touching the tree.
+
+// fingerprintSkipDirs hold dependencies, caches and build output that
+// validators themselves write to
+var fingerprintSkipDirs = map[string]bool{
+    ".mypy_cache":   true,
+    ".next":         true,
+    ".pytest_cache": true,
+    ".ruff_cache":   true,
+    "build":         true,
+    "coverage":      true,
+    "dist":          true,
+}
+
+// errTooManyFiles stops fingerprinting a project too large to cache
+var errTooManyFiles = errors.New("too many files to fingerprint")
+
+// resultCache is an LRU of validation responses with a TTL
+type resultCache struct {
+    maxEntries int
+    maxFiles   int
+    ttl        time.Duration
+
+    mu      sync.Mutex
+    order   *list.List // most recently used first; values are *cacheEntry
+    entries map[string]*list.Element
+}
+
+type cacheEntry struct {
+    key      string
+    resp     *pb.ValidationResponse
+    storedAt time.Time
+}
+
+// loadResultCache reads the RESULT_CACHE_* settings; it returns nil when
+// caching is disabled
+func loadResultCache() *resultCache {
+    maxEntries := envInt("RESULT_CACHE_MAX_ENTRIES", 256)
+    if maxEntries <= 0 {
+        return nil
+    }
+    return &resultCache{
+        maxEntries: maxEntries,
+        maxFiles:   envInt("RESULT_CACHE_MAX_FILES", 50000),
+        ttl:        time.Duration(envInt("RESULT_CACHE_TTL_SECONDS", 600)) * time.Second,
+        order:      list.New(),
+        entries:    make(map[string]*list.Element),
+    }
+}
+
+// key derives the cache key for req, or "" when the run cannot be cached
+func (c *resultCache) key(req *pb.ValidationRequest) string {
+    if c == nil || !req.UseCache || req.GitBaseRef != "" || req.GitHeadRef != "" {
+        return ""
+    }
+
+    keyed := proto.Clone(req).(*pb.ValidationRequest)
+    keyed.UseCache = false
+    encoded, err := proto.MarshalOptions{Deterministic: true}.Marshal(keyed)
+    if err != nil {
+        return ""
+    }
+
+    h := sha256.New()
+    h.Write(encoded)
+    if err := c.fingerprint(h, req.ProjectRoot); err != nil {
+        return ""
+    }
+    return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprint hashes the path, size and mtime of the project's files
+func (c *resultCache) fingerprint(h io.Writer, root string) error {
+    files := 0
+    return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+        if err != nil {
+            return err
+        }
+        if entry.IsDir() {
+            if path != root && (markerSkipDirs[entry.Name()] || fingerprintSkipDirs[entry.Name()]) {
+                return filepath.SkipDir
+            }
+            return nil
+        }
+        if files++; files > c.maxFiles {
+            return errTooManyFiles
+        }
+        info, err := entry.Info()
+        if err != nil {
+            return err
+        }
+        rel, _ := filepath.Rel(root, path)
+        fmt.Fprintf(h, "%q %d %d\n", rel, info.Size(), info.ModTime().UnixNano())
+        return nil
+    })
+}
+
+// get returns a copy of the cached response for key, if still fresh
+func (c *resultCache) get(key string) (*pb.ValidationResponse, bool) {
+    if key == "" {
+        return nil, false
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    elem, ok := c.entries[key]
+    if !ok {
+        return nil, false
+    }
+    entry := elem.Value.(*cacheEntry)
+    if time.Since(entry.storedAt) > c.ttl {
+        c.order.Remove(elem)
+        delete(c.entries, key)
+        return nil, false
+    }
+    c.order.MoveToFront(elem)
+    return proto.Clone(entry.resp).(*pb.ValidationResponse), true
+}
+
+// put stores a copy of resp under key, evicting the least recently used
+// entries beyond maxEntries. Runs cut short are not stored.
+func (c *resultCache) put(key string, resp *pb.ValidationResponse) {
+    if key == "" || !cacheable(resp) {
+        return
+    }
+    entry := &cacheEntry{key: key, resp: proto.Clone(resp).(*pb.ValidationResponse), storedAt: time.Now()}
+
+    c.mu.Lock()
+    defer c.mu.Unlock()
+
+    if elem, ok := c.entries[key]; ok {
+        elem.Value = entry
+        c.order.MoveToFront(elem)
+        return
+    }
+    c.entries[key] = c.order.PushFront(entry)
+    for c.order.Len() > c.maxEntries {
+        oldest := c.order.Back()
+        c.order.Remove(oldest)
+        delete(c.entries, oldest.Value.(*cacheEntry).key)
+    }
+}
+
+// markUnchanged flags the results of a cached response as skipped with
+// SKIP_REASON_UNCHANGED, since nothing was executed for them. Unlike other
+// skips they keep the earlier run's verdict, so a cached failure still
+// fails. Results that were already skipped keep their original reason.
+func markUnchanged(resp *pb.ValidationResponse) {
+    for _, result := range resp.Results {
+        if !result.Skipped {
+            result.Skipped = true
+            result.SkipReason = pb.SkipReason_SKIP_REASON_UNCHANGED
+        }
+    }
+}
+
+// cacheable reports whether every validator ran to a verdict of its own
+func cacheable(resp *pb.ValidationResponse) bool {
+    for _, result := range resp.Results {
+        switch result.FailureReason {
+        case pb.FailureReason_FAILURE_REASON_ABORTED,
+            pb.FailureReason_FAILURE_REASON_CANCELLED,
+            pb.FailureReason_FAILURE_REASON_TIMEOUT,
+            pb.FailureReason_FAILURE_REASON_RUNAWAY_OUTPUT,
+            pb.FailureReason_FAILURE_REASON_SIGNALED:
+            return false
+        }
+    }
+    return true
+}
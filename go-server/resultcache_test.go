@@ -0,0 +1,76 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// countRuns reads how many times a recipe appended to counter
+func countRuns(t *testing.T, counter string) int {
+    t.Helper()
+    data, err := os.ReadFile(counter)
+    if err != nil && !os.IsNotExist(err) {
+        t.Fatal(err)
+    }
+    return strings.Count(string(data), "\n")
+}
+
+func TestResultCacheServesUnchangedProject(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    counter := filepath.Join(t.TempDir(), "runs")
+    root := makeProject(t, map[string]string{"test": "echo ran >> " + counter + "; exit 1"})
+    req := &pb.ValidationRequest{ProjectRoot: root, UseCache: true}
+    ctx := context.Background()
+
+    first, err := ts.client.ValidateProject(ctx, req)
+    if err != nil {
+        t.Fatalf("first ValidateProject: %v", err)
+    }
+    if first.CacheHit || resultsByName(first)["test"].GetSkipped() {
+        t.Fatalf("first run = %v, want an executed run", first)
+    }
+
+    second, err := ts.client.ValidateProject(ctx, req)
+    if err != nil {
+        t.Fatalf("second ValidateProject: %v", err)
+    }
+    if !second.CacheHit {
+        t.Fatal("second identical call was not a cache hit")
+    }
+    if runs := countRuns(t, counter); runs != 1 {
+        t.Errorf("test target ran %d times, want 1", runs)
+    }
+    result := resultsByName(second)["test"]
+    if result == nil || !result.Skipped || result.SkipReason != pb.SkipReason_SKIP_REASON_UNCHANGED {
+        t.Errorf("cached result = %v, want skipped with SKIP_REASON_UNCHANGED", result)
+    }
+    if result.GetSuccess() || second.Success {
+        t.Error("a cached failure was reported as success")
+    }
+
+    // Touching a source file busts the cache
+    source := filepath.Join(root, "main.c")
+    if err := os.WriteFile(source, []byte("int main;\n"), 0o644); err != nil {
+        t.Fatal(err)
+    }
+    later := time.Now().Add(time.Second)
+    if err := os.Chtimes(source, later, later); err != nil {
+        t.Fatal(err)
+    }
+    third, err := ts.client.ValidateProject(ctx, req)
+    if err != nil {
+        t.Fatalf("third ValidateProject: %v", err)
+    }
+    if third.CacheHit || resultsByName(third)["test"].GetSkipped() {
+        t.Errorf("run after a change = %v, want a fresh run", third)
+    }
+    if runs := countRuns(t, counter); runs != 2 {
+        t.Errorf("test target ran %d times, want 2", runs)
+    }
+}
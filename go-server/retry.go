@@ -0,0 +1,118 @@
+package main
+
+import (
+    "context"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// defaultTransientExitCodes mark failures worth retrying: 75 is EX_TEMPFAIL,
+// 137 is SIGKILL (typically the OOM killer)
+var defaultTransientExitCodes = []int{75, 137}
+
+// defaultTransientPatterns are output fragments (matched case-insensitively)
+// that indicate network trouble or lock contention rather than a real defect
+var defaultTransientPatterns = []string{
+    "ECONNRESET",
+    "ECONNREFUSED",
+    "ETIMEDOUT",
+    "EAI_AGAIN",
+    "connection reset by peer",
+    "connection refused",
+    "could not resolve host",
+    "temporary failure in name resolution",
+    "resource temporarily unavailable",
+    "blocking waiting for file lock",
+    "unable to acquire lock",
+    "out of memory",
+}
+
+// retryClassifier decides whether a failed attempt is transient and worth
+// re-running, or permanent (an assertion failure, a lint error) so retrying
+// would only repeat it
+type retryClassifier struct {
+    exitCodes map[int]bool
+    patterns  []string
+
+    // baseBackoff is the wait before the first retry, doubling for each
+    // further one up to maxBackoff
+    baseBackoff time.Duration
+    maxBackoff  time.Duration
+}
+
+// loadRetryClassifier reads RETRY_TRANSIENT_EXIT_CODES and
+// RETRY_TRANSIENT_PATTERNS (comma-separated), falling back to the
+// defaults, and the backoff from RETRY_BACKOFF_MS (500) and
+// RETRY_BACKOFF_MAX_MS (10000)
+func loadRetryClassifier() *retryClassifier {
+    c := &retryClassifier{
+        exitCodes:   make(map[int]bool),
+        baseBackoff: time.Duration(envInt("RETRY_BACKOFF_MS", 500)) * time.Millisecond,
+        maxBackoff:  time.Duration(envInt("RETRY_BACKOFF_MAX_MS", 10000)) * time.Millisecond,
+    }
+
+    codes := defaultTransientExitCodes
+    if v := os.Getenv("RETRY_TRANSIENT_EXIT_CODES"); v != "" {
+        codes = nil
+        for _, field := range strings.Split(v, ",") {
+            if n, err := strconv.Atoi(strings.TrimSpace(field)); err == nil {
+                codes = append(codes, n)
+            }
+        }
+    }
+    for _, code := range codes {
+        c.exitCodes[code] = true
+    }
+
+    patterns := defaultTransientPatterns
+    if v := os.Getenv("RETRY_TRANSIENT_PATTERNS"); v != "" {
+        patterns = strings.Split(v, ",")
+    }
+    for _, p := range patterns {
+        if p = strings.TrimSpace(p); p != "" {
+            c.patterns = append(c.patterns, strings.ToLower(p))
+        }
+    }
+
+    return c
+}
+
+// transient reports whether a failure with this exit code and output should be retried
+func (c *retryClassifier) transient(exitCode int, output string) bool {
+    if c.exitCodes[exitCode] {
+        return true
+    }
+    lower := strings.ToLower(output)
+    for _, p := range c.patterns {
+        if strings.Contains(lower, p) {
+            return true
+        }
+    }
+    return false
+}
+
+// backoff returns the wait before retrying after the given failed attempt
+func (c *retryClassifier) backoff(attempt int) time.Duration {
+    wait := c.baseBackoff
+    for i := 1; i < attempt && wait < c.maxBackoff; i++ {
+        wait *= 2
+    }
+    return min(wait, c.maxBackoff)
+}
+
+// sleepContext waits for d, returning false if ctx ends first
+func sleepContext(ctx context.Context, d time.Duration) bool {
+    if d <= 0 {
+        return ctx.Err() == nil
+    }
+    timer := time.NewTimer(d)
+    defer timer.Stop()
+    select {
+    case <-timer.C:
+        return true
+    case <-ctx.Done():
+        return false
+    }
+}
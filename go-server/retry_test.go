@@ -0,0 +1,88 @@
+package main
+
+import (
+    "context"
+    "path/filepath"
+    "testing"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestRetryFlakyValidator(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("RETRY_BACKOFF_MS", "10")
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+
+    // flaky fails with status the first time it runs, then passes
+    flaky := func(status string) string {
+        marker := filepath.Join(t.TempDir(), "ran")
+        return "sh -c 'if [ -f " + marker + " ]; then echo passed; else touch " + marker + "; exit " + status + "; fi'"
+    }
+    tests := []struct {
+        name      string
+        command   string
+        retries   int32
+        exitCodes []int32
+        success   bool
+        attempts  int32
+        transient bool
+    }{
+        {name: "transient failure then success", command: flaky("75"), retries: 2, success: true, attempts: 2},
+        {name: "assertion failure is not retried", command: flaky("1"), retries: 2, attempts: 1},
+        {name: "requested exit code is retried", command: flaky("1"), retries: 2, exitCodes: []int32{1}, success: true, attempts: 2},
+        {name: "no retries requested", command: flaky("75"), attempts: 1, transient: true},
+        {name: "retries exhausted", command: "sh -c 'exit 75'", retries: 2, attempts: 3, transient: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+                ProjectRoot:      root,
+                OverrideCommands: map[string]string{"test": tt.command},
+                Retries:          tt.retries,
+                RetryExitCodes:   tt.exitCodes,
+            })
+            if err != nil {
+                t.Fatalf("ValidateProject: %v", err)
+            }
+            result := resultsByName(resp)["test"]
+            if result.GetSuccess() != tt.success || result.GetAttemptCount() != tt.attempts || result.GetTransient() != tt.transient {
+                t.Errorf("success=%v attempts=%d transient=%v, want %v, %d and %v", result.GetSuccess(), result.GetAttemptCount(), result.GetTransient(), tt.success, tt.attempts, tt.transient)
+            }
+            if resp.Success != tt.success {
+                t.Errorf("overall success = %v, want %v", resp.Success, tt.success)
+            }
+        })
+    }
+}
+
+func TestRetryClassifier(t *testing.T) {
+    t.Setenv("RETRY_TRANSIENT_EXIT_CODES", "")
+    t.Setenv("RETRY_TRANSIENT_PATTERNS", "")
+    t.Setenv("RETRY_BACKOFF_MS", "100")
+    t.Setenv("RETRY_BACKOFF_MAX_MS", "300")
+    c := loadRetryClassifier()
+
+    for _, tt := range []struct {
+        exitCode int
+        output   string
+        want     bool
+    }{
+        {75, "", true},
+        {137, "", true},
+        {1, "expected 2, got 3", false},
+        {1, "npm ERR! network ECONNRESET", true},
+        {1, "Could Not Resolve Host: github.com", true},
+    } {
+        if got := c.transient(tt.exitCode, tt.output); got != tt.want {
+            t.Errorf("transient(%d, %q) = %v, want %v", tt.exitCode, tt.output, got, tt.want)
+        }
+    }
+
+    for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 10: 300 * time.Millisecond} {
+        if got := c.backoff(attempt); got != want {
+            t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+        }
+    }
+}
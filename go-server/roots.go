@@ -0,0 +1,93 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strings"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+)
+
+// loadAllowedRoots reads ALLOWED_ROOTS, a colon-separated list of
+// directories that project roots must live under. Unset allows any
+// absolute path.
+func loadAllowedRoots() []string {
+    var roots []string
+    for _, root := range filepath.SplitList(os.Getenv("ALLOWED_ROOTS")) {
+        if root = strings.TrimSpace(root); root != "" {
+            roots = append(roots, resolveRoot(filepath.Clean(root)))
+        }
+    }
+    return roots
+}
+
+// checkRoot validates a project root or lock path: it must be absolute, must
+// not contain "..", and must lie under one of the allowed roots if any are
+// configured. Symlinks are resolved first so a link cannot escape the
+// allowlist.
+func (l requestLimits) checkRoot(field, value string) error {
+    if err := l.checkPath(field, value); err != nil {
+        return err
+    }
+    if value == "" {
+        return status.Errorf(codes.InvalidArgument, "%s is required", field)
+    }
+    if !filepath.IsAbs(value) {
+        return status.Errorf(codes.InvalidArgument, "%s %q must be an absolute path", field, value)
+    }
+    for _, elem := range strings.Split(filepath.ToSlash(value), "/") {
+        if elem == ".." {
+            return status.Errorf(codes.InvalidArgument, "%s %q must not contain \"..\"", field, value)
+        }
+    }
+    if len(l.allowedRoots) == 0 {
+        return nil
+    }
+
+    resolved := resolveRoot(filepath.Clean(value))
+    for _, allowed := range l.allowedRoots {
+        if withinRoot(resolved, allowed) {
+            return nil
+        }
+    }
+    return status.Errorf(codes.InvalidArgument, "%s %q is outside the allowed roots", field, value)
+}
+
+// resolveRoot follows symlinks in path, leaving it unchanged if it does not
+// exist (yet)
+func resolveRoot(path string) string {
+    if resolved, err := filepath.EvalSymlinks(path); err == nil {
+        return resolved
+    }
+    return path
+}
+
+// withinRoot reports whether path is root or a descendant of it
+func withinRoot(path, root string) bool {
+    rel, err := filepath.Rel(root, path)
+    return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// checkWorkingDir verifies that working_dir, relative to the project root,
+// is a directory that stays inside the root once symlinks are resolved
+func checkWorkingDir(projectRoot, workingDir string) error {
+    if workingDir == "" {
+        return nil
+    }
+    root, err := filepath.EvalSymlinks(projectRoot)
+    if err != nil {
+        return status.Errorf(codes.InvalidArgument, "project_root %q: %v", projectRoot, err)
+    }
+    dir, err := filepath.EvalSymlinks(filepath.Join(projectRoot, workingDir))
+    if err != nil {
+        return status.Errorf(codes.InvalidArgument, "working_dir %q: %v", workingDir, err)
+    }
+    if !withinRoot(dir, root) {
+        return status.Errorf(codes.InvalidArgument, "working_dir %q resolves outside project_root", workingDir)
+    }
+    if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+        return status.Errorf(codes.InvalidArgument, "working_dir %q is not a directory", workingDir)
+    }
+    return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestCheckRoot(t *testing.T) {
+    allowed := t.TempDir()
+    outside := t.TempDir()
+    if err := os.Mkdir(filepath.Join(allowed, "project"), 0o755); err != nil {
+        t.Fatal(err)
+    }
+    // A link inside the allowed root pointing out of it
+    if err := os.Symlink(outside, filepath.Join(allowed, "escape")); err != nil {
+        t.Fatal(err)
+    }
+    t.Setenv("ALLOWED_ROOTS", allowed+string(filepath.ListSeparator)+"/nonexistent-root")
+    limits := loadRequestLimits()
+
+    tests := []struct {
+        name string
+        path string
+        ok   bool
+    }{
+        {"allowed root itself", allowed, true},
+        {"project under allowed root", filepath.Join(allowed, "project"), true},
+        {"not yet created under allowed root", filepath.Join(allowed, "new"), true},
+        {"empty", "", false},
+        {"relative", "project", false},
+        {"traversal out of allowed root", allowed + "/project/../../etc", false},
+        {"traversal that stays inside", allowed + "/project/../project", false},
+        {"outside allowed roots", outside, false},
+        {"sibling sharing a prefix", allowed + "-other", false},
+        {"symlink escaping allowed root", filepath.Join(allowed, "escape"), false},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            err := limits.checkRoot("project_root", tt.path)
+            if tt.ok && err != nil {
+                t.Errorf("checkRoot(%q) = %v, want nil", tt.path, err)
+            }
+            if !tt.ok && status.Code(err) != codes.InvalidArgument {
+                t.Errorf("checkRoot(%q) = %v, want InvalidArgument", tt.path, err)
+            }
+        })
+    }
+}
+
+func TestRPCsRejectDisallowedRoots(t *testing.T) {
+    allowed := t.TempDir()
+    t.Setenv("ALLOWED_ROOTS", allowed)
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    inside := filepath.Join(allowed, "project")
+    if err := os.Mkdir(inside, 0o755); err != nil {
+        t.Fatal(err)
+    }
+
+    for _, root := range []string{t.TempDir(), inside + "/../../etc", "relative"} {
+        if _, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root}); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("ValidateProject(%q) = %v, want InvalidArgument", root, err)
+        }
+        if _, err := ts.client.GetProjectMetadata(ctx, &pb.ValidationRequest{ProjectRoot: root}); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("GetProjectMetadata(%q) = %v, want InvalidArgument", root, err)
+        }
+        lock := &pb.LockRequest{ProjectPath: root}
+        if _, err := ts.client.AcquireLock(ctx, lock); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("AcquireLock(%q) = %v, want InvalidArgument", root, err)
+        }
+        if _, err := ts.client.CheckLock(ctx, lock); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("CheckLock(%q) = %v, want InvalidArgument", root, err)
+        }
+        if _, err := ts.client.ReleaseLock(ctx, lock); status.Code(err) != codes.InvalidArgument {
+            t.Errorf("ReleaseLock(%q) = %v, want InvalidArgument", root, err)
+        }
+    }
+
+    if _, err := ts.client.GetProjectMetadata(ctx, &pb.ValidationRequest{ProjectRoot: inside}); err != nil {
+        t.Errorf("GetProjectMetadata under the allowed root: %v", err)
+    }
+    if _, err := ts.client.CheckLock(ctx, &pb.LockRequest{ProjectPath: inside}); err != nil {
+        t.Errorf("CheckLock under the allowed root: %v", err)
+    }
+}
+
+func TestWorkingDirOverride(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+    if err := os.MkdirAll(filepath.Join(root, "web", "src"), 0o755); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.WriteFile(filepath.Join(root, "notes.txt"), nil, 0o644); err != nil {
+        t.Fatal(err)
+    }
+    if err := os.Symlink(t.TempDir(), filepath.Join(root, "escape")); err != nil {
+        t.Fatal(err)
+    }
+    pwd := map[string]string{"test": "sh -c pwd"}
+
+    for workingDir, want := range map[string]string{"": root, "web": filepath.Join(root, "web"), "web/src": filepath.Join(root, "web/src")} {
+        resp, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root, WorkingDir: workingDir, OverrideCommands: pwd})
+        if err != nil {
+            t.Fatalf("working_dir %q: %v", workingDir, err)
+        }
+        // Detection still happens at the project root
+        if resp.Metadata.GetProjectType() != "make" {
+            t.Errorf("working_dir %q: project type %q, want make", workingDir, resp.Metadata.GetProjectType())
+        }
+        result := resultsByName(resp)["test"]
+        if got := strings.TrimSpace(result.GetOutput()); got != want || result.GetWorkDir() != want {
+            t.Errorf("working_dir %q: ran in %q (work_dir %q), want %q", workingDir, got, result.GetWorkDir(), want)
+        }
+    }
+
+    for _, workingDir := range []string{"../outside", "escape", "notes.txt", "missing"} {
+        _, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{ProjectRoot: root, WorkingDir: workingDir, OverrideCommands: pwd})
+        if status.Code(err) != codes.InvalidArgument {
+            t.Errorf("working_dir %q = %v, want InvalidArgument", workingDir, err)
+        }
+    }
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+    "bytes"
+    "errors"
+    "io"
+    "time"
+)
+
+// Runaway output guard
+//
+// A test stuck in a loop can print gigabytes before its timeout fires. The
+// guard counts output lines in fixed windows of RUNAWAY_OUTPUT_WINDOW_MS and
+// kills the validator once a window holds more than
+// RUNAWAY_OUTPUT_LINES_PER_SEC times its length, recording
+// FAILURE_REASON_RUNAWAY_OUTPUT. Short bursts below a full window's budget
+// are tolerated. Chatty tools opt out per request via chatty_validators;
+// setting the rate to 0 disables the guard server-wide.
+
+// errRunawayOutput is the cancellation cause recorded when the guard trips
+var errRunawayOutput = errors.New("killed for runaway output")
+
+// runawayPolicy holds the server-wide output rate threshold
+type runawayPolicy struct {
+    linesPerSec int
+    window      time.Duration
+}
+
+// loadRunawayPolicy reads the guard settings from the environment
+func loadRunawayPolicy() runawayPolicy {
+    return runawayPolicy{
+        linesPerSec: envInt("RUNAWAY_OUTPUT_LINES_PER_SEC", 5000),
+        window:      time.Duration(envInt("RUNAWAY_OUTPUT_WINDOW_MS", 3000)) * time.Millisecond,
+    }
+}
+
+// guard returns a writer that observes spec's output and calls trip once the
+// threshold is exceeded, or nil when the guard does not apply
+func (p runawayPolicy) guard(spec *validatorSpec, trip func()) io.Writer {
+    if p.linesPerSec <= 0 || p.window <= 0 || spec.chatty {
+        return nil
+    }
+    return &runawayGuard{
+        limit:  int(float64(p.linesPerSec) * p.window.Seconds()),
+        window: p.window,
+        start:  time.Now(),
+        trip:   trip,
+    }
+}
+
+// runawayGuard counts lines per window. Writes must not be concurrent,
+// which holds when stdout and stderr share one writer.
+type runawayGuard struct {
+    limit   int
+    window  time.Duration
+    start   time.Time
+    lines   int
+    tripped bool
+    trip    func()
+}
+
+func (g *runawayGuard) Write(p []byte) (int, error) {
+    if now := time.Now(); now.Sub(g.start) >= g.window {
+        g.start = now
+        g.lines = 0
+    }
+    g.lines += bytes.Count(p, []byte("\n"))
+    if !g.tripped && g.lines > g.limit {
+        g.tripped = true
+        g.trip()
+    }
+    return len(p), nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+    "context"
+    "os"
+    "os/exec"
+    "runtime"
+    "strings"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// buildRunManifest describes how a run executes in enough detail to repeat
+// it on another machine: the resolved validators (env redacted), the
+// version each validator's program reports, and the host it ran on. A dry
+// run leaves the versions out rather than run the programs to ask.
+func (s *CCToolsServer) buildRunManifest(ctx context.Context, req *pb.ValidationRequest, metadata *pb.ProjectMetadata, specs []*validatorSpec) *pb.RunManifest {
+    hostname, _ := os.Hostname()
+    manifest := &pb.RunManifest{
+        ProjectRoot:       req.ProjectRoot,
+        ProjectType:       metadata.ProjectType,
+        FilePaths:         req.FilePaths,
+        StartedAt:         time.Now().Unix(),
+        ToolchainVersions: make(map[string]string),
+        Host: &pb.HostInfo{
+            Hostname:  hostname,
+            Os:        runtime.GOOS,
+            Arch:      runtime.GOARCH,
+            NumCpu:    int32(runtime.NumCPU()),
+            GoVersion: runtime.Version(),
+        },
+    }
+
+    for _, spec := range specs {
+        manifest.Validators = append(manifest.Validators, spec.definition(metadata.ProjectType))
+
+        // Asking for the version runs the program, so it is held to the
+        // same allowlist as the validator itself
+        program := spec.program()
+        if _, seen := manifest.ToolchainVersions[program]; seen || program == "" || req.DryRun || s.allowedCommands.check(spec) != nil {
+            continue
+        }
+        manifest.ToolchainVersions[program] = programVersion(ctx, spec, program)
+    }
+    return manifest
+}
+
+// program returns the executable the validator's own command runs,
+// ignoring any login shell or command prefix around it
+func (v *validatorSpec) program() string {
+    if v.args != nil {
+        return v.args[0]
+    }
+    if fields, _ := shellSplit(v.command); len(fields) > 0 {
+        return fields[0]
+    }
+    return ""
+}
+
+// programVersion returns the first line of `program --version` as run for
+// the validator, or an empty string when the program does not answer
+func programVersion(ctx context.Context, spec *validatorSpec, program string) string {
+    ctx, cancel := context.WithTimeout(ctx, toolchainProbeTimeout)
+    defer cancel()
+
+    argv := []string{program, "--version"}
+    if spec.loginShell {
+        argv = []string{"bash", "-lc", shellJoin(argv)}
+    }
+    cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+    cmd.Dir = spec.workDir
+    cmd.Env = spec.environ()
+    out, err := cmd.Output()
+    if err != nil {
+        return ""
+    }
+    line, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\n")
+    return line
+}
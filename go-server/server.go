@@ -2,13 +2,25 @@ package main
 
 import (
     "context"
+    "errors"
     "fmt"
+    "io"
+    "io/fs"
+    "log/slog"
     "os"
     "os/exec"
+    "runtime"
     "strings"
     "sync"
+    "sync/atomic"
+    "syscall"
     "time"
 
+    "google.golang.org/grpc/codes"
+    health "google.golang.org/grpc/health"
+    "google.golang.org/grpc/status"
+    "google.golang.org/protobuf/proto"
+
     pb "github.com/devflow/cc-tools-server/proto"
 )
 
@@ -16,32 +28,234 @@ import (
 type LockManager struct {
     locks map[string]*LockInfo
     mutex sync.RWMutex
+
+    // useLockFiles makes .devflow.lock in the project root the source of
+    // truth so external tools and the server honor the same lock
+    useLockFiles bool
+
+    // released is closed when a lock is removed (see nextRelease)
+    released chan struct{}
+}
+
+// newLockManager creates the lock manager and starts sweeping expired locks
+func newLockManager() *LockManager {
+    lm := &LockManager{
+        locks:        make(map[string]*LockInfo),
+        useLockFiles: envBool("LOCK_FILES", false),
+    }
+    lm.sweepExpired(time.Duration(envInt("LOCK_SWEEP_INTERVAL_SECONDS", int(defaultLockSweepInterval.Seconds()))) * time.Second)
+    return lm
 }
 
 type LockInfo struct {
     ProcessID   int32
     AcquiredAt  int64
     ProjectPath string
+    Owner       string
+    Namespace   string
+    TTLSeconds  int64 // 0 = never expires
 }
 
 // CCToolsServer implements the gRPC service
 type CCToolsServer struct {
     pb.UnimplementedCCToolsIntegrationServer
     lockManager *LockManager
+
+    // maxBatchConcurrency caps the per-call parallelism of the batch RPCs
+    maxBatchConcurrency int
+
+    // history records recent validator execution times per project
+    history *validationHistory
+
+    // markerDepth is the default number of directory levels searched for
+    // marker files; markerDepths overrides it per project type
+    markerDepth  int
+    markerDepths map[string]int
+
+    // jobs tracks running validations so operators can cancel them
+    jobs *jobRegistry
+
+    // adminToken guards the admin RPCs; empty disables them
+    adminToken string
+
+    // retry classifies failed attempts as transient or permanent
+    retry *retryClassifier
+
+    // contention tracks how long clients wait for held locks
+    contention *lockContention
+
+    // minFreeDiskMB is the free space required under the project root
+    // before validators run; 0 disables the check
+    minFreeDiskMB int64
+
+    // projectEnv holds default validator environment variables per project type
+    projectEnv map[string]map[string]string
+
+    // shellPool keeps pre-forked login shells for login-shell validators; nil when disabled
+    shellPool *warmShellPool
+
+    // shedder rejects validations while the server is saturated
+    shedder loadShedder
+
+    // quotas cap concurrent validations per caller identity
+    quotas *identityQuotas
+
+    // Default StreamValidation flush policy, overridable per request
+    streamFlushLines    int
+    streamFlushInterval time.Duration
+
+    // progressInterval is the default StreamValidation progress event period
+    progressInterval time.Duration
+
+    // streamClientBuffer bounds the events queued for a slow StreamValidation client
+    streamClientBuffer int
+
+    // validationLogs persists streamed output for GetValidationLog; nil when disabled
+    validationLogs *validationLogs
+
+    // outputPrefixFormat is the default prefix for prefix_output (OUTPUT_PREFIX_FORMAT)
+    outputPrefixFormat string
+
+    // emptyRunDefault decides the outcome of runs where no validator executed
+    emptyRunDefault pb.EmptyRunPolicy
+
+    // defaultNamespace applies to lock requests without a namespace or identity
+    defaultNamespace string
+
+    // artifactLimits bounds the files collected by artifact_globs
+    artifactLimits artifactLimits
+
+    // disabledDetectors are project types this node never detects
+    disabledDetectors map[string]bool
+
+    // lockOwnerSources is the fallback chain for locks acquired without an owner
+    lockOwnerSources []string
+
+    // runaway kills validators whose output rate suggests a runaway loop
+    runaway runawayPolicy
+
+    // defaultTimeout applies to validators the request sets no timeout for
+    // (DEFAULT_TIMEOUT_MS)
+    defaultTimeout time.Duration
+
+    // commandPrefix wraps every validator's argv (VALIDATOR_COMMAND_PREFIX)
+    commandPrefix []string
+
+    // forceColorTypes are project types whose validators always get forceColorEnv
+    forceColorTypes map[string]bool
+
+    // slots schedules validators by contention key and MAX_CONCURRENT_VALIDATORS
+    slots *validatorSlots
+
+    // contentionKeys override the default contention key per project type
+    contentionKeys map[string]string
+
+    // parallelism caps the validators one request runs at once (VALIDATOR_PARALLELISM)
+    parallelism int
+
+    // allowedCommands lists the programs validators may run; nil allows any
+    allowedCommands *commandAllowlist
+
+    // maxOutputBytes caps each captured output of a validator (MAX_OUTPUT_BYTES, 0 = unlimited)
+    maxOutputBytes int
+
+    // health is the gRPC health service, set once the server starts serving
+    health *health.Server
+
+    // readiness is the outcome of the latest readiness check
+    readiness readinessState
+
+    // inflight counts running validations; readiness drops while it is
+    // above degradeAbove (DEGRADED_INFLIGHT_THRESHOLD, 0 disables)
+    inflight     atomic.Int64
+    degradeAbove int
+
+    // resultCache answers use_cache requests for unchanged projects; nil when disabled
+    resultCache *resultCache
+
+    // subprojectDepth and subprojectLimit cap the levels searched and the
+    // projects returned by DiscoverSubProjects
+    subprojectDepth int
+    subprojectLimit int
+
+    // limits checks the roots of batch entries, which the request-limits
+    // interceptor leaves to the batch handlers so a bad root fails only its
+    // own entry
+    limits requestLimits
 }
 
 func NewCCToolsServer() *CCToolsServer {
     return &CCToolsServer{
-        lockManager: &LockManager{
-            locks: make(map[string]*LockInfo),
-        },
+        lockManager:         newLockManager(),
+        maxBatchConcurrency: envInt("BATCH_MAX_CONCURRENCY", runtime.NumCPU()),
+        history:             newValidationHistory(envInt("HISTORY_SAMPLES", 20), envInt("HISTORY_MAX_ENTRIES", 4096)),
+        markerDepth:         envInt("MARKER_SEARCH_DEPTH", 1),
+        markerDepths:        loadMarkerDepths(),
+        jobs:                newJobRegistry(),
+        adminToken:          os.Getenv("ADMIN_TOKEN"),
+        limits:              loadRequestLimits(),
+        retry:               loadRetryClassifier(),
+        contention:          newLockContention(envInt("LOCK_STATS_MAX_PROJECTS", 256)),
+        minFreeDiskMB:       int64(envInt("MIN_FREE_DISK_MB", 100)),
+        projectEnv:          loadProjectEnv(),
+        shellPool:           newWarmShellPool(envInt("WARM_SHELL_POOL_SIZE", 0), loadMaxOutputBytes()),
+        shedder:             loadLoadShedder(),
+        quotas:              loadIdentityQuotas(),
+        streamFlushLines:    envInt("STREAM_FLUSH_LINES", defaultStreamFlushLines),
+        streamFlushInterval: time.Duration(envInt("STREAM_FLUSH_INTERVAL_MS", defaultStreamFlushIntervalMs)) * time.Millisecond,
+        emptyRunDefault:     loadEmptyRunPolicy(),
+        defaultNamespace:    os.Getenv("LOCK_NAMESPACE"),
+        artifactLimits:      loadArtifactLimits(),
+        disabledDetectors:   loadDisabledDetectors(),
+        lockOwnerSources:    loadLockOwnerSources(),
+        runaway:             loadRunawayPolicy(),
+        progressInterval:    time.Duration(envInt("STREAM_PROGRESS_INTERVAL_MS", defaultStreamProgressIntervalMs)) * time.Millisecond,
+        defaultTimeout:      loadDefaultTimeout(),
+        commandPrefix:       strings.Fields(os.Getenv("VALIDATOR_COMMAND_PREFIX")),
+        forceColorTypes:     envSet("FORCE_COLOR_PROJECT_TYPES"),
+        slots:               newValidatorSlots(envInt("MAX_CONCURRENT_VALIDATORS", 0)),
+        streamClientBuffer:  envInt("STREAM_CLIENT_BUFFER", defaultStreamClientBuffer),
+        validationLogs:      loadValidationLogs(),
+        outputPrefixFormat:  envString("OUTPUT_PREFIX_FORMAT", defaultOutputPrefixFormat),
+        contentionKeys:      envMap("CONTENTION_KEYS"),
+        parallelism:         envInt("VALIDATOR_PARALLELISM", defaultValidatorParallelism),
+        allowedCommands:     loadCommandAllowlist(),
+        maxOutputBytes:      loadMaxOutputBytes(),
+        resultCache:         loadResultCache(),
+        subprojectDepth:     envInt("SUBPROJECT_MAX_DEPTH", defaultSubprojectMaxDepth),
+        subprojectLimit:     envInt("SUBPROJECT_MAX_RESULTS", defaultSubprojectMaxResults),
+        degradeAbove:        envInt("DEGRADED_INFLIGHT_THRESHOLD", 0),
     }
 }
 
 // ValidateProject implements validation with cc-tools integration
 func (s *CCToolsServer) ValidateProject(ctx context.Context, req *pb.ValidationRequest) (*pb.ValidationResponse, error) {
+    return s.validateProject(ctx, req, nil)
+}
+
+// validateProject runs a validation, sending live output to stream when it is non-nil
+func (s *CCToolsServer) validateProject(ctx context.Context, req *pb.ValidationRequest, stream *validationStream) (*pb.ValidationResponse, error) {
     startTime := time.Now()
 
+    if err := s.shedder.shedLoad(ctx, s.jobs.count()); err != nil {
+        return nil, err
+    }
+    releaseQuota, err := s.quotas.acquire(identityFromContext(ctx))
+    if err != nil {
+        return nil, err
+    }
+    defer releaseQuota()
+    defer s.trackInflight()()
+
+    // Fail fast on a full disk rather than letting validators die with
+    // cryptic write errors halfway through
+    if err := s.checkFreeDisk(req.ProjectRoot); err != nil {
+        return nil, err
+    }
+    if err := checkWorkingDir(req.ProjectRoot, req.WorkingDir); err != nil {
+        return nil, err
+    }
+
     // Get project metadata first
     metadata, err := s.detectProjectMetadata(req.ProjectRoot)
     if err != nil {
@@ -52,20 +266,111 @@ func (s *CCToolsServer) ValidateProject(ctx context.Context, req *pb.ValidationR
         }, nil
     }
 
+    // Add the files changed in the requested git range to the changed-files list
+    var changedFiles []string
+    if req.GitBaseRef != "" || req.GitHeadRef != "" {
+        changedFiles, err = changedFilesInRange(ctx, req.ProjectRoot, req.GitBaseRef, req.GitHeadRef)
+        if err != nil {
+            return nil, err
+        }
+        req = proto.Clone(req).(*pb.ValidationRequest)
+        req.FilePaths = append(req.FilePaths, changedFiles...)
+    }
+
+    // A dry run stops at the plan: nothing is executed, cached or registered
+    if req.DryRun {
+        return s.dryRun(ctx, req, metadata, stream, startTime), nil
+    }
+
+    // Answer from the result cache when an identical request already ran
+    // against an unchanged tree
+    cacheKey := ""
+    if stream == nil {
+        cacheKey = s.resultCache.key(req)
+    }
+    if cached, ok := s.resultCache.get(cacheKey); ok {
+        cached.CacheHit = true
+        cached.RunId = ""
+        markUnchanged(cached)
+        cached.ExecutionTimeMs = time.Since(startTime).Milliseconds()
+        return cached, nil
+    }
+
+    // Register the run so it can be aborted while validators execute. It
+    // derives from the caller's context, so a cancelled call or an expired
+    // deadline kills the validators too.
+    jobCtx, job, finish := s.jobs.start(ctx, req.ProjectRoot)
+    defer finish()
+    stream.setRunID(job.id)
+    sendRunID(ctx, job.id)
+
+    specs := s.resolveValidators(req, metadata)
+    var manifest *pb.RunManifest
+    if req.IncludeManifest {
+        manifest = s.buildRunManifest(jobCtx, req, metadata, specs)
+    }
+
     // Execute validations based on project type
     results := make([]*pb.ValidationResult, 0)
-
-    // Run lint if available
-    if lintCmd, exists := metadata.Commands["lint"]; exists {
-        result := s.executeValidator("lint", lintCmd, req.ProjectRoot, req.TimeoutMs)
+    for _, result := range excludedValidators(req, metadata) {
+        stream.finish(nil, result)
         results = append(results, result)
     }
-
-    // Run test if available
-    if testCmd, exists := metadata.Commands["test"]; exists {
-        result := s.executeValidator("test", testCmd, req.ProjectRoot, req.TimeoutMs)
+    usage := &runConcurrency{}
+    failFast := ""
+    if req.CheckToolchain {
+        // Run first so an outdated toolchain fails fast
+        result := s.checkToolchain(jobCtx, req, metadata)
+        stream.finish(nil, result)
         results = append(results, result)
+        if !result.Success {
+            failFast = "Toolchain version check failed"
+        }
     }
+    // The configure step generates the build tree the others work in, so
+    // it runs alone before them
+    configure, specs := splitConfigure(specs)
+    if configure != nil {
+        var result *pb.ValidationResult
+        if failFast != "" {
+            result = skippedResult(configure.name, pb.SkipReason_SKIP_REASON_FAIL_FAST, failFast)
+            stream.finish(nil, result)
+        } else {
+            result = s.runScheduled(jobCtx, req, configure, stream, usage)
+        }
+        results = append(results, result)
+        if !result.Success {
+            failFast = "Configure step failed"
+        }
+    }
+    // Validators run in parallel, each with its own timeout; they start in
+    // order as parallel slots free up, and results are collected by index
+    // and put into resultOrder below
+    parallel := make(chan struct{}, s.validatorParallelism(req))
+    specResults := make([]*pb.ValidationResult, len(specs))
+    var wg sync.WaitGroup
+    for i, spec := range specs {
+        if failFast != "" {
+            specResults[i] = skippedResult(spec.name, pb.SkipReason_SKIP_REASON_FAIL_FAST, failFast)
+            stream.finish(nil, specResults[i])
+            continue
+        }
+        select {
+        case parallel <- struct{}{}:
+        case <-jobCtx.Done():
+            specResults[i] = &pb.ValidationResult{Validator: spec.name, Error: fmt.Sprintf("cancelled while waiting for a validator slot: %v", jobCtx.Err())}
+            stream.finish(nil, specResults[i])
+            continue
+        }
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            defer func() { <-parallel }()
+            specResults[i] = s.runScheduled(jobCtx, req, spec, stream, usage)
+        }()
+    }
+    wg.Wait()
+    results = append(results, specResults...)
 
     // Check overall success
     success := true
@@ -76,57 +381,259 @@ func (s *CCToolsServer) ValidateProject(ctx context.Context, req *pb.ValidationR
         }
     }
 
-    return &pb.ValidationResponse{
+    // Decide what a run that executed nothing means
+    var errorMessage string
+    var warnings []string
+    if !ranAny(results) {
+        switch s.emptyRunPolicy(req) {
+        case pb.EmptyRunPolicy_EMPTY_RUN_POLICY_ERROR:
+            success = false
+            errorMessage = noValidatorsRan
+        case pb.EmptyRunPolicy_EMPTY_RUN_POLICY_WARN:
+            warnings = append(warnings, noValidatorsRan)
+        }
+    }
+
+    sortResults(results)
+    recordValidations(metadata.ProjectType, results)
+
+    // The summary always covers every validator, even when passing results
+    // are dropped from the response
+    summary := summarizeResults(results)
+    usage.annotate(summary)
+    if req.FailuresOnly {
+        results = failedResults(results)
+    }
+
+    resp := &pb.ValidationResponse{
         Success:         success,
         Results:         results,
         Metadata:        metadata,
         ExecutionTimeMs: time.Since(startTime).Milliseconds(),
-    }, nil
+        Summary:         summary,
+        ChangedFiles:    changedFiles,
+        ErrorMessage:    errorMessage,
+        Warnings:        warnings,
+        Manifest:        manifest,
+        RunId:           job.id,
+    }
+    if jobCtx.Err() == nil {
+        s.resultCache.put(cacheKey, resp)
+    }
+    return resp, nil
+}
+
+// failedResults keeps only the results of validators that failed
+func failedResults(results []*pb.ValidationResult) []*pb.ValidationResult {
+    failed := make([]*pb.ValidationResult, 0)
+    for _, result := range results {
+        if !result.Success {
+            failed = append(failed, result)
+        }
+    }
+    return failed
+}
+
+// checkFreeDisk returns FailedPrecondition when the filesystem holding
+// projectRoot has less than the configured minimum free. Platforms or paths
+// where free space cannot be determined are not blocked.
+func (s *CCToolsServer) checkFreeDisk(projectRoot string) error {
+    if s.minFreeDiskMB <= 0 {
+        return nil
+    }
+    free, ok := freeDiskMB(projectRoot)
+    if !ok || free >= s.minFreeDiskMB {
+        return nil
+    }
+    return status.Errorf(codes.FailedPrecondition, "insufficient disk space: %d MB free under %s, %d MB required", free, projectRoot, s.minFreeDiskMB)
+}
+
+// summarizeResults counts passed, failed and skipped validators
+func summarizeResults(results []*pb.ValidationResult) *pb.ValidationSummary {
+    summary := &pb.ValidationSummary{Total: int32(len(results))}
+    for _, result := range results {
+        switch {
+        case result.Skipped:
+            summary.Skipped++
+        case result.Success:
+            summary.Passed++
+        default:
+            summary.Failed++
+        }
+    }
+    return summary
 }
 
 // GetProjectMetadata detects and returns project metadata
 func (s *CCToolsServer) GetProjectMetadata(ctx context.Context, req *pb.ValidationRequest) (*pb.ProjectMetadata, error) {
-    return s.detectProjectMetadata(req.ProjectRoot)
+    metadata, err := s.detectProjectMetadata(req.ProjectRoot)
+    if err != nil {
+        return nil, err
+    }
+
+    // Tool lookups add latency, so they only run on request
+    if req.VerifyTooling {
+        annotateTooling(metadata)
+    }
+
+    metadata.Etag = metadataETag(req.ProjectRoot, metadata)
+    if req.IfNoneMatch != "" && req.IfNoneMatch == metadata.Etag {
+        return &pb.ProjectMetadata{Etag: metadata.Etag, NotModified: true}, nil
+    }
+    return metadata, nil
 }
 
 // AcquireLock acquires a PID-based lock for the project
 func (s *CCToolsServer) AcquireLock(ctx context.Context, req *pb.LockRequest) (*pb.LockStatus, error) {
+    lockStatus, _, err := s.acquireLock(ctx, req)
+    return lockStatus, err
+}
+
+// acquireLock takes the lock, waiting up to timeout_ms for a held one, and
+// reports whether this call acquired it
+func (s *CCToolsServer) acquireLock(ctx context.Context, req *pb.LockRequest) (*pb.LockStatus, bool, error) {
+    namespace := s.lockNamespace(ctx, req)
+    name := lockName(namespace, req.ProjectPath)
+    owner := s.lockOwner(ctx, req)
+    if err := ctx.Err(); err != nil {
+        return nil, false, status.FromContextError(err).Err()
+    }
+
+    released := s.lockManager.nextRelease()
+    lockStatus, acquired, err := s.tryAcquireLock(req, namespace, owner)
+    if err != nil || acquired {
+        return lockStatus, acquired, err
+    }
+    if req.TimeoutMs <= 0 {
+        s.contention.record(name, 0, false)
+        return lockStatus, false, nil
+    }
+
+    // Held by someone else: retry whenever a lock is released, and poll for
+    // holders the server is not told about, until it frees up, timeout_ms
+    // elapses or the caller goes away. A waiter holds no place in any
+    // queue, so a cancelled one never delays the next acquirer.
+    waitStart := time.Now()
+    deadline := time.NewTimer(time.Duration(req.TimeoutMs) * time.Millisecond)
+    defer deadline.Stop()
+    ticker := time.NewTicker(lockPollInterval)
+    defer ticker.Stop()
+
+    for {
+        select {
+        case <-ctx.Done():
+            s.contention.record(name, time.Since(waitStart), false)
+            return nil, false, status.FromContextError(ctx.Err()).Err()
+        case <-deadline.C:
+            s.contention.record(name, time.Since(waitStart), false)
+            return lockStatus, false, nil
+        case <-released:
+        case <-ticker.C:
+        }
+
+        released = s.lockManager.nextRelease()
+        lockStatus, acquired, err = s.tryAcquireLock(req, namespace, owner)
+        if err != nil {
+            return nil, false, err
+        }
+        if acquired && ctx.Err() != nil {
+            // The caller hung up while we were taking the lock; nobody would
+            // ever release it, so hand it straight to the next waiter
+            s.releaseAbandonedLock(req, namespace)
+            s.contention.record(name, time.Since(waitStart), false)
+            return nil, false, status.FromContextError(ctx.Err()).Err()
+        }
+        if acquired {
+            s.contention.record(name, time.Since(waitStart), true)
+            return lockStatus, true, nil
+        }
+    }
+}
+
+// releaseAbandonedLock drops a lock taken on behalf of a caller that is gone
+func (s *CCToolsServer) releaseAbandonedLock(req *pb.LockRequest, namespace string) {
+    s.lockManager.mutex.Lock()
+    defer s.lockManager.mutex.Unlock()
+    if err := s.lockManager.remove(lockID(namespace, req.ProjectPath), req.ProjectPath, namespace); err != nil {
+        slog.Error("failed to release abandoned lock", "project_path", req.ProjectPath, "namespace", namespace, "error", err)
+    }
+}
+
+// tryAcquireLock makes a single attempt to take the lock, reporting whether
+// it succeeded. When it did not, the status describes the current holder.
+func (s *CCToolsServer) tryAcquireLock(req *pb.LockRequest, namespace, owner string) (*pb.LockStatus, bool, error) {
     s.lockManager.mutex.Lock()
     defer s.lockManager.mutex.Unlock()
 
-    lockID := fmt.Sprintf("devflow_%s", req.ProjectPath)
+    lockID := lockID(namespace, req.ProjectPath)
 
     // Check if already locked
-    if lockInfo, exists := s.lockManager.locks[lockID]; exists {
-        // Check if process is still alive
-        if s.isProcessAlive(lockInfo.ProcessID) && !req.ForceRelease {
+    lockInfo, err := s.lockManager.lookup(lockID, req.ProjectPath, namespace)
+    if err != nil {
+        return nil, false, status.Errorf(codes.Internal, "failed to read lock: %v", err)
+    }
+    if lockInfo != nil {
+        // Check if process is still alive and the lock has not expired
+        if s.isProcessAlive(lockInfo.ProcessID) && !lockInfo.expired(time.Now()) && !req.ForceRelease {
+            lockContentionTotal.Inc()
             return &pb.LockStatus{
                 LockId:      lockID,
                 ProjectPath: req.ProjectPath,
+                Namespace:   namespace,
                 ProcessId:   lockInfo.ProcessID,
                 AcquiredAt:  lockInfo.AcquiredAt,
                 IsLocked:    true,
-            }, nil
+                Owner:       lockInfo.Owner,
+                ExpiresAt:   lockInfo.expiresAt(),
+            }, false, nil
+        }
+        // Stale, expired or force-released: clear it before re-acquiring
+        if err := s.lockManager.remove(lockID, req.ProjectPath, namespace); err != nil {
+            return nil, false, status.Errorf(codes.Internal, "failed to clear stale lock: %v", err)
         }
     }
 
     // Acquire lock
     currentPID := int32(os.Getpid())
-    lockInfo := &LockInfo{
+    lockInfo = &LockInfo{
         ProcessID:   currentPID,
         AcquiredAt:  time.Now().Unix(),
         ProjectPath: req.ProjectPath,
+        Owner:       owner,
+        Namespace:   namespace,
+        TTLSeconds:  int64(req.TtlSeconds),
     }
 
-    s.lockManager.locks[lockID] = lockInfo
+    if err := s.lockManager.store(lockID, lockInfo); err != nil {
+        if errors.Is(err, fs.ErrExist) {
+            // An external tool created the lock file after our check
+            if holder, _ := readLockFile(req.ProjectPath, namespace); holder != nil {
+                lockContentionTotal.Inc()
+                return &pb.LockStatus{
+                    LockId:      lockID,
+                    ProjectPath: req.ProjectPath,
+                    Namespace:   namespace,
+                    ProcessId:   holder.ProcessID,
+                    AcquiredAt:  holder.AcquiredAt,
+                    IsLocked:    true,
+                    Owner:       holder.Owner,
+                    ExpiresAt:   holder.expiresAt(),
+                }, false, nil
+            }
+        }
+        return nil, false, status.Errorf(codes.Internal, "failed to write lock: %v", err)
+    }
 
     return &pb.LockStatus{
         LockId:      lockID,
         ProjectPath: req.ProjectPath,
+        Namespace:   namespace,
         ProcessId:   currentPID,
         AcquiredAt:  lockInfo.AcquiredAt,
         IsLocked:    true,
-    }, nil
+        Owner:       lockInfo.Owner,
+        ExpiresAt:   lockInfo.expiresAt(),
+    }, true, nil
 }
 
 // ReleaseLock releases the lock for the project
@@ -134,12 +641,16 @@ func (s *CCToolsServer) ReleaseLock(ctx context.Context, req *pb.LockRequest) (*
     s.lockManager.mutex.Lock()
     defer s.lockManager.mutex.Unlock()
 
-    lockID := fmt.Sprintf("devflow_%s", req.ProjectPath)
-    delete(s.lockManager.locks, lockID)
+    namespace := s.lockNamespace(ctx, req)
+    lockID := lockID(namespace, req.ProjectPath)
+    if err := s.lockManager.remove(lockID, req.ProjectPath, namespace); err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to remove lock: %v", err)
+    }
 
     return &pb.LockStatus{
         LockId:      lockID,
         ProjectPath: req.ProjectPath,
+        Namespace:   namespace,
         IsLocked:    false,
     }, nil
 }
@@ -149,21 +660,30 @@ func (s *CCToolsServer) CheckLock(ctx context.Context, req *pb.LockRequest) (*pb
     s.lockManager.mutex.RLock()
     defer s.lockManager.mutex.RUnlock()
 
-    lockID := fmt.Sprintf("devflow_%s", req.ProjectPath)
+    namespace := s.lockNamespace(ctx, req)
+    lockID := lockID(namespace, req.ProjectPath)
 
-    if lockInfo, exists := s.lockManager.locks[lockID]; exists {
+    lockInfo, err := s.lockManager.lookup(lockID, req.ProjectPath, namespace)
+    if err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to read lock: %v", err)
+    }
+    if lockInfo != nil {
         return &pb.LockStatus{
             LockId:      lockID,
             ProjectPath: req.ProjectPath,
+            Namespace:   namespace,
             ProcessId:   lockInfo.ProcessID,
             AcquiredAt:  lockInfo.AcquiredAt,
-            IsLocked:    s.isProcessAlive(lockInfo.ProcessID),
+            IsLocked:    s.isProcessAlive(lockInfo.ProcessID) && !lockInfo.expired(time.Now()),
+            Owner:       lockInfo.Owner,
+            ExpiresAt:   lockInfo.expiresAt(),
         }, nil
     }
 
     return &pb.LockStatus{
         LockId:      lockID,
         ProjectPath: req.ProjectPath,
+        Namespace:   namespace,
         IsLocked:    false,
     }, nil
 }
@@ -176,71 +696,273 @@ func (s *CCToolsServer) detectProjectMetadata(projectRoot string) (*pb.ProjectMe
     }
 
     // Check for different project types
-    if s.fileExists(projectRoot + "/package.json") {
-        metadata.ProjectType = "npm"
-        metadata.Language = "javascript"
-        metadata.ConfigFiles = append(metadata.ConfigFiles, "package.json")
-        metadata.Commands["lint"] = "npm run lint"
-        metadata.Commands["test"] = "npm test"
-    } else if s.fileExists(projectRoot + "/Cargo.toml") {
-        metadata.ProjectType = "cargo"
-        metadata.Language = "rust"
-        metadata.ConfigFiles = append(metadata.ConfigFiles, "Cargo.toml")
-        metadata.Commands["lint"] = "cargo clippy"
-        metadata.Commands["test"] = "cargo test"
-    } else if s.fileExists(projectRoot + "/Makefile") {
-        metadata.ProjectType = "make"
-        metadata.ConfigFiles = append(metadata.ConfigFiles, "Makefile")
-        metadata.Commands["lint"] = "make lint"
-        metadata.Commands["test"] = "make test"
-    } else {
-        metadata.ProjectType = "unknown"
+    metadata.ProjectType = "unknown"
+    for _, d := range detectors {
+        if m, ok := d.matches(s, metadata); ok {
+            d.describe(s, metadata, m)
+            break
+        }
     }
 
+    applyDevflowConfig(metadata)
+    annotateProjectName(metadata)
+    metadata.Fingerprint = configFingerprint(projectRoot, metadata.ConfigFiles)
     return metadata, nil
 }
 
-func (s *CCToolsServer) executeValidator(name, command, projectRoot string, timeoutMs int32) *pb.ValidationResult {
+// runValidator executes a single validator for the request, honoring the
+// duration hint and recording the execution time in the history
+func (s *CCToolsServer) runValidator(ctx context.Context, req *pb.ValidationRequest, spec *validatorSpec) *pb.ValidationResult {
+    if req.MaxDurationHintMs > 0 {
+        if p50, ok := s.history.p50(req.ProjectRoot, spec.name); ok && p50 > req.MaxDurationHintMs {
+            return skippedResult(spec.name, pb.SkipReason_SKIP_REASON_TOO_SLOW,
+                fmt.Sprintf("Typical duration %dms exceeds hint of %dms", p50, req.MaxDurationHintMs))
+        }
+    }
+
     startTime := time.Now()
+    result := s.executeValidator(ctx, spec)
+    s.history.record(req.ProjectRoot, spec.name, result.ExecutionTimeMs)
+    result.Diagnostics = parseDiagnostics(spec, result)
+
+    if len(req.ArtifactGlobs) > 0 {
+        // Truncate to the filesystem's mtime granularity so files written in
+        // the first instants of the run are not missed
+        result.Artifacts = collectArtifacts(req.ProjectRoot, req.ArtifactGlobs, startTime.Truncate(time.Second), s.artifactLimits)
+    }
+    return result
+}
 
-    // Parse command
-    parts := strings.Fields(command)
-    if len(parts) == 0 {
-        return &pb.ValidationResult{
-            Validator:       name,
-            Success:        false,
-            Error:          "Empty command",
-            ExecutionTimeMs: time.Since(startTime).Milliseconds(),
-        }
+// skippedResult builds the result for a validator that was not executed.
+// Skipped validators report success so they never fail a run on their own;
+// clients should check Skipped before trusting Success.
+func skippedResult(name string, reason pb.SkipReason, detail string) *pb.ValidationResult {
+    return &pb.ValidationResult{
+        Validator:  name,
+        Success:    true,
+        Output:     detail,
+        Skipped:    true,
+        SkipReason: reason,
     }
+}
 
-    // Create command with timeout
-    timeout := time.Duration(timeoutMs) * time.Millisecond
-    if timeout == 0 {
-        timeout = 30 * time.Second // Default timeout
+func (s *CCToolsServer) executeValidator(ctx context.Context, spec *validatorSpec) (result *pb.ValidationResult) {
+    startTime := time.Now()
+    name := spec.name
+    ctx, span := startValidatorSpan(ctx, spec)
+    defer func() { endValidatorSpan(span, result, time.Since(startTime)) }()
+
+    parts := spec.argv()
+    if rejected := s.checkCommand(spec, parts); rejected != nil {
+        rejected.ExecutionTimeMs = time.Since(startTime).Milliseconds()
+        return rejected
     }
+    // The variables set for the validator often carry credentials, so only
+    // the redacted form is logged
+    debugCtx(ctx, "validator starting", "validator", name, "argv", parts, "dir", spec.workDir, "env", redactEnv(spec.env))
+
+    // Re-run transient failures up to the requested number of retries,
+    // backing off between attempts; permanent failures are returned as-is
+    for attempt := 1; ; attempt++ {
+        var exitCode int
+        result, exitCode = s.runCommand(ctx, spec, parts)
+        result.AttemptCount = int32(attempt)
+        // Only plain nonzero exits and timeouts are candidates for a retry
+        switch {
+        case result.Success:
+        case result.FailureReason == pb.FailureReason_FAILURE_REASON_EXIT_CODE:
+            result.Transient = exitCode >= 0 && (spec.retryExitCodes[exitCode] || s.retry.transient(exitCode, result.Output))
+        case result.FailureReason == pb.FailureReason_FAILURE_REASON_TIMEOUT:
+            result.Transient = true
+        }
+        if !result.Transient || attempt > spec.retries || !sleepContext(ctx, s.retry.backoff(attempt)) {
+            break
+        }
+    }
+    result.WorkDir = spec.workDir
+    result.ExecutionTimeMs = time.Since(startTime).Milliseconds()
+    observeValidatorDuration(name, time.Since(startTime))
+    logCtx(ctx, "validator finished", "validator", name, "dir", spec.workDir, "success", result.Success, "attempts", result.AttemptCount, "dur_ms", result.ExecutionTimeMs)
+    return result
+}
 
-    ctx, cancel := context.WithTimeout(context.Background(), timeout)
-    defer cancel()
+// checkCommand returns the failed result for a command that must not be
+// started: one that does not parse, is empty, or runs a program outside
+// ALLOWED_COMMANDS. It returns nil for a command that may run.
+func (s *CCToolsServer) checkCommand(spec *validatorSpec, parts []string) *pb.ValidationResult {
+    // String-form commands are split with shell quoting rules unless a
+    // login shell interprets them
+    if spec.args == nil && !spec.loginShell {
+        if _, err := shellSplit(spec.command); err != nil {
+            return &pb.ValidationResult{
+                Validator:   spec.name,
+                Success:     false,
+                Error:       fmt.Sprintf("Invalid command: %v", err),
+                CommandForm: spec.commandForm(),
+                WorkDir:     spec.workDir,
+            }
+        }
+    }
+    if len(parts) == 0 || len(strings.Fields(spec.command)) == 0 {
+        return &pb.ValidationResult{
+            Validator: spec.name,
+            Success:   false,
+            Error:     "Empty command",
+            WorkDir:   spec.workDir,
+        }
+    }
+    if err := s.allowedCommands.check(spec); err != nil {
+        return &pb.ValidationResult{
+            Validator:     spec.name,
+            Success:       false,
+            Error:         err.Error(),
+            ResolvedArgv:  parts,
+            CommandForm:   spec.commandForm(),
+            WorkDir:       spec.workDir,
+            FailureReason: pb.FailureReason_FAILURE_REASON_NOT_PERMITTED,
+        }
+    }
+    return nil
+}
 
-    cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
-    cmd.Dir = projectRoot
+// runCommand performs a single attempt of a validator, returning its result
+// and exit code (-1 if the command could not run or was killed)
+func (s *CCToolsServer) runCommand(ctx context.Context, spec *validatorSpec, parts []string) (*pb.ValidationResult, int) {
+    startTime := time.Now()
+    name := spec.name
 
-    output, err := cmd.CombinedOutput()
+    // Create command with timeout
+    parent := ctx
+    ctx, cancel := context.WithTimeout(ctx, spec.timeout)
+    defer cancel()
+    ctx, trip := context.WithCancelCause(ctx)
+    defer trip(nil)
+    guard := s.runaway.guard(spec, func() { trip(errRunawayOutput) })
+
+    var output, stdout, stderr string
+    var truncated bool
+    var err error
+    var startFailure pb.StartFailureReason
+    if shell := s.shellPool.take(spec); shell != nil {
+        output, err = shell.run(ctx, spec, guard)
+        stdout, stderr = shell.split.streams()
+        truncated = shell.output.truncated() || shell.split.truncated()
+    } else {
+        cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+        cmd.Dir = spec.workDir
+        cmd.Env = spec.environ()
+        setProcessGroup(cmd)
+
+        // exec copies stdin from its own goroutine, so a child that fills
+        // its output pipes before draining stdin cannot deadlock the write
+        stdin, closeStdin, stdinErr := spec.openStdin()
+        if stdinErr != nil {
+            return &pb.ValidationResult{
+                Validator:       name,
+                Success:         false,
+                Error:           fmt.Sprintf("failed to open stdin_file: %v", stdinErr),
+                ExecutionTimeMs: time.Since(startTime).Milliseconds(),
+                ResolvedArgv:    parts,
+                CommandForm:     spec.commandForm(),
+            }, -1
+        }
+        defer closeStdin()
+        cmd.Stdin = stdin
+
+        // stdout and stderr are kept apart and also interleaved into one
+        // combined sink, each capped at MAX_OUTPUT_BYTES as it is captured
+        buf := &tailBuffer{max: s.maxOutputBytes}
+        var sink io.Writer = buf
+        var w *lineWriter
+        if spec.stream != nil {
+            w = &lineWriter{fn: spec.stream, output: tailBuffer{max: s.maxOutputBytes}}
+            sink = w
+        }
+        if spec.log != nil {
+            sink = io.MultiWriter(sink, &bestEffortWriter{validator: name, w: spec.log})
+        }
+        if guard != nil {
+            sink = io.MultiWriter(sink, guard)
+        }
+        split := newSplitOutput(sink, s.maxOutputBytes)
+        split.attach(cmd)
+        err = cmd.Run()
+        stdout, stderr = split.streams()
+        if w != nil {
+            w.flush()
+            buf = &w.output
+        }
+        output = buf.String()
+        truncated = buf.truncated() || split.truncated()
+        startFailure = startFailureReason(cmd, err)
+    }
 
     success := err == nil
     errorMsg := ""
+    exitCode := 0
+    failureReason := pb.FailureReason_FAILURE_REASON_UNSPECIFIED
     if err != nil {
         errorMsg = err.Error()
+        exitCode = -1
+        var exitErr *exec.ExitError
+        if errors.As(err, &exitErr) {
+            exitCode = exitErr.ExitCode()
+        }
+        if spec.loginShell && startFailure == pb.StartFailureReason_START_FAILURE_REASON_UNSPECIFIED {
+            startFailure = shellStartFailureReason(exitCode)
+        }
+    }
+    // A command that exited 0 succeeded even if the run was cancelled
+    // after it finished; only a failed command is attributed to a cancel
+    switch cause := context.Cause(ctx); {
+    case err == nil:
+    case errors.Is(cause, errAborted), errors.Is(cause, errShutdown):
+        success = false
+        errorMsg = cause.Error()
+        failureReason = pb.FailureReason_FAILURE_REASON_ABORTED
+    case errors.Is(cause, errRunCancelled):
+        success = false
+        errorMsg = cause.Error()
+        failureReason = pb.FailureReason_FAILURE_REASON_CANCELLED
+    case errors.Is(cause, errRunawayOutput):
+        success = false
+        errorMsg = errRunawayOutput.Error()
+        failureReason = pb.FailureReason_FAILURE_REASON_RUNAWAY_OUTPUT
+    case parent.Err() != nil:
+        // The caller went away or its deadline passed, not the validator's own timeout
+        success = false
+        errorMsg = fmt.Sprintf("cancelled by caller: %v", parent.Err())
+        failureReason = pb.FailureReason_FAILURE_REASON_CANCELLED
+    case startFailure == pb.StartFailureReason_START_FAILURE_REASON_NOT_FOUND:
+        failureReason = pb.FailureReason_FAILURE_REASON_COMMAND_NOT_FOUND
+    case errors.Is(ctx.Err(), context.DeadlineExceeded):
+        failureReason = pb.FailureReason_FAILURE_REASON_TIMEOUT
+        errorMsg = fmt.Sprintf("timed out after %s", spec.timeout)
+    case startFailure != pb.StartFailureReason_START_FAILURE_REASON_UNSPECIFIED:
+        // Described by start_failure_reason
+    case exitCode > 0:
+        failureReason = pb.FailureReason_FAILURE_REASON_EXIT_CODE
+    case exitCode == -1:
+        // An ExitError without an exit status: the process died from a signal
+        failureReason = pb.FailureReason_FAILURE_REASON_SIGNALED
     }
 
     return &pb.ValidationResult{
         Validator:       name,
         Success:        success,
-        Output:         string(output),
+        Output:         output,
+        Stdout:          stdout,
+        Stderr:          stderr,
         Error:          errorMsg,
         ExecutionTimeMs: time.Since(startTime).Milliseconds(),
-    }
+        FailureReason:   failureReason,
+        ResolvedArgv:    parts,
+        CommandForm:     spec.commandForm(),
+        StartFailed:     startFailure != pb.StartFailureReason_START_FAILURE_REASON_UNSPECIFIED,
+        StartFailureReason: startFailure,
+        ExitCode:        int32(exitCode),
+        Truncated:       truncated,
+    }, exitCode
 }
 
 func (s *CCToolsServer) fileExists(filepath string) bool {
@@ -248,12 +970,18 @@ func (s *CCToolsServer) fileExists(filepath string) bool {
     return err == nil
 }
 
+// isProcessAlive reports whether the lock holder's PID still runs. Locks
+// taken through this server record the server's own PID, which is always
+// alive, so only a .devflow.lock file written by another process (see
+// LOCK_FILES) can be detected as stale this way; in-memory locks go stale
+// through their TTL alone. Signal 0 checks existence without delivering a
+// signal, which also means a PID reused by an unrelated process counts as
+// alive.
 func (s *CCToolsServer) isProcessAlive(pid int32) bool {
-    // Simple check - in production would be more robust
     process, err := os.FindProcess(int(pid))
     if err != nil {
         return false
     }
-    return process.Signal(nil) == nil
+    return process.Signal(syscall.Signal(0)) == nil
 }
 
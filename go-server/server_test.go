@@ -0,0 +1,235 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "testing"
+    "time"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// shellSpec is a validator running script with sh, under any allowlist
+func shellSpec(t *testing.T, name, script string) *validatorSpec {
+    t.Helper()
+    args := []string{"sh", "-c", script}
+    return &validatorSpec{
+        name:    name,
+        command: shellJoin(args),
+        args:    args,
+        workDir: t.TempDir(),
+        timeout: 30 * time.Second,
+    }
+}
+
+// waitForFile returns the trimmed content of path once it is non-empty
+func waitForFile(t *testing.T, path string) string {
+    t.Helper()
+    deadline := time.Now().Add(5 * time.Second)
+    for {
+        if data, err := os.ReadFile(path); err == nil && len(strings.TrimSpace(string(data))) > 0 {
+            return strings.TrimSpace(string(data))
+        }
+        if time.Now().After(deadline) {
+            t.Fatalf("%s was never written", path)
+        }
+        time.Sleep(10 * time.Millisecond)
+    }
+}
+
+func TestRunCommandCancelledByCaller(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    s := NewCCToolsServer()
+    spec := shellSpec(t, "test", `echo $$ > pid; exec sleep 30`)
+    pidFile := filepath.Join(spec.workDir, "pid")
+
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    done := make(chan *pb.ValidationResult, 1)
+    go func() {
+        result, _ := s.runCommand(ctx, spec, spec.argv())
+        done <- result
+    }()
+    pid, err := strconv.Atoi(waitForFile(t, pidFile))
+    if err != nil {
+        t.Fatal(err)
+    }
+    cancel()
+
+    var result *pb.ValidationResult
+    select {
+    case result = <-done:
+    case <-time.After(10 * time.Second):
+        t.Fatal("runCommand did not return after the caller cancelled")
+    }
+    if result.Success || result.FailureReason != pb.FailureReason_FAILURE_REASON_CANCELLED {
+        t.Errorf("result = %v, want FAILURE_REASON_CANCELLED", result)
+    }
+    if !strings.Contains(result.Error, "cancelled by caller") {
+        t.Errorf("error = %q, want a cancelled by caller message", result.Error)
+    }
+    if s.isProcessAlive(int32(pid)) {
+        t.Errorf("process %d still running after cancellation", pid)
+    }
+}
+
+func TestRunCommandSuccessSurvivesLateCancel(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    s := NewCCToolsServer()
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+
+    // The trailing partial line is flushed after the command has exited,
+    // so the caller goes away between the exit and the classification
+    spec := shellSpec(t, "test", `printf done`)
+    spec.stream = func(string) { cancel() }
+
+    result, exitCode := s.runCommand(ctx, spec, spec.argv())
+    if ctx.Err() == nil {
+        t.Fatal("the stream callback did not cancel the caller")
+    }
+    if !result.Success || exitCode != 0 || result.FailureReason != pb.FailureReason_FAILURE_REASON_UNSPECIFIED {
+        t.Errorf("result = %v (exit %d), want the exit 0 reported as success", result, exitCode)
+    }
+}
+
+func TestCancelledLockWaiterDoesNotBlockLaterAcquirers(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    root := t.TempDir()
+
+    if held, err := ts.client.AcquireLock(ctx, &pb.LockRequest{ProjectPath: root, Owner: "first"}); err != nil || !held.IsLocked {
+        t.Fatalf("AcquireLock = %v, %v", held, err)
+    }
+
+    // A waiter that hangs up gets Canceled well before its timeout
+    waitCtx, cancel := context.WithCancel(ctx)
+    cancelled := make(chan error, 1)
+    go func() {
+        _, err := ts.client.AcquireLock(waitCtx, &pb.LockRequest{ProjectPath: root, Owner: "cancelled", TimeoutMs: 30000})
+        cancelled <- err
+    }()
+    time.Sleep(100 * time.Millisecond)
+    cancel()
+    select {
+    case err := <-cancelled:
+        if status.Code(err) != codes.Canceled {
+            t.Errorf("cancelled waiter got %v, want Canceled", err)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("cancelled waiter did not return")
+    }
+
+    // The next waiter takes the lock as soon as it is released
+    type acquired struct {
+        lock *pb.LockStatus
+        err  error
+        at   time.Time
+    }
+    next := make(chan acquired, 1)
+    go func() {
+        lock, err := ts.client.AcquireLock(ctx, &pb.LockRequest{ProjectPath: root, Owner: "next", TimeoutMs: 20000})
+        next <- acquired{lock, err, time.Now()}
+    }()
+    time.Sleep(100 * time.Millisecond)
+    released := time.Now()
+    if _, err := ts.client.ReleaseLock(ctx, &pb.LockRequest{ProjectPath: root, Owner: "first"}); err != nil {
+        t.Fatalf("ReleaseLock: %v", err)
+    }
+    select {
+    case got := <-next:
+        if got.err != nil || !got.lock.IsLocked || got.lock.Owner != "next" {
+            t.Fatalf("next waiter got %v, %v, want the lock", got.lock, got.err)
+        }
+        if wait := got.at.Sub(released); wait > 2*time.Second {
+            t.Errorf("next waiter took %s after the release", wait)
+        }
+    case <-time.After(10 * time.Second):
+        t.Fatal("next waiter never got the lock")
+    }
+}
+
+func TestExecuteValidatorFailureReasons(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    s := NewCCToolsServer()
+    tests := []struct {
+        name     string
+        script   string
+        args     []string
+        timeout  time.Duration
+        reason   pb.FailureReason
+        exitCode int32
+    }{
+        {name: "success", script: "true", reason: pb.FailureReason_FAILURE_REASON_UNSPECIFIED, exitCode: 0},
+        {name: "non-zero exit", script: "exit 3", reason: pb.FailureReason_FAILURE_REASON_EXIT_CODE, exitCode: 3},
+        {name: "timeout", script: "exec sleep 30", timeout: 200 * time.Millisecond, reason: pb.FailureReason_FAILURE_REASON_TIMEOUT, exitCode: -1},
+        {name: "command not found", args: []string{"devflow-no-such-command"}, reason: pb.FailureReason_FAILURE_REASON_COMMAND_NOT_FOUND, exitCode: -1},
+        {name: "signal", script: "kill -KILL $$", reason: pb.FailureReason_FAILURE_REASON_SIGNALED, exitCode: -1},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            spec := shellSpec(t, "test", tt.script)
+            if tt.args != nil {
+                spec.args, spec.command = tt.args, shellJoin(tt.args)
+            }
+            if tt.timeout > 0 {
+                spec.timeout = tt.timeout
+            }
+            result := s.executeValidator(t.Context(), spec)
+            if result.FailureReason != tt.reason || result.ExitCode != tt.exitCode {
+                t.Errorf("reason=%v exit=%d, want %v and %d (error %q)", result.FailureReason, result.ExitCode, tt.reason, tt.exitCode, result.Error)
+            }
+            if result.Success != (tt.reason == pb.FailureReason_FAILURE_REASON_UNSPECIFIED) {
+                t.Errorf("Success = %v for %v", result.Success, tt.reason)
+            }
+        })
+    }
+}
+
+func TestConfigureRunsBeforeOtherValidators(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    ts := newTestServer(t, serverConfig{})
+    root := writeProject(t, map[string]string{"CMakeLists.txt": "project(app CXX)\n"})
+    runLog := filepath.Join(t.TempDir(), "run.log")
+    step := func(name, status string) string {
+        return "sh -c 'echo " + name + " >> " + runLog + "; exit " + status + "'"
+    }
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot:      root,
+        OverrideCommands: map[string]string{configureValidator: step("configure", "0"), "build": step("build", "0"), "test": step("test", "0")},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    if !resp.Success || validatorOrder(resp) != "configure,build,test" {
+        t.Fatalf("success=%v order=%s, want configure,build,test passing", resp.Success, validatorOrder(resp))
+    }
+    ran, err := os.ReadFile(runLog)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if lines := strings.Fields(string(ran)); len(lines) != 3 || lines[0] != "configure" {
+        t.Errorf("ran %v, want configure first", lines)
+    }
+
+    // A failed configure skips the rest
+    resp, err = ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot:      root,
+        OverrideCommands: map[string]string{configureValidator: step("configure", "1"), "build": step("build", "0"), "test": step("test", "0")},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    for _, name := range []string{"build", "test"} {
+        if result := resultsByName(resp)[name]; !result.GetSkipped() || result.SkipReason != pb.SkipReason_SKIP_REASON_FAIL_FAST {
+            t.Errorf("%s after a failed configure = %v, want skipped", name, result)
+        }
+    }
+}
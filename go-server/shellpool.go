@@ -0,0 +1,305 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "log/slog"
+    "os"
+    "os/exec"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+)
+
+// Warm shell pool
+//
+// Login-shell validators pay for sourcing the user's profile on every run,
+// which dominates the cost of short checks. The pool keeps a few `bash -l`
+// processes started ahead of time, each blocked reading its script from
+// stdin with the profile already loaded: a shell joins the pool only once
+// it has run a first line that signals on a spare pipe, and so only after
+// its profile finished. A validator takes one, sends
+// "cd <dir>; export <env>; eval <command>" and closes stdin.
+//
+// Shells are single-use: a shell that ran a command may have changed its
+// directory, exports, traps or left background jobs behind, so it is never
+// handed out again and the pool forks a replacement instead. The working
+// directory and environment therefore always start from a clean state.
+//
+// Anything that must be configured before the process starts cannot use the
+// pool, because pooled shells were forked before the request arrived:
+// sandboxed execution (including VALIDATOR_COMMAND_PREFIX wrappers), cgroup
+// placement, per-run resource limits and running as a different user all
+// need a fresh process. Such modes must bypass
+// warmShellPool.take. Streamed runs bypass it too, since a pooled shell's
+// output destination is also fixed at fork.
+
+// warmShell is a login shell waiting for its script
+type warmShell struct {
+    cmd    *exec.Cmd
+    stdin  io.WriteCloser
+    output *shellOutput
+    split  *splitOutput
+}
+
+// shellOutput buffers a pooled shell's output and copies it to a tap that
+// is installed once the shell is handed out
+type shellOutput struct {
+    mu  sync.Mutex
+    buf tailBuffer
+    tap io.Writer
+}
+
+func (o *shellOutput) Write(p []byte) (int, error) {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    o.buf.Write(p)
+    if o.tap != nil {
+        o.tap.Write(p)
+    }
+    return len(p), nil
+}
+
+func (o *shellOutput) setTap(tap io.Writer) {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    o.tap = tap
+}
+
+func (o *shellOutput) String() string {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    return o.buf.String()
+}
+
+func (o *shellOutput) truncated() bool {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    return o.buf.truncated()
+}
+
+// Refill backoff: a shell that fails to start is retried after
+// refillRetryMin, doubling up to refillRetryMax, so a transient failure
+// (fork limit, bash briefly missing) does not shrink the pool for good
+const (
+    refillRetryMin = 100 * time.Millisecond
+    refillRetryMax = 30 * time.Second
+)
+
+// shellReadyTimeout bounds how long a new shell may take to load its profile
+const shellReadyTimeout = time.Minute
+
+// warmShellPool hands out pre-forked login shells
+type warmShellPool struct {
+    shells    chan *warmShell
+    maxOutput int
+
+    mu     sync.Mutex
+    closed bool
+    done   chan struct{} // closed by close to stop refills
+}
+
+// newWarmShellPool starts size shells in the background, each capturing at
+// most maxOutput bytes per output; size <= 0 disables pooling
+func newWarmShellPool(size, maxOutput int) *warmShellPool {
+    if size <= 0 {
+        return nil
+    }
+    p := &warmShellPool{shells: make(chan *warmShell, size), maxOutput: maxOutput, done: make(chan struct{})}
+    for i := 0; i < size; i++ {
+        go p.refill()
+    }
+    return p
+}
+
+// refill forks one shell into the pool, retrying with backoff until it
+// starts or the pool is closed
+func (p *warmShellPool) refill() {
+    for delay := refillRetryMin; ; delay = min(2*delay, refillRetryMax) {
+        select {
+        case <-p.done:
+            return
+        default:
+        }
+        shell, err := p.fork()
+        if err == nil {
+            p.put(shell)
+            return
+        }
+        slog.Warn("warm shell pool: fork failed", "error", err, "retry_in", delay.String())
+        select {
+        case <-p.done:
+            return
+        case <-time.After(delay):
+        }
+    }
+}
+
+// fork starts a login shell and waits until it has loaded its profile and
+// is waiting for its script
+func (p *warmShellPool) fork() (*warmShell, error) {
+    cmd := exec.Command("bash", "-l")
+    output := &shellOutput{buf: tailBuffer{max: p.maxOutput}}
+    split := newSplitOutput(output, p.maxOutput)
+    split.attach(cmd)
+    startProcessGroup(cmd)
+
+    stdin, err := cmd.StdinPipe()
+    if err != nil {
+        return nil, err
+    }
+    ready, readyWriter, err := os.Pipe()
+    if err != nil {
+        return nil, err
+    }
+    defer ready.Close()
+    cmd.ExtraFiles = []*os.File{readyWriter}
+    err = cmd.Start()
+    readyWriter.Close()
+    if err != nil {
+        return nil, fmt.Errorf("failed to start shell: %w", err)
+    }
+    shell := &warmShell{cmd: cmd, stdin: stdin, output: output, split: split}
+
+    // bash runs the first line after the profile; it signals on fd 3 and
+    // closes it so validators do not inherit the pipe
+    if _, err := io.WriteString(stdin, "printf . >&3; exec 3>&-\n"); err != nil {
+        shell.kill()
+        return nil, fmt.Errorf("failed to start shell: %w", err)
+    }
+    ready.SetReadDeadline(time.Now().Add(shellReadyTimeout))
+    if _, err := ready.Read(make([]byte, 1)); err != nil {
+        shell.kill()
+        return nil, fmt.Errorf("shell did not load its profile: %w", err)
+    }
+    return shell, nil
+}
+
+// put adds shell to the pool, or kills it when the pool is closed. There
+// is one refill per shell taken, so the channel always has room.
+func (p *warmShellPool) put(shell *warmShell) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if p.closed {
+        shell.kill()
+        return
+    }
+    p.shells <- shell
+}
+
+// close stops refilling and kills the idle shells; shells already handed
+// out finish their validator. It is safe to call on a nil pool and more
+// than once.
+func (p *warmShellPool) close() {
+    if p == nil {
+        return
+    }
+    p.mu.Lock()
+    if !p.closed {
+        p.closed = true
+        close(p.done)
+    }
+    p.mu.Unlock()
+    for {
+        select {
+        case shell := <-p.shells:
+            shell.kill()
+        default:
+            return
+        }
+    }
+}
+
+// take returns a warm shell for spec, or nil when the pool is disabled,
+// empty, or the validator cannot run in a pooled shell
+func (p *warmShellPool) take(spec *validatorSpec) *warmShell {
+    if p == nil || !spec.loginShell || spec.stream != nil || spec.stdin != nil || spec.stdinFile != "" || len(spec.prefix) > 0 {
+        return nil
+    }
+    for k := range spec.env {
+        if !isShellName(k) {
+            return nil
+        }
+    }
+
+    select {
+    case shell := <-p.shells:
+        go p.refill()
+        return shell
+    default:
+        return nil
+    }
+}
+
+// kill ends an idle shell and reaps it
+func (w *warmShell) kill() {
+    w.stdin.Close()
+    killProcessGroup(w.cmd)
+    w.cmd.Wait()
+}
+
+// run sends the validator to the shell and waits for it, killing the
+// shell's process group when ctx ends. Output is also copied to tap when
+// it is non-nil.
+func (w *warmShell) run(ctx context.Context, spec *validatorSpec, tap io.Writer) (string, error) {
+    if tap != nil {
+        w.output.setTap(tap)
+    }
+    if _, err := io.WriteString(w.stdin, w.script(spec)); err != nil {
+        killProcessGroup(w.cmd)
+        w.cmd.Wait()
+        return "", err
+    }
+    w.stdin.Close()
+
+    done := make(chan struct{})
+    go func() {
+        select {
+        case <-ctx.Done():
+            killProcessGroup(w.cmd)
+        case <-done:
+        }
+    }()
+    err := w.cmd.Wait()
+    close(done)
+
+    if ctx.Err() != nil && err != nil {
+        err = ctx.Err()
+    }
+    return w.output.String(), err
+}
+
+// script renders the commands that set up and run the validator
+func (w *warmShell) script(spec *validatorSpec) string {
+    var b strings.Builder
+    b.WriteString("cd -- " + shellQuote(spec.workDir) + " || exit 126\n")
+
+    keys := make([]string, 0, len(spec.env))
+    for k := range spec.env {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    for _, k := range keys {
+        b.WriteString("export " + shellQuote(k+"="+spec.env[k]) + "\n")
+    }
+
+    // Detach stdin so the command cannot read the rest of the script
+    b.WriteString("eval " + shellQuote(spec.command) + " </dev/null\n")
+    return b.String()
+}
+
+// isShellName reports whether name is a valid shell variable name
+func isShellName(name string) bool {
+    if name == "" {
+        return false
+    }
+    for i, c := range name {
+        if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (i > 0 && c >= '0' && c <= '9') {
+            continue
+        }
+        return false
+    }
+    return true
+}
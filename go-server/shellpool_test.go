@@ -0,0 +1,102 @@
+package main
+
+import (
+    "context"
+    "os"
+    "strings"
+    "testing"
+    "time"
+)
+
+// waitForShells waits until the pool holds n idle shells
+func waitForShells(tb testing.TB, p *warmShellPool, n int) {
+    tb.Helper()
+    deadline := time.Now().Add(10 * time.Second)
+    for len(p.shells) < n {
+        if time.Now().After(deadline) {
+            tb.Fatalf("pool holds %d shells, want %d", len(p.shells), n)
+        }
+        time.Sleep(5 * time.Millisecond)
+    }
+}
+
+func TestWarmShellPoolRunsValidator(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("WARM_SHELL_POOL_SIZE", "1")
+    s := NewCCToolsServer()
+    defer s.shellPool.close()
+    waitForShells(t, s.shellPool, 1)
+
+    spec := &validatorSpec{name: "lint", command: "echo pooled $GREETING", loginShell: true, workDir: t.TempDir(), timeout: 10 * time.Second, env: map[string]string{"GREETING": "hi"}}
+    result := s.executeValidator(context.Background(), spec)
+    if !result.Success || !strings.Contains(result.Output, "pooled hi") {
+        t.Errorf("result = %v, want the pooled shell's output", result)
+    }
+    // The used shell is replaced
+    waitForShells(t, s.shellPool, 1)
+}
+
+func TestWarmShellPoolRetriesFailedRefill(t *testing.T) {
+    path := os.Getenv("PATH")
+    t.Setenv("PATH", t.TempDir())
+    p := newWarmShellPool(1, 0)
+    defer p.close()
+
+    // bash cannot be found, so the first attempts fail and are retried
+    time.Sleep(3 * refillRetryMin)
+    if len(p.shells) != 0 {
+        t.Fatal("a shell started without bash on PATH")
+    }
+    os.Setenv("PATH", path)
+    waitForShells(t, p, 1)
+}
+
+func TestWarmShellPoolCloseKillsIdleShells(t *testing.T) {
+    p := newWarmShellPool(2, 0)
+    waitForShells(t, p, 2)
+    idle := []*warmShell{<-p.shells, <-p.shells}
+    for _, shell := range idle {
+        p.shells <- shell
+    }
+
+    p.close()
+    if len(p.shells) != 0 {
+        t.Errorf("%d shells left in a closed pool", len(p.shells))
+    }
+    for _, shell := range idle {
+        if shell.cmd.ProcessState == nil {
+            t.Errorf("shell %d still running after close", shell.cmd.Process.Pid)
+        }
+    }
+    // Closing twice and taking from a closed pool are harmless
+    p.close()
+    if shell := p.take(&validatorSpec{loginShell: true}); shell != nil {
+        t.Error("take returned a shell from a closed pool")
+    }
+}
+
+// benchmarkValidate measures a small login-shell lint, the case the warm
+// pool is for. With a pool, each iteration waits untimed for a warm shell.
+func benchmarkValidate(b *testing.B, poolSize string) {
+    b.Setenv("ALLOWED_COMMANDS", "*")
+    b.Setenv("WARM_SHELL_POOL_SIZE", poolSize)
+    s := NewCCToolsServer()
+    defer s.shellPool.close()
+    dir := b.TempDir()
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        if s.shellPool != nil {
+            b.StopTimer()
+            waitForShells(b, s.shellPool, 1)
+            b.StartTimer()
+        }
+        spec := &validatorSpec{name: "lint", command: "true", loginShell: true, workDir: dir, timeout: 10 * time.Second}
+        if result := s.executeValidator(context.Background(), spec); !result.Success {
+            b.Fatalf("lint failed: %v", result)
+        }
+    }
+}
+
+func BenchmarkValidatePooled(b *testing.B)   { benchmarkValidate(b, "2") }
+func BenchmarkValidateUnpooled(b *testing.B) { benchmarkValidate(b, "0") }
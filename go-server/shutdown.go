@@ -0,0 +1,104 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "log/slog"
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+
+    "google.golang.org/grpc"
+    health "google.golang.org/grpc/health"
+)
+
+// Shutdown policy
+//
+// SHUTDOWN_POLICY decides what happens to running validations when the
+// server is asked to stop:
+//
+//   - DRAIN (default): stop accepting RPCs and let in-flight validations
+//     finish, for at most SHUTDOWN_TIMEOUT_SECONDS (formerly
+//     SHUTDOWN_DRAIN_TIMEOUT_SECONDS, still honored). Whatever is still
+//     running then is killed.
+//   - KILL: kill every running validation at once, then stop. Useful when
+//     the node is being reclaimed and waiting buys nothing.
+//
+// Either way the health service reports NOT_SERVING first, so load
+// balancers stop routing to the instance while it drains. Killed
+// validators fail with FAILURE_REASON_ABORTED, so clients that are still
+// connected learn why.
+
+// shutdownPolicy is the parsed SHUTDOWN_POLICY
+type shutdownPolicy string
+
+const (
+    shutdownDrain shutdownPolicy = "DRAIN"
+    shutdownKill  shutdownPolicy = "KILL"
+)
+
+// loadShutdownPolicy reads SHUTDOWN_POLICY, defaulting to DRAIN
+func loadShutdownPolicy() shutdownPolicy {
+    switch policy := shutdownPolicy(strings.ToUpper(envString("SHUTDOWN_POLICY", string(shutdownDrain)))); policy {
+    case shutdownDrain, shutdownKill:
+        return policy
+    default:
+        slog.Warn("Unknown SHUTDOWN_POLICY", "policy", policy, "using", shutdownDrain)
+        return shutdownDrain
+    }
+}
+
+// shutdown stops grpcServer, and the HTTP gateway when there is one,
+// according to the configured policy
+func (s *CCToolsServer) shutdown(grpcServer *grpc.Server, hs *health.Server, gateway *http.Server) {
+    policy := loadShutdownPolicy()
+    timeout := time.Duration(envInt("SHUTDOWN_TIMEOUT_SECONDS", envInt("SHUTDOWN_DRAIN_TIMEOUT_SECONDS", 30))) * time.Second
+    running := s.jobs.count()
+    slog.Info("Shutting down", "policy", policy, "drain_timeout", timeout.String(), "running_validations", running)
+    // NOT_SERVING for good: later readiness checks cannot flip it back
+    hs.Shutdown()
+
+    if policy == shutdownKill {
+        killed := s.jobs.cancelAll(errShutdown)
+        slog.Info("Shutdown: killed running validations", "count", killed)
+    }
+
+    // Both listeners drain at once; a gateway request is a validation like
+    // any other
+    var draining sync.WaitGroup
+    draining.Add(1)
+    go func() {
+        defer draining.Done()
+        grpcServer.GracefulStop()
+    }()
+    if gateway != nil {
+        draining.Add(1)
+        go func() {
+            defer draining.Done()
+            if err := gateway.Shutdown(context.Background()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+                slog.Warn("Shutdown: HTTP gateway", "error", err)
+            }
+        }()
+    }
+    stopped := make(chan struct{})
+    go func() {
+        draining.Wait()
+        close(stopped)
+    }()
+
+    // Idle warm shells would otherwise outlive the server
+    s.shellPool.close()
+
+    select {
+    case <-stopped:
+        slog.Info("Shutdown: complete; all in-flight RPCs finished")
+    case <-time.After(timeout):
+        killed := s.jobs.cancelAll(errShutdown)
+        grpcServer.Stop()
+        if gateway != nil {
+            gateway.Close()
+        }
+        slog.Warn("Shutdown: drain timeout; killed running validations", "drain_timeout", timeout.String(), "count", killed)
+    }
+}
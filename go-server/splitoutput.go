@@ -0,0 +1,57 @@
+package main
+
+import (
+    "io"
+    "os/exec"
+    "sync"
+)
+
+// splitOutput records a command's stdout and stderr separately while
+// passing both, in arrival order, to a combined writer. exec copies the two
+// streams from separate goroutines; the lock serializes them so every
+// write lands whole in the combined output and order within each stream is
+// preserved. Each stream keeps only its last max bytes (see tailBuffer).
+type splitOutput struct {
+    mu       sync.Mutex
+    combined io.Writer
+    stdout   tailBuffer
+    stderr   tailBuffer
+}
+
+// newSplitOutput records streams capped at max bytes each, copying both to combined
+func newSplitOutput(combined io.Writer, max int) *splitOutput {
+    return &splitOutput{combined: combined, stdout: tailBuffer{max: max}, stderr: tailBuffer{max: max}}
+}
+
+// attach points cmd's stdout and stderr at o
+func (o *splitOutput) attach(cmd *exec.Cmd) {
+    cmd.Stdout = splitWriter{o, &o.stdout}
+    cmd.Stderr = splitWriter{o, &o.stderr}
+}
+
+// streams returns what was written to stdout and stderr so far
+func (o *splitOutput) streams() (string, string) {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    return o.stdout.String(), o.stderr.String()
+}
+
+// truncated reports whether either stream exceeded the cap
+func (o *splitOutput) truncated() bool {
+    o.mu.Lock()
+    defer o.mu.Unlock()
+    return o.stdout.truncated() || o.stderr.truncated()
+}
+
+// splitWriter is one stream of a splitOutput
+type splitWriter struct {
+    o   *splitOutput
+    buf *tailBuffer
+}
+
+func (w splitWriter) Write(p []byte) (int, error) {
+    w.o.mu.Lock()
+    defer w.o.mu.Unlock()
+    w.buf.Write(p)
+    return w.o.combined.Write(p)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestExecuteValidatorSeparatesStreams(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("WARM_SHELL_POOL_SIZE", "1")
+    s := NewCCToolsServer()
+    defer s.shellPool.close()
+    script := "echo out1; echo err1 >&2; echo out2; echo err2 >&2"
+
+    t.Run("direct", func(t *testing.T) {
+        result := s.executeValidator(t.Context(), shellSpec(t, "test", script))
+        if result.Stdout != "out1\nout2\n" || result.Stderr != "err1\nerr2\n" {
+            t.Errorf("stdout=%q stderr=%q", result.Stdout, result.Stderr)
+        }
+        for _, line := range []string{"out1", "out2", "err1", "err2"} {
+            if !strings.Contains(result.Output, line) {
+                t.Errorf("combined output %q has no %s", result.Output, line)
+            }
+        }
+    })
+
+    // A pooled shell splits the streams too; its profile may print first
+    t.Run("pooled", func(t *testing.T) {
+        waitForShells(t, s.shellPool, 1)
+        spec := &validatorSpec{name: "test", command: script, loginShell: true, workDir: t.TempDir(), timeout: 10 * time.Second}
+        result := s.executeValidator(t.Context(), spec)
+        if !strings.HasSuffix(result.Stdout, "out1\nout2\n") || strings.Contains(result.Stdout, "err") {
+            t.Errorf("stdout = %q, want only out1 and out2", result.Stdout)
+        }
+        if !strings.HasSuffix(result.Stderr, "err1\nerr2\n") || strings.Contains(result.Stderr, "out") {
+            t.Errorf("stderr = %q, want only err1 and err2", result.Stderr)
+        }
+    })
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+    "errors"
+    "io/fs"
+    "os/exec"
+    "syscall"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Exit codes a shell uses when it could not run the command it was given
+const (
+    shellExitNotExecutable = 126
+    shellExitNotFound      = 127
+)
+
+// startFailureReason classifies why cmd never started, or returns
+// UNSPECIFIED when it ran (successfully or not)
+func startFailureReason(cmd *exec.Cmd, err error) pb.StartFailureReason {
+    if err == nil || cmd.Process != nil {
+        return pb.StartFailureReason_START_FAILURE_REASON_UNSPECIFIED
+    }
+
+    var pathErr *fs.PathError
+    switch {
+    case errors.As(err, &pathErr) && pathErr.Op == "chdir":
+        return pb.StartFailureReason_START_FAILURE_REASON_BAD_WORKING_DIR
+    case errors.Is(err, exec.ErrNotFound), errors.Is(err, fs.ErrNotExist):
+        return pb.StartFailureReason_START_FAILURE_REASON_NOT_FOUND
+    case errors.Is(err, fs.ErrPermission):
+        return pb.StartFailureReason_START_FAILURE_REASON_PERMISSION_DENIED
+    case errors.Is(err, syscall.ENOEXEC):
+        return pb.StartFailureReason_START_FAILURE_REASON_NOT_EXECUTABLE
+    default:
+        return pb.StartFailureReason_START_FAILURE_REASON_OTHER
+    }
+}
+
+// shellStartFailureReason maps the exit codes bash reserves for "command
+// not found" and "not executable" to start failures. Login-shell commands
+// that exit 126 or 127 on their own are reported the same way.
+func shellStartFailureReason(exitCode int) pb.StartFailureReason {
+    switch exitCode {
+    case shellExitNotFound:
+        return pb.StartFailureReason_START_FAILURE_REASON_NOT_FOUND
+    case shellExitNotExecutable:
+        return pb.StartFailureReason_START_FAILURE_REASON_NOT_EXECUTABLE
+    default:
+        return pb.StartFailureReason_START_FAILURE_REASON_UNSPECIFIED
+    }
+}
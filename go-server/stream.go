@@ -0,0 +1,361 @@
+package main
+
+import (
+    "bytes"
+    "log/slog"
+    "os"
+    "strings"
+    "sync"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Default StreamValidation flush policy: output lines are sent once this
+// many accumulate or this much time passes, whichever comes first
+const (
+    defaultStreamFlushLines      = 20
+    defaultStreamFlushIntervalMs = 100
+)
+
+// defaultStreamClientBuffer is how many events may wait for a slow client
+// before further output is dropped from its stream
+const defaultStreamClientBuffer = 256
+
+// defaultStreamProgressIntervalMs is how often a running validator reports progress
+const defaultStreamProgressIntervalMs = 5000
+
+// defaultOutputPrefixFormat is prepended to streamed lines with prefix_output.
+// {validator} expands to the validator name and {run} to the run's job id.
+const defaultOutputPrefixFormat = "[{validator}] "
+
+// StreamValidation runs a validation like ValidateProject but streams each
+// validator's output while it runs. Every validator ends with an event
+// carrying its result; the last event carries the full response.
+// Cancelling the stream kills the validators still running.
+//
+// Output is teed: a raw copy goes to the run's persisted log (see
+// validationLogs) and the other to the client. Events are handed to the
+// client through a bounded queue, so a slow reader never stalls a
+// validator; output that does not fit is dropped from the stream only,
+// counted in dropped_lines, and stays available from GetValidationLog.
+func (s *CCToolsServer) StreamValidation(req *pb.ValidationRequest, srv pb.CCToolsIntegration_StreamValidationServer) error {
+    stream := &validationStream{
+        srv:              srv,
+        queue:            make(chan *pb.ValidationEvent, max(1, s.streamClientBuffer)),
+        delivered:        make(chan struct{}),
+        logs:             s.validationLogs,
+        flushLines:       int(req.StreamFlushLines),
+        flushInterval:    time.Duration(req.StreamFlushIntervalMs) * time.Millisecond,
+        progressInterval: time.Duration(req.StreamProgressIntervalMs) * time.Millisecond,
+        history:          s.history,
+        projectRoot:      req.ProjectRoot,
+    }
+    if req.PrefixOutput {
+        stream.prefixFormat = req.OutputPrefixFormat
+        if stream.prefixFormat == "" {
+            stream.prefixFormat = s.outputPrefixFormat
+        }
+    }
+    if stream.flushLines <= 0 {
+        stream.flushLines = s.streamFlushLines
+    }
+    if stream.flushInterval <= 0 {
+        stream.flushInterval = s.streamFlushInterval
+    }
+    if stream.progressInterval <= 0 {
+        stream.progressInterval = s.progressInterval
+    }
+
+    go stream.deliver()
+    resp, err := s.validateProject(srv.Context(), req, stream)
+    if err == nil {
+        stream.send(&pb.ValidationEvent{Response: resp})
+    }
+    close(stream.queue)
+    <-stream.delivered
+    if err != nil {
+        return err
+    }
+    return stream.sendErr
+}
+
+// validationStream sends the events of one StreamValidation call
+type validationStream struct {
+    srv           pb.CCToolsIntegration_StreamValidationServer
+    flushLines    int
+    flushInterval time.Duration
+
+    // progressInterval paces progress events; estimates come from the
+    // median duration recorded in history for the project's validators
+    progressInterval time.Duration
+    history          *validationHistory
+    projectRoot      string
+
+    // prefixFormat is prepended to each streamed line (see
+    // defaultOutputPrefixFormat); empty sends lines as produced. Only the
+    // streamed copy is prefixed, results always carry the raw output.
+    prefixFormat string
+    runID        string
+
+    // logs persists each validator's raw output; nil when disabled
+    logs *validationLogs
+
+    // queue feeds deliver, the only goroutine calling Send; delivered is
+    // closed once it has drained the queue. sendErr is the first Send
+    // failure, after which events are discarded.
+    queue     chan *pb.ValidationEvent
+    delivered chan struct{}
+    sendErr   error
+}
+
+// setRunID records the run's job id, sent on every event and used for
+// {run} in the output prefix and to name the persisted log
+func (vs *validationStream) setRunID(id string) {
+    if vs != nil {
+        vs.runID = id
+    }
+}
+
+// deliver sends queued events to the client until the queue is closed
+func (vs *validationStream) deliver() {
+    defer close(vs.delivered)
+    for event := range vs.queue {
+        if vs.sendErr == nil {
+            vs.sendErr = vs.srv.Send(event)
+        }
+    }
+}
+
+// send queues an event, waiting for room; used for results and the final
+// response, which are sent after the validator has exited
+func (vs *validationStream) send(event *pb.ValidationEvent) {
+    event.RunId = vs.runID
+    vs.queue <- event
+}
+
+// offer queues an event unless the client has fallen behind, in which case
+// it reports false and the event is dropped
+func (vs *validationStream) offer(event *pb.ValidationEvent) bool {
+    event.RunId = vs.runID
+    select {
+    case vs.queue <- event:
+        return true
+    default:
+        return false
+    }
+}
+
+// attach routes the validator's output to a new batcher and its persisted
+// log and starts its progress events; nil-safe so non-streaming runs pass
+// a nil stream
+func (vs *validationStream) attach(spec *validatorSpec) *outputBatcher {
+    if vs == nil {
+        return nil
+    }
+    var b *outputBatcher
+    b = newOutputBatcher(vs.flushLines, vs.flushInterval, func(lines []string) error {
+        // Called with b.mu held, which guards b.dropped
+        if vs.offer(&pb.ValidationEvent{Validator: spec.name, Lines: lines, DroppedLines: b.dropped}) {
+            b.dropped = 0
+        } else {
+            b.dropped += int64(len(lines))
+        }
+        return nil
+    })
+    if vs.logs != nil {
+        if f, err := vs.logs.create(vs.runID, spec.name); err != nil {
+            slog.Warn("StreamValidation: not persisting output", "validator", spec.name, "run_id", vs.runID, "error", err)
+        } else {
+            spec.log = f
+            b.logFile = f
+        }
+    }
+    spec.stream = b.add
+    if vs.prefixFormat != "" {
+        prefix := strings.NewReplacer("{validator}", spec.name, "{run}", vs.runID).Replace(vs.prefixFormat)
+        spec.stream = func(line string) { b.add(prefix + line) }
+    }
+    b.stopProgress = vs.reportProgress(spec.name)
+    return b
+}
+
+// reportProgress sends a progress event for validator every progressInterval
+// until the returned stop function is called. stop waits for the reporter
+// to exit so no progress event follows the validator's result.
+func (vs *validationStream) reportProgress(validator string) func() {
+    typical, known := vs.history.p50(vs.projectRoot, validator)
+    start := time.Now()
+    ticker := time.NewTicker(vs.progressInterval)
+    done := make(chan struct{})
+    stopped := make(chan struct{})
+    go func() {
+        defer close(stopped)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-done:
+                return
+            case <-ticker.C:
+            }
+            progress := &pb.Progress{ElapsedMs: time.Since(start).Milliseconds()}
+            if known && typical > 0 {
+                progress.TypicalMs = typical
+                // Cap below 100 so an overrunning validator never looks finished
+                progress.PercentEstimate = int32(min(99, progress.ElapsedMs*100/typical))
+            }
+            // Progress is advisory: a client that is behind simply misses it
+            vs.offer(&pb.ValidationEvent{Validator: validator, Progress: progress})
+        }
+    }()
+    return func() {
+        close(done)
+        <-stopped
+    }
+}
+
+// finish flushes any buffered output and sends the validator's result
+func (vs *validationStream) finish(b *outputBatcher, result *pb.ValidationResult) {
+    if vs == nil {
+        return
+    }
+    b.close()
+    // A failed send means the client is gone; the run continues regardless
+    vs.send(&pb.ValidationEvent{Validator: result.Validator, Result: result, DroppedLines: b.droppedLines()})
+}
+
+// outputBatcher groups output lines and sends them when maxLines have
+// accumulated or interval has elapsed since the first unsent line
+type outputBatcher struct {
+    mu       sync.Mutex
+    lines    []string
+    maxLines int
+    send     func([]string) error
+    err      error // first send error; later sends are dropped
+
+    interval time.Duration
+    timer    *time.Timer
+    closed   bool
+
+    // stopProgress ends the validator's progress events
+    stopProgress func()
+
+    // dropped counts lines the client missed since the last delivered batch
+    dropped int64
+
+    // logFile is the validator's persisted log, closed with the batcher
+    logFile *os.File
+}
+
+func newOutputBatcher(maxLines int, interval time.Duration, send func([]string) error) *outputBatcher {
+    return &outputBatcher{maxLines: maxLines, interval: interval, send: send}
+}
+
+// add buffers one line, flushing if the batch is full
+func (b *outputBatcher) add(line string) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    if b.closed {
+        return
+    }
+
+    b.lines = append(b.lines, line)
+    if len(b.lines) >= b.maxLines {
+        b.flushLocked()
+        return
+    }
+    if b.timer == nil {
+        b.timer = time.AfterFunc(b.interval, b.flushTimer)
+    }
+}
+
+func (b *outputBatcher) flushTimer() {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    if !b.closed {
+        b.flushLocked()
+    }
+}
+
+// close sends whatever is still buffered and stops the batcher
+func (b *outputBatcher) close() {
+    if b == nil {
+        return
+    }
+    if b.stopProgress != nil {
+        b.stopProgress()
+    }
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    b.flushLocked()
+    b.closed = true
+    if b.logFile != nil {
+        b.logFile.Close()
+    }
+}
+
+// droppedLines returns how many lines never reached the client; nil-safe
+func (b *outputBatcher) droppedLines() int64 {
+    if b == nil {
+        return 0
+    }
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.dropped
+}
+
+func (b *outputBatcher) flushLocked() {
+    if b.timer != nil {
+        b.timer.Stop()
+        b.timer = nil
+    }
+    if len(b.lines) == 0 {
+        return
+    }
+    lines := b.lines
+    b.lines = nil
+    if b.err == nil {
+        b.err = b.send(lines)
+    }
+}
+
+// lineWriter captures command output and reports each complete line to fn
+type lineWriter struct {
+    mu      sync.Mutex
+    output  tailBuffer
+    partial []byte
+    fn      func(string)
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+
+    w.output.Write(p)
+    w.partial = append(w.partial, p...)
+    for {
+        i := bytes.IndexByte(w.partial, '\n')
+        if i < 0 {
+            break
+        }
+        w.fn(string(bytes.TrimSuffix(w.partial[:i], []byte("\r"))))
+        w.partial = w.partial[i+1:]
+    }
+    // A line longer than the output cap is reported in pieces rather than
+    // held whole
+    if w.output.max > 0 && len(w.partial) >= w.output.max {
+        w.fn(string(w.partial))
+        w.partial = nil
+    }
+    return len(p), nil
+}
+
+// flush reports a trailing line without a newline
+func (w *lineWriter) flush() {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if len(w.partial) > 0 {
+        w.fn(string(w.partial))
+        w.partial = nil
+    }
+}
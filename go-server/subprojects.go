@@ -0,0 +1,115 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "path"
+    "path/filepath"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Sub-project discovery
+//
+// DiscoverSubProjects finds the projects inside a monorepo. It walks the
+// tree breadth-first down to max_depth levels below project_root (default
+// and cap SUBPROJECT_MAX_DEPTH, 4), skipping markerSkipDirs, and runs
+// detection in every directory that directly holds a marker file of an
+// enabled detector. Discovery stops after max_results projects (default
+// and cap SUBPROJECT_MAX_RESULTS, 200) and reports that it was truncated.
+
+const (
+    defaultSubprojectMaxDepth   = 4
+    defaultSubprojectMaxResults = 200
+)
+
+// DiscoverSubProjects lists the projects under project_root, the root
+// itself included when it is one
+func (s *CCToolsServer) DiscoverSubProjects(ctx context.Context, req *pb.DiscoverSubProjectsRequest) (*pb.DiscoverSubProjectsResponse, error) {
+    maxDepth := clampLimit(int(req.MaxDepth), s.subprojectDepth)
+    maxResults := clampLimit(int(req.MaxResults), s.subprojectLimit)
+    markers := s.subprojectMarkers()
+
+    resp := &pb.DiscoverSubProjectsResponse{}
+    level := []string{"."}
+    for depth := 0; depth <= maxDepth && len(level) > 0; depth++ {
+        var next []string
+        for _, dir := range level {
+            if err := ctx.Err(); err != nil {
+                return nil, err
+            }
+            entries, err := os.ReadDir(filepath.Join(req.ProjectRoot, dir))
+            if err != nil {
+                if dir == "." {
+                    return nil, err
+                }
+                resp.Warnings = append(resp.Warnings, err.Error())
+                continue
+            }
+
+            if holdsMarker(entries, markers) {
+                if len(resp.Projects) == maxResults {
+                    resp.Truncated = true
+                    return resp, nil
+                }
+                if project := s.detectSubProject(req.ProjectRoot, dir); project != nil {
+                    resp.Projects = append(resp.Projects, project)
+                }
+            }
+            for _, entry := range entries {
+                if entry.IsDir() && !markerSkipDirs[entry.Name()] {
+                    next = append(next, path.Join(dir, entry.Name()))
+                }
+            }
+        }
+        level = next
+    }
+    return resp, nil
+}
+
+// detectSubProject runs detection in dir. A deeper MARKER_SEARCH_DEPTH can
+// make detection settle on a marker below dir; that project is reported
+// from its own directory instead, so nil is returned.
+func (s *CCToolsServer) detectSubProject(projectRoot, dir string) *pb.SubProject {
+    metadata, err := s.detectProjectMetadata(filepath.Join(projectRoot, dir))
+    if err != nil {
+        metadata = &pb.ProjectMetadata{ProjectType: "unknown", Warnings: []string{fmt.Sprintf("detection failed: %v", err)}}
+    }
+    if metadata.ProjectType == "unknown" || metadata.MarkerDir != "." {
+        return nil
+    }
+    return &pb.SubProject{Path: dir, Metadata: metadata}
+}
+
+// subprojectMarkers are the marker files of the enabled detectors
+func (s *CCToolsServer) subprojectMarkers() map[string]bool {
+    markers := make(map[string]bool)
+    for _, d := range detectors {
+        if s.disabledDetectors[d.name()] {
+            continue
+        }
+        for _, marker := range d.descriptor().ConfigFiles {
+            markers[marker] = true
+        }
+    }
+    return markers
+}
+
+// holdsMarker reports whether a directory listing contains a marker file
+func holdsMarker(entries []os.DirEntry, markers map[string]bool) bool {
+    for _, entry := range entries {
+        if !entry.IsDir() && markers[entry.Name()] {
+            return true
+        }
+    }
+    return false
+}
+
+// clampLimit returns requested, or max when requested is unset or above it
+func clampLimit(requested, max int) int {
+    if requested <= 0 || requested > max {
+        return max
+    }
+    return requested
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+    "context"
+    "strings"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// monorepo is a fixture tree with npm and cargo packages at several depths,
+// plus packages hidden in directories discovery must skip
+func monorepo(t *testing.T) string {
+    t.Helper()
+    return writeProject(t, map[string]string{
+        "package.json":                               `{"name": "root", "workspaces": ["packages/*"]}`,
+        "packages/web/package.json":                  `{"name": "web"}`,
+        "packages/api/Cargo.toml":                    "[package]\nname = \"api\"\n",
+        "packages/api/crates/core/Cargo.toml":        "[package]\nname = \"core\"\n",
+        "packages/web/node_modules/dep/package.json": `{"name": "dep"}`,
+        "packages/api/target/pkg/Cargo.toml":         "[package]\nname = \"built\"\n",
+        "vendor/lib/package.json":                    `{"name": "vendored"}`,
+        ".git/hooks/package.json":                    "{}",
+        "docs/README.md":                             "no marker here",
+    })
+}
+
+// subProjects renders a response as "path=type" entries in order
+func subProjects(resp *pb.DiscoverSubProjectsResponse) string {
+    entries := make([]string, 0, len(resp.Projects))
+    for _, project := range resp.Projects {
+        entries = append(entries, project.Path+"="+project.Metadata.GetProjectType())
+    }
+    return strings.Join(entries, ",")
+}
+
+func TestDiscoverSubProjects(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    root := monorepo(t)
+    ctx := context.Background()
+
+    tests := []struct {
+        name       string
+        maxDepth   int32
+        maxResults int32
+        want       string
+        truncated  bool
+    }{
+        {name: "whole tree", want: ".=npm,packages/api=cargo,packages/web=npm,packages/api/crates/core=cargo"},
+        {name: "depth 2", maxDepth: 2, want: ".=npm,packages/api=cargo,packages/web=npm"},
+        {name: "result cap", maxResults: 2, want: ".=npm,packages/api=cargo", truncated: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            resp, err := ts.client.DiscoverSubProjects(ctx, &pb.DiscoverSubProjectsRequest{ProjectRoot: root, MaxDepth: tt.maxDepth, MaxResults: tt.maxResults})
+            if err != nil {
+                t.Fatalf("DiscoverSubProjects: %v", err)
+            }
+            if got := subProjects(resp); got != tt.want {
+                t.Errorf("projects = %s, want %s", got, tt.want)
+            }
+            if resp.Truncated != tt.truncated {
+                t.Errorf("truncated = %v, want %v", resp.Truncated, tt.truncated)
+            }
+        })
+    }
+
+    // Each entry is detected with its own directory as the root
+    resp, err := ts.client.DiscoverSubProjects(ctx, &pb.DiscoverSubProjectsRequest{ProjectRoot: root})
+    if err != nil {
+        t.Fatalf("DiscoverSubProjects: %v", err)
+    }
+    for _, project := range resp.Projects {
+        if project.Path == "packages/web" && project.Metadata.ProjectName != "web" {
+            t.Errorf("packages/web detected as %q", project.Metadata.ProjectName)
+        }
+    }
+}
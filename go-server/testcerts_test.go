@@ -0,0 +1,123 @@
+package main
+
+import (
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rand"
+    "crypto/tls"
+    "crypto/x509"
+    "crypto/x509/pkix"
+    "encoding/pem"
+    "math/big"
+    "net"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// testPKI is a throwaway CA with a server certificate and the files the
+// TLS_* settings point at
+type testPKI struct {
+    certFile, keyFile, caFile string
+
+    ca    *x509.Certificate
+    caKey *ecdsa.PrivateKey
+    pool  *x509.CertPool
+}
+
+// newTestPKI writes a CA and a server certificate for localhost,
+// 127.0.0.1 and "bufconn" into a temp dir
+func newTestPKI(t *testing.T) *testPKI {
+    t.Helper()
+    dir := t.TempDir()
+    caKey := newTestKey(t)
+    caTemplate := &x509.Certificate{
+        SerialNumber:          big.NewInt(1),
+        Subject:               pkix.Name{CommonName: "test CA"},
+        NotBefore:             time.Now().Add(-time.Hour),
+        NotAfter:              time.Now().Add(time.Hour),
+        IsCA:                  true,
+        BasicConstraintsValid: true,
+        KeyUsage:              x509.KeyUsageCertSign,
+    }
+    caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+    ca, _ := x509.ParseCertificate(caDER)
+    pki := &testPKI{ca: ca, caKey: caKey, pool: x509.NewCertPool()}
+    pki.pool.AddCert(ca)
+    pki.caFile = writePEM(t, filepath.Join(dir, "ca.pem"), "CERTIFICATE", caDER)
+
+    serverDER, serverKey := pki.issue(t, "server", x509.ExtKeyUsageServerAuth)
+    pki.certFile = writePEM(t, filepath.Join(dir, "server.pem"), "CERTIFICATE", serverDER)
+    keyDER, err := x509.MarshalECPrivateKey(serverKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+    pki.keyFile = writePEM(t, filepath.Join(dir, "server-key.pem"), "EC PRIVATE KEY", keyDER)
+    return pki
+}
+
+// clientCert issues a client certificate with the given common name
+func (p *testPKI) clientCert(t *testing.T, commonName string) tls.Certificate {
+    t.Helper()
+    der, key := p.issue(t, commonName, x509.ExtKeyUsageClientAuth)
+    return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+// clientConfig trusts the CA and presents certs, if any
+func (p *testPKI) clientConfig(certs ...tls.Certificate) *tls.Config {
+    return &tls.Config{RootCAs: p.pool, Certificates: certs, ServerName: "localhost"}
+}
+
+func (p *testPKI) issue(t *testing.T, commonName string, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+    t.Helper()
+    key := newTestKey(t)
+    serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+    template := &x509.Certificate{
+        SerialNumber: serial,
+        Subject:      pkix.Name{CommonName: commonName},
+        NotBefore:    time.Now().Add(-time.Hour),
+        NotAfter:     time.Now().Add(time.Hour),
+        KeyUsage:     x509.KeyUsageDigitalSignature,
+        ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+        DNSNames:     []string{"localhost", "bufconn"},
+        IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+    }
+    der, err := x509.CreateCertificate(rand.Reader, template, p.ca, &key.PublicKey, p.caKey)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return der, key
+}
+
+// setEnv points the TLS_* settings at the PKI, with client certificates
+// required when mutual is set
+func (p *testPKI) setEnv(t *testing.T, mutual bool) {
+    t.Setenv("TLS_CERT_FILE", p.certFile)
+    t.Setenv("TLS_KEY_FILE", p.keyFile)
+    if mutual {
+        t.Setenv("TLS_CLIENT_CA_FILE", p.caFile)
+    } else {
+        t.Setenv("TLS_CLIENT_CA_FILE", "")
+    }
+}
+
+func newTestKey(t *testing.T) *ecdsa.PrivateKey {
+    t.Helper()
+    key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+    if err != nil {
+        t.Fatal(err)
+    }
+    return key
+}
+
+func writePEM(t *testing.T, path, blockType string, der []byte) string {
+    t.Helper()
+    if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+        t.Fatal(err)
+    }
+    return path
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
+    "fmt"
+    "os"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/credentials"
+)
+
+// serverCredentials builds the transport security for the gRPC listener
+// from serverTLSConfig. With no cert vars set it returns a nil option for
+// plaintext. The returned mode describes the choice for the startup log.
+func serverCredentials() (grpc.ServerOption, *tls.Config, string, error) {
+    config, mode, err := serverTLSConfig()
+    if err != nil || config == nil {
+        return nil, nil, mode, err
+    }
+    return grpc.Creds(credentials.NewTLS(config)), config, mode, nil
+}
+
+// serverTLSConfig loads TLS_CERT_FILE and TLS_KEY_FILE, adding mutual TLS
+// when TLS_CLIENT_CA_FILE is set: clients must then present a certificate
+// signed by that CA, whose common name becomes their identity (see
+// identityFromContext). It returns nil for plaintext. The gRPC listener and
+// the HTTP gateway share the config.
+func serverTLSConfig() (*tls.Config, string, error) {
+    certFile := os.Getenv("TLS_CERT_FILE")
+    keyFile := os.Getenv("TLS_KEY_FILE")
+    clientCAFile := os.Getenv("TLS_CLIENT_CA_FILE")
+
+    if certFile == "" && keyFile == "" {
+        if clientCAFile != "" {
+            return nil, "", errors.New("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+        }
+        return nil, "plaintext", nil
+    }
+    if certFile == "" || keyFile == "" {
+        return nil, "", errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+    }
+
+    cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to load TLS certificate: %w", err)
+    }
+    if clientCAFile == "" {
+        return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, "TLS", nil
+    }
+
+    caPEM, err := os.ReadFile(clientCAFile)
+    if err != nil {
+        return nil, "", fmt.Errorf("failed to read client CA: %w", err)
+    }
+    clientCAs := x509.NewCertPool()
+    if !clientCAs.AppendCertsFromPEM(caPEM) {
+        return nil, "", fmt.Errorf("no certificates found in %s", clientCAFile)
+    }
+    return &tls.Config{
+        Certificates: []tls.Certificate{cert},
+        ClientCAs:    clientCAs,
+        ClientAuth:   tls.RequireAndVerifyClientCert,
+        MinVersion:   tls.VersionTLS12,
+    }, "mutual TLS", nil
+}
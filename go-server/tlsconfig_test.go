@@ -0,0 +1,80 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestMutualTLSRequiresClientCert(t *testing.T) {
+    pki := newTestPKI(t)
+    pki.setEnv(t, true)
+    ts := newTestServer(t, serverConfig{tls: true},
+        grpc.WithTransportCredentials(credentials.NewTLS(pki.clientConfig(pki.clientCert(t, "team-a")))))
+    if ts.parts.transport != "mutual TLS" {
+        t.Errorf("transport = %q, want mutual TLS", ts.parts.transport)
+    }
+    ctx := context.Background()
+    req := &pb.LockRequest{ProjectPath: t.TempDir()}
+
+    if _, err := ts.client.CheckLock(ctx, req); err != nil {
+        t.Errorf("CheckLock with a valid client cert: %v", err)
+    }
+
+    without := pb.NewCCToolsIntegrationClient(ts.dial(t,
+        grpc.WithTransportCredentials(credentials.NewTLS(pki.clientConfig()))))
+    if _, err := without.CheckLock(ctx, req); status.Code(err) != codes.Unavailable {
+        t.Errorf("CheckLock without a client cert = %v, want Unavailable", err)
+    }
+
+    // A certificate from another CA is rejected too
+    other := newTestPKI(t)
+    untrusted := pb.NewCCToolsIntegrationClient(ts.dial(t,
+        grpc.WithTransportCredentials(credentials.NewTLS(pki.clientConfig(other.clientCert(t, "team-a"))))))
+    if _, err := untrusted.CheckLock(ctx, req); status.Code(err) != codes.Unavailable {
+        t.Errorf("CheckLock with an untrusted client cert = %v, want Unavailable", err)
+    }
+}
+
+func TestServerTLSConfigModes(t *testing.T) {
+    pki := newTestPKI(t)
+    tests := []struct {
+        name                      string
+        certFile, keyFile, caFile string
+        mode                      string
+        wantErr                   bool
+    }{
+        {name: "plaintext", mode: "plaintext"},
+        {name: "TLS", certFile: pki.certFile, keyFile: pki.keyFile, mode: "TLS"},
+        {name: "mutual TLS", certFile: pki.certFile, keyFile: pki.keyFile, caFile: pki.caFile, mode: "mutual TLS"},
+        {name: "cert without key", certFile: pki.certFile, wantErr: true},
+        {name: "client CA without cert", caFile: pki.caFile, wantErr: true},
+        {name: "client CA file without certificates", certFile: pki.certFile, keyFile: pki.keyFile, caFile: pki.keyFile, wantErr: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv("TLS_CERT_FILE", tt.certFile)
+            t.Setenv("TLS_KEY_FILE", tt.keyFile)
+            t.Setenv("TLS_CLIENT_CA_FILE", tt.caFile)
+            config, mode, err := serverTLSConfig()
+            if tt.wantErr {
+                if err == nil {
+                    t.Fatalf("serverTLSConfig() = %q, want an error", mode)
+                }
+                return
+            }
+            if err != nil {
+                t.Fatalf("serverTLSConfig: %v", err)
+            }
+            if mode != tt.mode || (config == nil) != (tt.mode == "plaintext") {
+                t.Errorf("serverTLSConfig() = %v, %q, want mode %q", config != nil, mode, tt.mode)
+            }
+        })
+    }
+}
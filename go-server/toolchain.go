@@ -0,0 +1,323 @@
+package main
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+    "os/exec"
+    "path/filepath"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// toolchainValidator is the name of the built-in toolchain version check
+const toolchainValidator = "toolchain-version"
+
+// toolchainProbeTimeout bounds the `<tool> --version` call
+const toolchainProbeTimeout = 10 * time.Second
+
+// toolchainRequirement is a version constraint a project declares for its toolchain
+type toolchainRequirement struct {
+    tool       string   // display name (node, rustc)
+    source     string   // where the constraint was declared
+    constraint string   // semver range, e.g. ">=18 <21" or "^1.70"
+    probe      []string // command printing the installed version
+}
+
+// toolchainReaders read a project type's declared toolchain requirement
+// from the directory holding its marker file; nil means none is declared
+var toolchainReaders = map[string]func(dir string) (*toolchainRequirement, error){
+    "npm":   npmToolchain,
+    "cargo": cargoToolchain,
+    "zig":   zigToolchain,
+    "gomod": goModToolchain,
+}
+
+func npmToolchain(dir string) (*toolchainRequirement, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+    if err != nil {
+        return nil, err
+    }
+    var manifest struct {
+        Engines map[string]string `json:"engines"`
+    }
+    if err := json.Unmarshal(data, &manifest); err != nil {
+        return nil, fmt.Errorf("package.json: %w", err)
+    }
+    constraint, ok := manifest.Engines["node"]
+    if !ok {
+        return nil, nil
+    }
+    return &toolchainRequirement{tool: "node", source: "package.json engines.node", constraint: constraint, probe: []string{"node", "--version"}}, nil
+}
+
+func cargoToolchain(dir string) (*toolchainRequirement, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "Cargo.toml"))
+    if err != nil {
+        return nil, err
+    }
+    for _, section := range []string{"package", "workspace.package"} {
+        if version, ok := tomlString(data, section, "rust-version"); ok {
+            // rust-version is a minimum, not a range
+            return &toolchainRequirement{tool: "rustc", source: "Cargo.toml rust-version", constraint: ">=" + version, probe: []string{"rustc", "--version"}}, nil
+        }
+    }
+    return nil, nil
+}
+
+// checkToolchain runs the built-in toolchain-version validator, comparing
+// the project's declared toolchain version against the installed one
+func (s *CCToolsServer) checkToolchain(ctx context.Context, req *pb.ValidationRequest, metadata *pb.ProjectMetadata) *pb.ValidationResult {
+    startTime := time.Now()
+    result := &pb.ValidationResult{Validator: toolchainValidator, Success: true}
+    defer func() { result.ExecutionTimeMs = time.Since(startTime).Milliseconds() }()
+
+    read, ok := toolchainReaders[metadata.ProjectType]
+    if !ok {
+        result.Output = fmt.Sprintf("No toolchain check for project type %q", metadata.ProjectType)
+        return result
+    }
+    dir := filepath.Join(req.ProjectRoot, metadata.MarkerDir)
+    requirement, err := read(dir)
+    if err != nil {
+        result.Success = false
+        result.Error = fmt.Sprintf("Failed to read toolchain requirement: %v", err)
+        return result
+    }
+    if requirement == nil {
+        result.Output = "No toolchain version declared"
+        return result
+    }
+
+    // The probe sees the environment the validators get, so a PATH set by
+    // PROJECT_ENV or the request finds the same toolchain they would
+    spec := s.resolveValidator(req, metadata, toolchainValidator, strings.Join(requirement.probe, " "))
+    probe := requirement.probe
+    if req.LoginShell {
+        probe = []string{"bash", "-lc", strings.Join(probe, " ")}
+    } else if path, ok := spec.env["PATH"]; ok {
+        if resolved, err := lookPathIn(probe[0], path); err == nil {
+            probe = append([]string{resolved}, probe[1:]...)
+        }
+    }
+    ctx, cancel := context.WithTimeout(ctx, toolchainProbeTimeout)
+    defer cancel()
+    cmd := exec.CommandContext(ctx, probe[0], probe[1:]...)
+    cmd.Dir = dir
+    cmd.Env = spec.environ()
+    out, err := cmd.Output()
+    result.ResolvedArgv = probe
+    if err != nil {
+        result.Success = false
+        result.Error = fmt.Sprintf("%s is not available: %v", requirement.tool, err)
+        return result
+    }
+
+    installed, ok := findVersion(string(out))
+    if !ok {
+        result.Success = false
+        result.Error = fmt.Sprintf("Could not parse %s version from %q", requirement.tool, strings.TrimSpace(string(out)))
+        return result
+    }
+
+    satisfied, err := versionSatisfies(installed, requirement.constraint)
+    if err != nil {
+        result.Success = false
+        result.Error = fmt.Sprintf("Invalid %s %q: %v", requirement.source, requirement.constraint, err)
+        return result
+    }
+    if !satisfied {
+        result.Success = false
+        result.Error = fmt.Sprintf("%s %s does not satisfy %s %q", requirement.tool, installed, requirement.source, requirement.constraint)
+        return result
+    }
+    result.Output = fmt.Sprintf("%s %s satisfies %s %q", requirement.tool, installed, requirement.source, requirement.constraint)
+    return result
+}
+
+// lookPathIn finds program in the directories of pathList, as exec.LookPath
+// does with the server's own PATH
+func lookPathIn(program, pathList string) (string, error) {
+    for _, dir := range filepath.SplitList(pathList) {
+        if dir == "" {
+            continue
+        }
+        if path, err := exec.LookPath(filepath.Join(dir, program)); err == nil {
+            return path, nil
+        }
+    }
+    return "", fmt.Errorf("%s not found in %s", program, pathList)
+}
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+){0,2}`)
+
+// findVersion extracts the first dotted version number from tool output
+// such as "v20.11.0" or "rustc 1.75.0 (82e1608df 2023-12-21)"
+func findVersion(output string) (string, bool) {
+    v := versionPattern.FindString(output)
+    return v, v != ""
+}
+
+// semver is a parsed version; n records how many components were given
+// so partial versions ("18", "1.70") can act as ranges
+type semver struct {
+    parts [3]int
+    n     int
+}
+
+func parseSemver(s string) (semver, error) {
+    var v semver
+    s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+    if i := strings.IndexAny(s, "-+"); i >= 0 {
+        s = s[:i] // prerelease and build metadata are ignored
+    }
+    if s == "" || s == "*" || s == "x" || s == "X" {
+        return v, nil
+    }
+    for i, field := range strings.Split(s, ".") {
+        if i >= 3 {
+            return v, fmt.Errorf("too many components in %q", s)
+        }
+        if field == "x" || field == "X" || field == "*" {
+            break
+        }
+        n, err := strconv.Atoi(field)
+        if err != nil || n < 0 {
+            return v, fmt.Errorf("invalid version %q", s)
+        }
+        v.parts[i] = n
+        v.n = i + 1
+    }
+    return v, nil
+}
+
+func (v semver) compare(o semver) int {
+    for i := 0; i < 3; i++ {
+        if v.parts[i] != o.parts[i] {
+            if v.parts[i] < o.parts[i] {
+                return -1
+            }
+            return 1
+        }
+    }
+    return 0
+}
+
+// bump returns the smallest version above every version matching v's given
+// components, incrementing component i (e.g. bump("1.2", 1) = 1.3.0)
+func (v semver) bump(i int) semver {
+    next := semver{n: 3}
+    copy(next.parts[:i], v.parts[:i])
+    next.parts[i] = v.parts[i] + 1
+    return next
+}
+
+// versionSatisfies reports whether version matches an npm-style range:
+// alternatives separated by "||", each a space-separated list of
+// comparators using >=, >, <=, <, =, ^, ~ or x-ranges ("18", "1.2.x"), or
+// a hyphen range ("1.2 - 1.4")
+func versionSatisfies(version, constraint string) (bool, error) {
+    v, err := parseSemver(version)
+    if err != nil {
+        return false, err
+    }
+    v.n = 3
+
+    for _, alternative := range strings.Split(constraint, "||") {
+        ok := true
+        for _, comparator := range splitComparators(alternative) {
+            matched, err := matchComparator(v, comparator)
+            if err != nil {
+                return false, err
+            }
+            ok = ok && matched
+        }
+        if ok {
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
+// splitComparators splits on whitespace, re-attaching operators written
+// apart from their version (">= 18") and turning a hyphen range "A - B"
+// into ">=A <=B"
+func splitComparators(s string) []string {
+    var result []string
+    pending := ""
+    for _, field := range strings.Fields(s) {
+        if field == "-" && pending == "" && len(result) > 0 {
+            if last := result[len(result)-1]; strings.TrimLeft(last, "<>=^~") == last {
+                result[len(result)-1] = ">=" + last
+                pending = "<="
+                continue
+            }
+        }
+        if strings.Trim(field, "<>=^~") == "" {
+            pending += field
+            continue
+        }
+        result = append(result, pending+field)
+        pending = ""
+    }
+    return result
+}
+
+func matchComparator(v semver, comparator string) (bool, error) {
+    op := comparator[:len(comparator)-len(strings.TrimLeft(comparator, "<>=^~"))]
+    target, err := parseSemver(comparator[len(op):])
+    if err != nil {
+        return false, err
+    }
+
+    // upper is the exclusive end of the versions a partial target covers
+    upper := target
+    if target.n > 0 && target.n < 3 {
+        upper = target.bump(target.n - 1)
+    }
+
+    switch op {
+    case "", "=":
+        if target.n == 0 {
+            return true, nil
+        }
+        if target.n == 3 {
+            return v.compare(target) == 0, nil
+        }
+        return v.compare(target) >= 0 && v.compare(upper) < 0, nil
+    case ">=":
+        return v.compare(target) >= 0, nil
+    case ">":
+        if target.n < 3 {
+            return v.compare(upper) >= 0, nil
+        }
+        return v.compare(target) > 0, nil
+    case "<":
+        return v.compare(target) < 0, nil
+    case "<=":
+        if target.n < 3 {
+            return v.compare(upper) < 0, nil
+        }
+        return v.compare(target) <= 0, nil
+    case "^":
+        // Compatible with the leftmost non-zero component
+        i := 0
+        for i < target.n-1 && target.parts[i] == 0 {
+            i++
+        }
+        return v.compare(target) >= 0 && v.compare(target.bump(i)) < 0, nil
+    case "~":
+        i := 1
+        if target.n < 2 {
+            i = 0
+        }
+        return v.compare(target) >= 0 && v.compare(target.bump(i)) < 0, nil
+    default:
+        return false, fmt.Errorf("unsupported operator %q", op)
+    }
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestVersionSatisfies(t *testing.T) {
+    tests := []struct {
+        version    string
+        constraint string
+        want       bool
+    }{
+        {"20.11.0", ">=18", true},
+        {"16.20.2", ">=18", false},
+        {"20.11.0", ">=18 <21", true},
+        {"21.0.0", ">=18 <21", false},
+        {"20.11.0", ">= 18 < 21", true},
+        {"1.75.0", "^1.70", true},
+        {"2.0.0", "^1.70", false},
+        {"0.2.5", "^0.2.3", true},
+        {"0.3.0", "^0.2.3", false},
+        {"1.2.9", "~1.2.3", true},
+        {"1.3.0", "~1.2.3", false},
+        {"18.4.0", "18", true},
+        {"1.2.7", "1.2.x", true},
+        {"1.3.0", "1.2.x", false},
+        {"16.0.0", "14 || >=18", false},
+        {"18.0.0", "14 || >=18", true},
+
+        // Hyphen ranges: inclusive at both ends, a partial upper bound
+        // covering its whole range
+        {"1.2.0", "1.2 - 1.4", true},
+        {"1.1.9", "1.2 - 1.4", false},
+        {"1.3.5", "1.2 - 1.4", true},
+        {"1.4.9", "1.2 - 1.4", true},
+        {"1.5.0", "1.2 - 1.4", false},
+        {"2.3.4", "1.2.3 - 2.3.4", true},
+        {"2.3.5", "1.2.3 - 2.3.4", false},
+        {"1.2.2", "1.2.3 - 2.3.4", false},
+        {"20.0.0", "14 - 16 || 18 - 20", true},
+        {"17.0.0", "14 - 16 || 18 - 20", false},
+    }
+    for _, tt := range tests {
+        got, err := versionSatisfies(tt.version, tt.constraint)
+        if err != nil {
+            t.Errorf("versionSatisfies(%q, %q): %v", tt.version, tt.constraint, err)
+            continue
+        }
+        if got != tt.want {
+            t.Errorf("versionSatisfies(%q, %q) = %v, want %v", tt.version, tt.constraint, got, tt.want)
+        }
+    }
+}
+
+func TestSplitComparators(t *testing.T) {
+    tests := map[string]string{
+        ">=18 <21":   ">=18,<21",
+        ">= 18 < 21": ">=18,<21",
+        "1.2 - 1.4":  ">=1.2,<=1.4",
+        "1.2.3 - 2":  ">=1.2.3,<=2",
+        "^1.2":       "^1.2",
+        "":           "",
+    }
+    for in, want := range tests {
+        if got := strings.Join(splitComparators(in), ","); got != want {
+            t.Errorf("splitComparators(%q) = %q, want %q", in, got, want)
+        }
+    }
+}
+
+func TestCheckToolchainProbeUsesValidatorEnv(t *testing.T) {
+    bin := t.TempDir()
+    node := filepath.Join(bin, "node")
+    if err := os.WriteFile(node, []byte("#!/bin/sh\necho \"v${FAKE_NODE_VERSION:-0.0.0}\"\n"), 0o755); err != nil {
+        t.Fatal(err)
+    }
+    root := writeProject(t, map[string]string{"package.json": `{"engines": {"node": "18 - 20"}}`})
+    s := NewCCToolsServer()
+    metadata, err := s.detectProjectMetadata(root)
+    if err != nil {
+        t.Fatal(err)
+    }
+
+    req := &pb.ValidationRequest{
+        ProjectRoot: root,
+        Env:         map[string]string{"PATH": bin + string(os.PathListSeparator) + os.Getenv("PATH"), "FAKE_NODE_VERSION": "19.8.1"},
+    }
+    result := s.checkToolchain(context.Background(), req, metadata)
+    if !result.Success || !strings.Contains(result.Output, "node 19.8.1 satisfies") {
+        t.Fatalf("result = %v, want the request's node 19.8.1 found and accepted", result)
+    }
+    if len(result.ResolvedArgv) == 0 || result.ResolvedArgv[0] != node {
+        t.Errorf("resolved argv = %q, want the node on the request's PATH", result.ResolvedArgv)
+    }
+
+    req.Env["FAKE_NODE_VERSION"] = "21.0.0"
+    if result := s.checkToolchain(context.Background(), req, metadata); result.Success {
+        t.Errorf("node 21.0.0 accepted for 18 - 20: %v", result)
+    }
+}
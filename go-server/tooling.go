@@ -0,0 +1,42 @@
+package main
+
+import (
+    "os"
+    "os/exec"
+    "path/filepath"
+    "strings"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// annotateTooling records, for each detected command, whether its program
+// can be found on this node. The check uses the server's PATH; tools that
+// only appear in a login shell (see login_shell) are reported unavailable.
+func annotateTooling(metadata *pb.ProjectMetadata) {
+    dir := filepath.Join(metadata.ProjectRoot, metadata.MarkerDir)
+    metadata.CommandAvailable = make(map[string]bool, len(metadata.Commands))
+    for name, command := range metadata.Commands {
+        metadata.CommandAvailable[name] = commandAvailable(command, dir)
+    }
+}
+
+// commandAvailable reports whether the program of a command string resolves
+// to an executable. Programs given as a path (./gradlew) are resolved against dir.
+func commandAvailable(command, dir string) bool {
+    fields := strings.Fields(command)
+    if len(fields) == 0 {
+        return false
+    }
+
+    program := fields[0]
+    if !strings.ContainsRune(program, filepath.Separator) {
+        _, err := exec.LookPath(program)
+        return err == nil
+    }
+
+    if !filepath.IsAbs(program) {
+        program = filepath.Join(dir, program)
+    }
+    info, err := os.Stat(program)
+    return err == nil && !info.IsDir() && info.Mode()&0o111 != 0
+}
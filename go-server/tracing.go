@@ -0,0 +1,86 @@
+package main
+
+import (
+    "context"
+    "log/slog"
+    "os"
+    "time"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    otelcodes "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/sdk/resource"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/trace"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// Tracing
+//
+// Every RPC gets a server span from the otelgrpc stats handler, joined to
+// the caller's trace through the W3C traceparent header, and every
+// validator a child span carrying its name, command, exit code and
+// duration. Spans are exported over OTLP/gRPC to
+// OTEL_EXPORTER_OTLP_ENDPOINT; the other standard OTEL_EXPORTER_OTLP_*
+// variables (headers, insecure, timeout) apply as usual. Without an
+// endpoint the global tracer provider stays the OpenTelemetry no-op and
+// tracing costs next to nothing.
+
+// tracerName identifies the spans this server creates itself
+const tracerName = "github.com/devflow/cc-tools-server"
+
+// setupTracing installs the OTLP tracer provider when an endpoint is
+// configured and returns a func that flushes and stops it
+func setupTracing() func(context.Context) error {
+    if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" {
+        return func(context.Context) error { return nil }
+    }
+
+    exporter, err := otlptracegrpc.New(context.Background())
+    if err != nil {
+        slog.Error("tracing disabled: failed to create OTLP exporter", "error", err)
+        return func(context.Context) error { return nil }
+    }
+    provider := sdktrace.NewTracerProvider(
+        sdktrace.WithBatcher(exporter),
+        sdktrace.WithResource(resource.NewSchemaless(
+            attribute.String("service.name", envString("OTEL_SERVICE_NAME", "cc-tools-server")),
+        )),
+    )
+    otel.SetTracerProvider(provider)
+    otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+    slog.Info("tracing enabled", "endpoint", os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"))
+    return provider.Shutdown
+}
+
+// startValidatorSpan opens the span covering one validator, retries included
+func startValidatorSpan(ctx context.Context, spec *validatorSpec) (context.Context, trace.Span) {
+    return otel.Tracer(tracerName).Start(ctx, "validator "+spec.name, trace.WithAttributes(
+        attribute.String("validator.name", spec.name),
+        attribute.String("validator.command", spec.command),
+        attribute.String("validator.dir", spec.workDir),
+    ))
+}
+
+// endValidatorSpan records the outcome of a validator and ends its span
+func endValidatorSpan(span trace.Span, result *pb.ValidationResult, elapsed time.Duration) {
+    span.SetAttributes(
+        attribute.Int("validator.exit_code", int(result.ExitCode)),
+        attribute.Int64("validator.duration_ms", elapsed.Milliseconds()),
+        attribute.Int("validator.attempts", int(result.AttemptCount)),
+    )
+    if result.FailureReason != pb.FailureReason_FAILURE_REASON_UNSPECIFIED {
+        span.SetAttributes(attribute.String("validator.failure_reason", result.FailureReason.String()))
+    }
+    if !result.Success {
+        description := result.Error
+        if description == "" {
+            description = "validator failed"
+        }
+        span.SetStatus(otelcodes.Error, description)
+    }
+    span.End()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+    "context"
+    "testing"
+
+    "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    otelcodes "go.opentelemetry.io/otel/codes"
+    sdktrace "go.opentelemetry.io/otel/sdk/trace"
+    "go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+func TestValidatorSpans(t *testing.T) {
+    exporter := tracetest.NewInMemoryExporter()
+    provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+    previous := otel.GetTracerProvider()
+    otel.SetTracerProvider(provider)
+    t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{"lint": "true", "test": "exit 3"})
+    if _, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{ProjectRoot: root}); err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    provider.ForceFlush(context.Background())
+
+    spans := make(map[string]tracetest.SpanStub)
+    for _, span := range exporter.GetSpans() {
+        spans[span.Name] = span
+    }
+    rpc, ok := spans["cc_tools_integration.CCToolsIntegration/ValidateProject"]
+    if !ok {
+        t.Fatalf("no RPC span among %v", exporter.GetSpans())
+    }
+
+    for validator, want := range map[string]struct {
+        command  string
+        exitCode int64
+        status   otelcodes.Code
+    }{
+        "lint": {"make lint", 0, otelcodes.Unset},
+        "test": {"make test", 2, otelcodes.Error},
+    } {
+        span, ok := spans["validator "+validator]
+        if !ok {
+            t.Errorf("no span for %s", validator)
+            continue
+        }
+        if span.Parent.SpanID() != rpc.SpanContext.SpanID() {
+            t.Errorf("%s span is not a child of the RPC span", validator)
+        }
+        attrs := make(map[attribute.Key]attribute.Value)
+        for _, kv := range span.Attributes {
+            attrs[kv.Key] = kv.Value
+        }
+        if got := attrs["validator.name"].AsString(); got != validator {
+            t.Errorf("%s: validator.name = %q", validator, got)
+        }
+        if got := attrs["validator.command"].AsString(); got != want.command {
+            t.Errorf("%s: validator.command = %q, want %q", validator, got, want.command)
+        }
+        if got := attrs["validator.exit_code"].AsInt64(); got != want.exitCode {
+            t.Errorf("%s: validator.exit_code = %d, want %d", validator, got, want.exitCode)
+        }
+        if _, ok := attrs["validator.duration_ms"]; !ok {
+            t.Errorf("%s: no validator.duration_ms", validator)
+        }
+        if span.Status.Code != want.status {
+            t.Errorf("%s: status %v, want %v", validator, span.Status.Code, want.status)
+        }
+    }
+}
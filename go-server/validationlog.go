@@ -0,0 +1,151 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "io"
+    "io/fs"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "sort"
+    "sync"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// defaultValidationLogReadBytes caps a GetValidationLog response without max_bytes
+const defaultValidationLogReadBytes = 1 << 20
+
+// validationLogs persists the output of streamed runs so the full log can
+// be fetched with GetValidationLog after (or while) the client watches it
+// live. Each run gets a directory named after its job id holding one
+// <validator>.log per validator; only the newest maxRuns runs are kept.
+type validationLogs struct {
+    dir     string
+    maxRuns int
+
+    mu   sync.Mutex
+    runs []string // run ids, oldest first
+}
+
+// loadValidationLogs reads VALIDATION_LOG_DIR and VALIDATION_LOG_MAX_RUNS;
+// a limit of 0 disables persistence and returns nil
+func loadValidationLogs() *validationLogs {
+    maxRuns := envInt("VALIDATION_LOG_MAX_RUNS", 100)
+    if maxRuns <= 0 {
+        return nil
+    }
+    l := &validationLogs{
+        dir:     envString("VALIDATION_LOG_DIR", filepath.Join(os.TempDir(), "cc-tools-validation-logs")),
+        maxRuns: maxRuns,
+    }
+
+    // Pick up runs left by a previous process so they are pruned in turn
+    entries, _ := os.ReadDir(l.dir)
+    modTimes := make(map[string]int64, len(entries))
+    for _, entry := range entries {
+        if info, err := entry.Info(); err == nil && entry.IsDir() {
+            l.runs = append(l.runs, entry.Name())
+            modTimes[entry.Name()] = info.ModTime().UnixNano()
+        }
+    }
+    sort.Slice(l.runs, func(i, j int) bool { return modTimes[l.runs[i]] < modTimes[l.runs[j]] })
+    return l
+}
+
+// create opens a fresh log for one validator of a run, dropping the oldest
+// runs beyond maxRuns
+func (l *validationLogs) create(runID, validator string) (*os.File, error) {
+    l.mu.Lock()
+    known := false
+    for _, id := range l.runs {
+        known = known || id == runID
+    }
+    if !known {
+        l.runs = append(l.runs, runID)
+        for len(l.runs) > l.maxRuns {
+            os.RemoveAll(filepath.Join(l.dir, l.runs[0]))
+            l.runs = l.runs[1:]
+        }
+    }
+    l.mu.Unlock()
+
+    dir := filepath.Join(l.dir, runID)
+    if err := os.MkdirAll(dir, 0o700); err != nil {
+        return nil, err
+    }
+    return os.OpenFile(filepath.Join(dir, validator+".log"), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+}
+
+// bestEffortWriter feeds a validator's persisted log without letting it
+// fail the run: the log is one branch of the output MultiWriter, so a full
+// disk would otherwise abort the copy and fail the validator. The first
+// error is logged once; later writes are dropped.
+type bestEffortWriter struct {
+    validator string
+    w         io.Writer
+    failed    bool
+}
+
+func (b *bestEffortWriter) Write(p []byte) (int, error) {
+    if b.failed {
+        return len(p), nil
+    }
+    if _, err := b.w.Write(p); err != nil {
+        b.failed = true
+        slog.Warn("validation log: write failed, no longer persisting output", "validator", b.validator, "error", err)
+    }
+    return len(p), nil
+}
+
+// GetValidationLog returns the persisted output of one validator of a
+// streamed run, identified by the run_id carried on its events
+func (s *CCToolsServer) GetValidationLog(ctx context.Context, req *pb.ValidationLogRequest) (*pb.ValidationLog, error) {
+    if s.validationLogs == nil {
+        return nil, status.Error(codes.FailedPrecondition, "validation logs are disabled (VALIDATION_LOG_MAX_RUNS=0)")
+    }
+    if !validLogName(req.RunId) || !validLogName(req.Validator) {
+        return nil, status.Error(codes.InvalidArgument, "run_id and validator must be plain names")
+    }
+    if req.Offset < 0 || req.MaxBytes < 0 {
+        return nil, status.Error(codes.InvalidArgument, "offset and max_bytes must not be negative")
+    }
+
+    f, err := os.Open(filepath.Join(s.validationLogs.dir, req.RunId, req.Validator+".log"))
+    if errors.Is(err, fs.ErrNotExist) {
+        return nil, status.Errorf(codes.NotFound, "no log for validator %q of run %q", req.Validator, req.RunId)
+    }
+    if err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to open log: %v", err)
+    }
+    defer f.Close()
+
+    info, err := f.Stat()
+    if err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to stat log: %v", err)
+    }
+    limit := req.MaxBytes
+    if limit == 0 {
+        limit = defaultValidationLogReadBytes
+    }
+    content, err := io.ReadAll(io.LimitReader(io.NewSectionReader(f, req.Offset, info.Size()), limit))
+    if err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to read log: %v", err)
+    }
+
+    return &pb.ValidationLog{
+        RunId:      req.RunId,
+        Validator:  req.Validator,
+        Content:    content,
+        TotalBytes: info.Size(),
+    }, nil
+}
+
+// validLogName rejects names that could resolve outside the log directory
+func validLogName(name string) bool {
+    return name != "" && name != "." && name != ".." && filepath.Base(name) == name
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+    "context"
+    "errors"
+    "strings"
+    "testing"
+    "time"
+)
+
+// failingWriter fails every write, like a log on a full disk
+type failingWriter struct{ writes *int }
+
+func (w failingWriter) Write(p []byte) (int, error) {
+    *w.writes++
+    return 0, errors.New("no space left on device")
+}
+
+func TestLogWriteFailureDoesNotFailValidator(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    s := NewCCToolsServer()
+    writes := 0
+    spec := &validatorSpec{
+        name:    "test",
+        command: "sh -c 'echo one; echo two >&2; echo three'",
+        workDir: t.TempDir(),
+        timeout: 10 * time.Second,
+        log:     failingWriter{&writes},
+    }
+
+    result := s.executeValidator(context.Background(), spec)
+    if !result.Success {
+        t.Fatalf("result = %v, want success despite the failing log", result)
+    }
+    for _, line := range []string{"one", "two", "three"} {
+        if !strings.Contains(result.Output, line) {
+            t.Errorf("output %q is missing %q", result.Output, line)
+        }
+    }
+    if writes != 1 {
+        t.Errorf("log written %d times, want 1 attempt before giving up", writes)
+    }
+}
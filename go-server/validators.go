@@ -0,0 +1,461 @@
+package main
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "io"
+    "log/slog"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "time"
+    "unicode"
+
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// defaultValidatorTimeoutMs is the DEFAULT_TIMEOUT_MS default, the timeout
+// of validators the request sets none for
+const defaultValidatorTimeoutMs = 30000
+
+// loadDefaultTimeout reads DEFAULT_TIMEOUT_MS, ignoring values that are not
+// positive
+func loadDefaultTimeout() time.Duration {
+    ms := envInt("DEFAULT_TIMEOUT_MS", defaultValidatorTimeoutMs)
+    if ms <= 0 {
+        slog.Warn("Ignoring non-positive DEFAULT_TIMEOUT_MS", "value", ms, "using", defaultValidatorTimeoutMs)
+        ms = defaultValidatorTimeoutMs
+    }
+    return time.Duration(ms) * time.Millisecond
+}
+
+// validatorStages lists the built-in validators ValidateProject runs, in
+// order; each runs when the project has a command for it
+var validatorStages = []string{"format", "lint", "typecheck", configureValidator, "build", "test"}
+
+// configureValidator prepares the build tree (e.g. `cmake -S . -B build`).
+// It runs on its own before the other validators, which are skipped when
+// it fails.
+const configureValidator = "configure"
+
+// resultOrder is the documented order of ValidationResults, independent of
+// how validators were scheduled. Validators not listed here follow, sorted
+// by name.
+var resultOrder = []string{toolchainValidator, "format", configureValidator, "build", "lint", "typecheck", "test"}
+
+// sortResults puts results into resultOrder
+func sortResults(results []*pb.ValidationResult) {
+    rank := func(name string) int {
+        for i, n := range resultOrder {
+            if n == name {
+                return i
+            }
+        }
+        return len(resultOrder)
+    }
+    sort.SliceStable(results, func(i, j int) bool {
+        ri, rj := rank(results[i].Validator), rank(results[j].Validator)
+        if ri != rj {
+            return ri < rj
+        }
+        return results[i].Validator < results[j].Validator
+    })
+}
+
+// validatorSpec is the fully resolved plan for running one validator. It is
+// shared by ValidateProject and the introspection RPCs so that what gets
+// reported is exactly what runs.
+type validatorSpec struct {
+    name    string
+    command string
+    workDir string
+    timeout time.Duration
+
+    // args is an explicit argv that bypasses tokenization of command;
+    // nil when the command was given in string form
+    args []string
+
+    // loginShell runs the command via `bash -lc` so that profile-managed
+    // toolchains (asdf, rbenv, nvm) resolve. Each run pays for sourcing the
+    // user's profile, so it is opt-in.
+    loginShell bool
+
+    // env holds variables set on top of the server environment
+    env map[string]string
+
+    // retries is how many times a transient failure is re-run;
+    // retryExitCodes are exit codes the request adds to the transient ones
+    retries        int
+    retryExitCodes map[int]bool
+
+    // stdin is written to the child's stdin, or stdinFile is piped into it
+    // when set; with neither the child reads from the null device
+    stdin     []byte
+    stdinFile string
+
+    // chatty exempts the validator from the runaway output guard
+    chatty bool
+
+    // prefix is the operator's VALIDATOR_COMMAND_PREFIX, spliced in front
+    // of the argv (see argv)
+    prefix []string
+
+    // contentionKey serializes validators that must not run concurrently
+    // (see validatorSlots); empty never waits
+    contentionKey string
+
+    // stream receives each output line as it is produced and log a raw
+    // copy of the output (StreamValidation only)
+    stream func(line string)
+    log    io.Writer
+}
+
+// resolveValidators returns the specs for every validator the request would
+// run, in order. Request overrides replace detected commands; an empty
+// override disables the validator, and an argv override wins over a string one.
+func (s *CCToolsServer) resolveValidators(req *pb.ValidationRequest, metadata *pb.ProjectMetadata) []*validatorSpec {
+    names := validatorNames(metadata)
+    specs := make([]*validatorSpec, 0, len(names))
+    for _, name := range names {
+        command, exists := metadata.Commands[name]
+        if override, ok := req.OverrideCommands[name]; ok {
+            command, exists = override, override != ""
+        }
+
+        var args []string
+        if argv, ok := req.OverrideArgv[name]; ok {
+            args = argv.GetArgs()
+            command, exists = shellJoin(args), len(args) > 0
+        }
+
+        if !exists {
+            continue
+        }
+        spec := s.resolveValidator(req, metadata, name, command)
+        spec.args = args
+        specs = append(specs, spec)
+    }
+    return specs
+}
+
+// validatorNames returns validatorStages followed by the project's other
+// commands, sorted by name. Detectors only emit stage commands, so the
+// others are extra validators from the project's devflow config, such as
+// coverage.
+func validatorNames(metadata *pb.ProjectMetadata) []string {
+    names := append([]string(nil), validatorStages...)
+    stages := make(map[string]bool, len(validatorStages))
+    for _, name := range validatorStages {
+        stages[name] = true
+    }
+    var extra []string
+    for name := range metadata.Commands {
+        if !stages[name] {
+            extra = append(extra, name)
+        }
+    }
+    sort.Strings(extra)
+    return append(names, extra...)
+}
+
+// excludedValidators returns a skipped result for each detected validator
+// the request disables with an empty override_commands or override_argv
+// entry, so it shows up as excluded rather than silently disappearing
+func excludedValidators(req *pb.ValidationRequest, metadata *pb.ProjectMetadata) []*pb.ValidationResult {
+    var results []*pb.ValidationResult
+    for _, name := range validatorNames(metadata) {
+        if metadata.Commands[name] == "" {
+            continue
+        }
+        field := ""
+        if argv, ok := req.OverrideArgv[name]; ok {
+            if len(argv.GetArgs()) == 0 {
+                field = "override_argv"
+            }
+        } else if override, ok := req.OverrideCommands[name]; ok && override == "" {
+            field = "override_commands"
+        }
+        if field != "" {
+            results = append(results, skippedResult(name, pb.SkipReason_SKIP_REASON_EXCLUDED, "Disabled by an empty "+field+" entry"))
+        }
+    }
+    return results
+}
+
+// splitConfigure separates the configure step from the other specs
+func splitConfigure(specs []*validatorSpec) (*validatorSpec, []*validatorSpec) {
+    for i, spec := range specs {
+        if spec.name == configureValidator {
+            rest := append(append([]*validatorSpec(nil), specs[:i]...), specs[i+1:]...)
+            return spec, rest
+        }
+    }
+    return nil, specs
+}
+
+// resolveValidator applies the request options to a detected command.
+// Validators run from the directory that holds the project's marker file,
+// or from working_dir when the request sets one. The timeout is the most
+// specific one set: validator_timeouts_ms, then timeout_ms, then
+// DEFAULT_TIMEOUT_MS.
+func (s *CCToolsServer) resolveValidator(req *pb.ValidationRequest, metadata *pb.ProjectMetadata, name, command string) *validatorSpec {
+    spec := &validatorSpec{
+        name:       name,
+        command:    command,
+        workDir:    filepath.Join(req.ProjectRoot, metadata.MarkerDir),
+        timeout:    time.Duration(req.TimeoutMs) * time.Millisecond,
+        loginShell: req.LoginShell,
+        env:        make(map[string]string),
+        retries:    int(req.Retries),
+        stdin:      req.Stdin,
+        prefix:     s.commandPrefix,
+    }
+    if req.WorkingDir != "" {
+        spec.workDir = filepath.Join(req.ProjectRoot, req.WorkingDir)
+    }
+    for _, code := range req.RetryExitCodes {
+        if spec.retryExitCodes == nil {
+            spec.retryExitCodes = make(map[int]bool)
+        }
+        spec.retryExitCodes[int(code)] = true
+    }
+    if req.StdinFile != "" {
+        spec.stdinFile = filepath.Join(req.ProjectRoot, req.StdinFile)
+    }
+    if ms := req.ValidatorTimeoutsMs[name]; ms > 0 {
+        spec.timeout = time.Duration(ms) * time.Millisecond
+    }
+    if spec.timeout == 0 {
+        spec.timeout = s.defaultTimeout
+    }
+    for k, v := range s.projectEnv[metadata.ProjectType] {
+        spec.env[k] = v
+    }
+    if req.ForceColor || s.forceColorTypes[metadata.ProjectType] {
+        for k, v := range forceColorEnv {
+            spec.env[k] = v
+        }
+    }
+    if len(req.FilePaths) > 0 {
+        spec.env[changedFilesEnv] = strings.Join(req.FilePaths, "\n")
+    }
+    for k, v := range req.Env {
+        spec.env[k] = v
+    }
+    for _, v := range req.LoginShellValidators {
+        if v == name {
+            spec.loginShell = true
+        }
+    }
+    for _, v := range req.ChattyValidators {
+        if v == name {
+            spec.chatty = true
+        }
+    }
+    spec.contentionKey = s.contentionKey(req, metadata, spec)
+    return spec
+}
+
+// openStdin returns the reader to attach to the child's stdin, or nil when
+// the validator gets no input. The caller must call close once it exits.
+func (v *validatorSpec) openStdin() (io.Reader, func(), error) {
+    if v.stdinFile != "" {
+        f, err := os.Open(v.stdinFile)
+        if err != nil {
+            return nil, nil, err
+        }
+        return f, func() { f.Close() }, nil
+    }
+    if v.stdin != nil {
+        return bytes.NewReader(v.stdin), func() {}, nil
+    }
+    return nil, func() {}, nil
+}
+
+// argv returns the argument vector executed for the validator.
+//
+// A command prefix (e.g. "timeout 600" or "firejail --quiet") goes in front
+// of everything else, so in shell mode it wraps `bash -lc` and the whole
+// shell runs sandboxed or instrumented. The prefix is operator
+// configuration and is trusted as-is; checks on which programs a
+// validator may run apply to the validator's own command, not the prefix.
+func (v *validatorSpec) argv() []string {
+    var parts []string
+    switch {
+    case v.loginShell:
+        // Hand the raw command to the shell so it sees it exactly as typed;
+        // argv-form commands are quoted so the shell rebuilds the same vector
+        parts = []string{"bash", "-lc", v.command}
+    case v.args != nil:
+        parts = v.args
+    default:
+        parts, _ = shellSplit(v.command)
+    }
+    if len(v.prefix) == 0 || len(parts) == 0 {
+        return parts
+    }
+    return append(append([]string(nil), v.prefix...), parts...)
+}
+
+// commandForm reports whether the command was given as a string or an argv
+func (v *validatorSpec) commandForm() string {
+    if v.args != nil {
+        return "argv"
+    }
+    return "string"
+}
+
+// shellJoin renders an argv as a single shell-safe command string
+func shellJoin(args []string) string {
+    quoted := make([]string, len(args))
+    for i, arg := range args {
+        quoted[i] = shellQuote(arg)
+    }
+    return strings.Join(quoted, " ")
+}
+
+// shellQuote single-quotes arg unless it consists only of shell-safe characters
+func shellQuote(arg string) string {
+    if arg == "" {
+        return "''"
+    }
+    safe := true
+    for _, r := range arg {
+        if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("@%+=:,./_-", r)) {
+            safe = false
+            break
+        }
+    }
+    if safe {
+        return arg
+    }
+    return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// shellSplit tokenizes a command string the way a POSIX shell splits words:
+// single quotes keep everything literally, double quotes keep spaces but
+// honour backslash escapes of \ " $ and `, and a backslash outside quotes
+// escapes the next character. There is no expansion of variables, globs or
+// substitutions. An unterminated quote or trailing backslash is an error.
+func shellSplit(command string) ([]string, error) {
+    var (
+        args    []string
+        word    strings.Builder
+        inWord  bool
+        quote   rune // ' or " while inside a quoted section
+        escaped bool
+    )
+    for _, r := range command {
+        switch {
+        case escaped:
+            if quote == '"' && !strings.ContainsRune("\\\"$`", r) {
+                word.WriteRune('\\')
+            }
+            word.WriteRune(r)
+            escaped = false
+        case quote == '\'':
+            if r == '\'' {
+                quote = 0
+            } else {
+                word.WriteRune(r)
+            }
+        case r == '\\':
+            escaped = true
+            inWord = true
+        case quote == '"':
+            if r == '"' {
+                quote = 0
+            } else {
+                word.WriteRune(r)
+            }
+        case r == '\'' || r == '"':
+            quote = r
+            inWord = true
+        case unicode.IsSpace(r):
+            if inWord {
+                args = append(args, word.String())
+                word.Reset()
+                inWord = false
+            }
+        default:
+            word.WriteRune(r)
+            inWord = true
+        }
+    }
+    if escaped {
+        return nil, fmt.Errorf("trailing backslash in %q", command)
+    }
+    if quote != 0 {
+        return nil, fmt.Errorf("unterminated %c quote in %q", quote, command)
+    }
+    if inWord {
+        args = append(args, word.String())
+    }
+    return args, nil
+}
+
+// definition converts the spec into its wire form with secrets redacted
+func (v *validatorSpec) definition(projectType string) *pb.ValidatorDefinition {
+    return &pb.ValidatorDefinition{
+        Validator:   v.name,
+        ProjectType: projectType,
+        Command:     v.command,
+        Argv:        v.argv(),
+        WorkingDir:  v.workDir,
+        TimeoutMs:   v.timeout.Milliseconds(),
+        LoginShell:  v.loginShell,
+        Env:         redactEnv(v.env),
+        CommandForm: v.commandForm(),
+        Retries:     int32(v.retries),
+    }
+}
+
+// GetValidatorDefinition returns the resolved spec of a single validator
+// after applying detection and the request options, without running it
+func (s *CCToolsServer) GetValidatorDefinition(ctx context.Context, req *pb.ValidatorDefinitionRequest) (*pb.ValidatorDefinition, error) {
+    if req.Request == nil {
+        return nil, status.Error(codes.InvalidArgument, "request is required")
+    }
+
+    metadata, err := s.detectProjectMetadata(req.Request.ProjectRoot)
+    if err != nil {
+        return nil, status.Errorf(codes.Internal, "failed to detect project metadata: %v", err)
+    }
+
+    for _, spec := range s.resolveValidators(req.Request, metadata) {
+        if spec.name == req.Validator {
+            return spec.definition(metadata.ProjectType), nil
+        }
+    }
+    return nil, status.Errorf(codes.NotFound, "validator %q is not run for project type %q", req.Validator, metadata.ProjectType)
+}
+
+// secretKeyMarkers flag environment variable names whose values must not be exposed
+var secretKeyMarkers = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "KEY", "CREDENTIAL", "AUTH"}
+
+// redactEnv copies env, masking the values of secret-looking keys
+func redactEnv(env map[string]string) map[string]string {
+    redacted := make(map[string]string, len(env))
+    for k, v := range env {
+        if isSecretKey(k) {
+            v = "[REDACTED]"
+        }
+        redacted[k] = v
+    }
+    return redacted
+}
+
+func isSecretKey(key string) bool {
+    upper := strings.ToUpper(key)
+    for _, marker := range secretKeyMarkers {
+        if strings.Contains(upper, marker) {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,319 @@
+package main
+
+import (
+    "context"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+
+    pb "github.com/devflow/cc-tools-server/proto"
+)
+
+// stagedProject is a make project with a target per recipe, the
+// non-default stages among them wired up through .devflow.yaml
+func stagedProject(t *testing.T, recipes map[string]string) string {
+    t.Helper()
+    var makefile, config strings.Builder
+    config.WriteString("commands:\n")
+    for target, recipe := range recipes {
+        makefile.WriteString(target + ":\n\t@" + recipe + "\n")
+        if target != "lint" && target != "test" {
+            config.WriteString("  " + target + ": make " + target + "\n")
+        }
+    }
+    return writeProject(t, map[string]string{"Makefile": makefile.String(), ".devflow.yaml": config.String()})
+}
+
+// validatorOrder lists the validators of a response in result order
+func validatorOrder(resp *pb.ValidationResponse) string {
+    names := make([]string, 0, len(resp.Results))
+    for _, result := range resp.Results {
+        names = append(names, result.Validator)
+    }
+    return strings.Join(names, ",")
+}
+
+func TestResultOrderIsStable(t *testing.T) {
+    t.Setenv("VALIDATOR_PARALLELISM", "5")
+    ts := newTestServer(t, serverConfig{})
+    // Earlier stages run longest, so in parallel they finish last
+    root := stagedProject(t, map[string]string{
+        "format":    "sleep 0.2",
+        "lint":      "sleep 0.15",
+        "typecheck": "sleep 0.1",
+        "build":     "sleep 0.05",
+        "test":      "true",
+    })
+    want := "format,build,lint,typecheck,test"
+
+    for run := 0; run < 3; run++ {
+        for _, parallel := range []int32{0, 1} {
+            resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{ProjectRoot: root, MaxParallelValidators: parallel})
+            if err != nil {
+                t.Fatalf("ValidateProject: %v", err)
+            }
+            if got := validatorOrder(resp); got != want {
+                t.Errorf("run %d, max_parallel_validators=%d: order %s, want %s", run, parallel, got, want)
+            }
+        }
+    }
+}
+
+func TestValidatorsRunConcurrently(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+    sleep := &pb.CommandArgv{Args: []string{"sleep", "0.3"}}
+    req := &pb.ValidationRequest{ProjectRoot: root, OverrideArgv: map[string]*pb.CommandArgv{"lint": sleep, "test": sleep}}
+
+    tests := []struct {
+        name        string
+        parallelism string
+        perRequest  int32
+        sequential  bool
+    }{
+        {name: "default parallelism"},
+        {name: "VALIDATOR_PARALLELISM=1", parallelism: "1", sequential: true},
+        {name: "max_parallel_validators=1", perRequest: 1, sequential: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            t.Setenv("VALIDATOR_PARALLELISM", tt.parallelism)
+            ts := newTestServer(t, serverConfig{})
+            req.MaxParallelValidators = tt.perRequest
+
+            start := time.Now()
+            resp, err := ts.client.ValidateProject(context.Background(), req)
+            elapsed := time.Since(start)
+            if err != nil || !resp.Success {
+                t.Fatalf("ValidateProject: %v, %v", err, resp)
+            }
+            // Two 300ms validators take about 300ms together, 600ms in turn
+            if tt.sequential && elapsed < 600*time.Millisecond {
+                t.Errorf("took %s, want at least the 600ms sum", elapsed)
+            }
+            if !tt.sequential && elapsed > 500*time.Millisecond {
+                t.Errorf("took %s, want about the 300ms of the slowest validator", elapsed)
+            }
+        })
+    }
+}
+
+func TestAllStagesRunInOrder(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    // Each stage appends its name to a log kept outside the project
+    runLog := filepath.Join(t.TempDir(), "stages.log")
+    stages := []string{"format", "lint", "typecheck", "build", "test"}
+    recipes := make(map[string]string, len(stages))
+    for _, stage := range stages {
+        recipes[stage] = "echo " + stage + " >> " + runLog
+    }
+    root := stagedProject(t, recipes)
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{ProjectRoot: root, MaxParallelValidators: 1})
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    if !resp.Success {
+        t.Fatalf("Success = false, results: %v", resp.Results)
+    }
+    if len(resp.Results) != len(stages) {
+        t.Errorf("got results %s, want one per stage", validatorOrder(resp))
+    }
+    ran, err := os.ReadFile(runLog)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got, want := strings.Fields(string(ran)), stages; strings.Join(got, ",") != strings.Join(want, ",") {
+        t.Errorf("stages ran as %v, want %v", got, want)
+    }
+}
+
+func TestSortResults(t *testing.T) {
+    var results []*pb.ValidationResult
+    for _, name := range []string{"test", "coverage", "lint", "bench", toolchainValidator, "build", "format", configureValidator, "typecheck"} {
+        results = append(results, &pb.ValidationResult{Validator: name})
+    }
+    sortResults(results)
+    if got, want := validatorOrder(&pb.ValidationResponse{Results: results}), "toolchain-version,format,configure,build,lint,typecheck,test,bench,coverage"; got != want {
+        t.Errorf("sortResults order %s, want %s", got, want)
+    }
+}
+
+func TestEmptyOverrideReportsValidatorExcluded(t *testing.T) {
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+
+    // An empty argv wins over a command override, as when running
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot:      root,
+        OverrideCommands: map[string]string{"lint": "", "format": "", "test": "make test"},
+        OverrideArgv:     map[string]*pb.CommandArgv{"test": {}},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    byName := resultsByName(resp)
+    for _, name := range []string{"lint", "test"} {
+        result := byName[name]
+        if result == nil || !result.Skipped || result.SkipReason != pb.SkipReason_SKIP_REASON_EXCLUDED {
+            t.Errorf("%s = %v, want skipped with SKIP_REASON_EXCLUDED", name, result)
+        }
+    }
+    // format is not detected for the project, so there is nothing to exclude
+    if format, ok := byName["format"]; ok {
+        t.Errorf("format = %v, want no result for an undetected validator", format)
+    }
+    if resp.Summary.Skipped != 2 || !resp.Success {
+        t.Errorf("success=%v summary=%v, want a passing run with 2 skipped", resp.Success, resp.Summary)
+    }
+}
+
+func TestShellSplit(t *testing.T) {
+    tests := []struct {
+        command string
+        want    []string
+        wantErr bool
+    }{
+        {command: "", want: nil},
+        {command: "   ", want: nil},
+        {command: "go test ./...", want: []string{"go", "test", "./..."}},
+        {command: `pytest -k "slow and db"`, want: []string{"pytest", "-k", "slow and db"}},
+        {command: `pytest -k 'slow and db'`, want: []string{"pytest", "-k", "slow and db"}},
+        {command: `ls my\ dir`, want: []string{"ls", "my dir"}},
+        {command: `echo "" ''`, want: []string{"echo", "", ""}},
+        {command: `echo "a \"quoted\" \$HOME \n"`, want: []string{"echo", `a "quoted" $HOME \n`}},
+        {command: `echo 'it'\''s'`, want: []string{"echo", "it's"}},
+        {command: `echo pre"mid dle"post`, want: []string{"echo", "premid dlepost"}},
+        {command: `echo "unterminated`, wantErr: true},
+        {command: `echo trailing\`, wantErr: true},
+    }
+    for _, tt := range tests {
+        got, err := shellSplit(tt.command)
+        if tt.wantErr {
+            if err == nil {
+                t.Errorf("shellSplit(%q) = %q, want an error", tt.command, got)
+            }
+            continue
+        }
+        if err != nil || strings.Join(got, "\x00") != strings.Join(tt.want, "\x00") || len(got) != len(tt.want) {
+            t.Errorf("shellSplit(%q) = %q, %v, want %q", tt.command, got, err, tt.want)
+        }
+    }
+}
+
+func TestExecuteValidatorBuildsArgvFromQuotedCommand(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    s := NewCCToolsServer()
+
+    spec := &validatorSpec{name: "test", command: `printf "[%s]" "slow and db" it\'s ''`, workDir: t.TempDir(), timeout: 30 * time.Second}
+    result := s.executeValidator(t.Context(), spec)
+    if !result.Success {
+        t.Fatalf("error %q", result.Error)
+    }
+    if want := []string{"printf", "[%s]", "slow and db", "it's", ""}; strings.Join(result.ResolvedArgv, "\x00") != strings.Join(want, "\x00") {
+        t.Errorf("argv = %q, want %q", result.ResolvedArgv, want)
+    }
+    if result.Output != "[slow and db][it's][]" {
+        t.Errorf("output = %q", result.Output)
+    }
+
+    for _, command := range []string{"", "   "} {
+        spec := &validatorSpec{name: "test", command: command, workDir: t.TempDir(), timeout: 30 * time.Second}
+        if result := s.executeValidator(t.Context(), spec); result.Success || result.Error != "Empty command" {
+            t.Errorf("command %q: success=%v error=%q, want Empty command", command, result.Success, result.Error)
+        }
+    }
+}
+
+func TestRequestEnvReachesValidator(t *testing.T) {
+    t.Setenv("DEVFLOW_GREETING", "from server")
+    t.Setenv("DEVFLOW_INHERITED", "inherited")
+    ts := newTestServer(t, serverConfig{})
+    root := makeProject(t, map[string]string{
+        "lint": "printenv DEVFLOW_GREETING DEVFLOW_INHERITED DEVFLOW_API_TOKEN",
+        "test": "true",
+    })
+
+    resp, err := ts.client.ValidateProject(context.Background(), &pb.ValidationRequest{
+        ProjectRoot: root,
+        Env:         map[string]string{"DEVFLOW_GREETING": "from request", "DEVFLOW_API_TOKEN": "s3cret"},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    lint := resultsByName(resp)["lint"]
+    if want := "from request\ninherited\ns3cret\n"; lint.GetOutput() != want {
+        t.Errorf("lint output = %q, want %q", lint.GetOutput(), want)
+    }
+
+    // The definition shows the request env with secrets redacted
+    def, err := ts.client.GetValidatorDefinition(context.Background(), &pb.ValidatorDefinitionRequest{
+        Request:   &pb.ValidationRequest{ProjectRoot: root, Env: map[string]string{"DEVFLOW_GREETING": "hi", "DEVFLOW_API_TOKEN": "s3cret"}},
+        Validator: "lint",
+    })
+    if err != nil {
+        t.Fatalf("GetValidatorDefinition: %v", err)
+    }
+    if def.Env["DEVFLOW_GREETING"] != "hi" || def.Env["DEVFLOW_API_TOKEN"] != "[REDACTED]" {
+        t.Errorf("definition env = %v, want the token redacted", def.Env)
+    }
+}
+
+func TestValidatorTimeoutOverridesDefaults(t *testing.T) {
+    t.Setenv("ALLOWED_COMMANDS", "*")
+    t.Setenv("DEFAULT_TIMEOUT_MS", "20000")
+    ts := newTestServer(t, serverConfig{})
+    ctx := context.Background()
+    root := makeProject(t, map[string]string{"lint": "true", "test": "true"})
+
+    tests := []struct {
+        name      string
+        timeoutMs int32
+        perName   map[string]int32
+        want      int64
+    }{
+        {name: "env default", want: 20000},
+        {name: "request default", timeoutMs: 10000, want: 10000},
+        {name: "validator over request", timeoutMs: 10000, perName: map[string]int32{"test": 200}, want: 200},
+        {name: "validator over env", perName: map[string]int32{"test": 200}, want: 200},
+        {name: "other validator", timeoutMs: 10000, perName: map[string]int32{"lint": 200}, want: 10000},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            def, err := ts.client.GetValidatorDefinition(ctx, &pb.ValidatorDefinitionRequest{
+                Request:   &pb.ValidationRequest{ProjectRoot: root, TimeoutMs: tt.timeoutMs, ValidatorTimeoutsMs: tt.perName},
+                Validator: "test",
+            })
+            if err != nil {
+                t.Fatalf("GetValidatorDefinition: %v", err)
+            }
+            if def.TimeoutMs != tt.want {
+                t.Errorf("timeout = %dms, want %dms", def.TimeoutMs, tt.want)
+            }
+        })
+    }
+
+    // Only the named validator is cut short; the rest keep timeout_ms
+    start := time.Now()
+    resp, err := ts.client.ValidateProject(ctx, &pb.ValidationRequest{
+        ProjectRoot:         root,
+        TimeoutMs:           10000,
+        ValidatorTimeoutsMs: map[string]int32{"test": 200},
+        OverrideCommands:    map[string]string{"lint": "sleep 0.5", "test": "sleep 30"},
+    })
+    if err != nil {
+        t.Fatalf("ValidateProject: %v", err)
+    }
+    if elapsed := time.Since(start); elapsed > 5*time.Second {
+        t.Errorf("validation took %s, want the 200ms validator timeout to apply", elapsed)
+    }
+    results := resultsByName(resp)
+    if test := results["test"]; test.GetSuccess() || test.GetFailureReason() != pb.FailureReason_FAILURE_REASON_TIMEOUT {
+        t.Errorf("test: success=%v reason=%s, want a timeout", test.GetSuccess(), test.GetFailureReason())
+    }
+    if lint := results["lint"]; !lint.GetSuccess() {
+        t.Errorf("lint failed under the request timeout: %s", lint.GetError())
+    }
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+    "context"
+    "log/slog"
+    "os"
+    "os/exec"
+    "strings"
+    "sync"
+    "time"
+
+    health "google.golang.org/grpc/health"
+    healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Warm-up
+//
+// The first validation after a cold start pays for toolchain discovery:
+// version-manager shims resolving, binaries and their libraries being
+// paged in, profile scripts running. With WARMUP_ENABLED the server reports
+// NOT_SERVING while it runs each known toolchain's version probe once and
+// detects the projects in WARMUP_PROJECTS, and only then switches health to
+// SERVING. Warm-up is best-effort and bounded by WARMUP_TIMEOUT_SECONDS;
+// when the budget runs out the server reports SERVING anyway.
+
+// warmUpPrograms are probed with --version whether or not a project uses them
+var warmUpPrograms = []string{"bash", "git", "make", "node", "npm", "cargo", "mix", "zig", "go", "python3", "mvn", "gradle"}
+
+// healthServices are the health entries flipped once the server is ready
+var healthServices = []string{"", readinessService}
+
+// startServing marks the server SERVING, running the warm-up first when enabled
+func startServing(hs *health.Server, s *CCToolsServer) {
+    s.health = hs
+    if !envBool("WARMUP_ENABLED", false) {
+        setServingStatus(hs, healthpb.HealthCheckResponse_SERVING)
+        s.startReadiness(hs)
+        return
+    }
+
+    setServingStatus(hs, healthpb.HealthCheckResponse_NOT_SERVING)
+    timeout := time.Duration(envInt("WARMUP_TIMEOUT_SECONDS", 60)) * time.Second
+    go func() {
+        ctx, cancel := context.WithTimeout(context.Background(), timeout)
+        defer cancel()
+        s.warmUp(ctx)
+        setServingStatus(hs, healthpb.HealthCheckResponse_SERVING)
+        s.startReadiness(hs)
+    }()
+}
+
+func setServingStatus(hs *health.Server, status healthpb.HealthCheckResponse_ServingStatus) {
+    for _, service := range healthServices {
+        hs.SetServingStatus(service, status)
+    }
+}
+
+// warmUp primes the toolchains the server is likely to run
+func (s *CCToolsServer) warmUp(ctx context.Context) {
+    start := time.Now()
+
+    programs := make(map[string]bool)
+    for _, program := range warmUpPrograms {
+        programs[program] = true
+    }
+    for _, root := range strings.Split(os.Getenv("WARMUP_PROJECTS"), ",") {
+        if root = strings.TrimSpace(root); root == "" {
+            continue
+        }
+        metadata, err := s.detectProjectMetadata(root)
+        if err != nil {
+            slog.Warn("warm-up: detection failed", "project_root", root, "error", err)
+            continue
+        }
+        annotateTooling(metadata)
+        for _, command := range metadata.Commands {
+            // Project-local programs have no directory to run from here,
+            // and config-supplied ones must pass the allowlist like any
+            // validator
+            fields := strings.Fields(command)
+            if len(fields) > 0 && !strings.ContainsRune(fields[0], '/') && s.allowedCommands.permits(fields[0], "") {
+                programs[fields[0]] = true
+            }
+        }
+    }
+
+    var wg sync.WaitGroup
+    for program := range programs {
+        if _, err := exec.LookPath(program); err != nil {
+            continue
+        }
+        wg.Add(1)
+        go func(program string) {
+            defer wg.Done()
+            cmd := exec.CommandContext(ctx, program, "--version")
+            setProcessGroup(cmd)
+            if err := cmd.Run(); err != nil && ctx.Err() == nil {
+                slog.Warn("warm-up: version check failed", "program", program, "error", err)
+            }
+        }(program)
+    }
+    wg.Wait()
+
+    if ctx.Err() != nil {
+        slog.Warn("warm-up: time budget exhausted; serving anyway", "dur_ms", time.Since(start).Milliseconds())
+        return
+    }
+    slog.Info("warm-up: primed programs", "programs", len(programs), "dur_ms", time.Since(start).Milliseconds())
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "regexp"
+)
+
+// build.zig.zon is a Zig struct literal; only the top-level string fields
+// the server needs are read. Since Zig 0.14 .name is an enum literal
+// (.name = .foo) rather than a string.
+var (
+    zonMinimumVersionPattern = regexp.MustCompile(`\.minimum_zig_version\s*=\s*"([^"]+)"`)
+    zonNamePattern           = regexp.MustCompile(`\.name\s*=\s*(?:"([^"]+)"|\.@?"?([A-Za-z_][A-Za-z0-9_]*))`)
+)
+
+// zigToolchain reads .minimum_zig_version from build.zig.zon
+func zigToolchain(dir string) (*toolchainRequirement, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "build.zig.zon"))
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    if err != nil {
+        return nil, err
+    }
+    m := zonMinimumVersionPattern.FindSubmatch(data)
+    if m == nil {
+        return nil, nil
+    }
+    return &toolchainRequirement{tool: "zig", source: "build.zig.zon minimum_zig_version", constraint: ">=" + string(m[1]), probe: []string{"zig", "version"}}, nil
+}
+
+// zigProjectName reads .name from build.zig.zon; projects without one are unnamed
+func zigProjectName(dir string) (string, []string, error) {
+    data, err := os.ReadFile(filepath.Join(dir, "build.zig.zon"))
+    if os.IsNotExist(err) {
+        return "", nil, nil
+    }
+    if err != nil {
+        return "", nil, err
+    }
+    m := zonNamePattern.FindSubmatch(data)
+    if m == nil {
+        return "", nil, nil
+    }
+    if len(m[1]) > 0 {
+        return string(m[1]), nil, nil
+    }
+    return string(m[2]), nil, nil
+}